@@ -5,12 +5,15 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/example/ybMigration/internal/analyzer"
+	"github.com/example/ybMigration/internal/checker"
 	"github.com/example/ybMigration/internal/config"
 	"github.com/example/ybMigration/internal/constants"
+	inputparser "github.com/example/ybMigration/internal/input-parser"
 	report "github.com/example/ybMigration/internal/report"
 	sqlparser "github.com/example/ybMigration/internal/sql-parser"
 )
@@ -49,21 +52,32 @@ func run(absConfigPath, absPath, absReportPath string) error {
 		return fmt.Errorf("创建SQL解析器失败")
 	}
 
-	// 使用分析器分析输入
-	result, err := analyzer.AnalyzeInput(absPath, sqlParser, checkers)
+	// 目录与归档输入：逐文件分析，转换结果按输入目录（归档内目录）结构输出
+	if info, err := os.Stat(absPath); err == nil && (info.IsDir() || inputparser.IsArchive(absPath)) {
+		return runDirectory(absPath, absReportPath, af, sqlParser, checkers)
+	}
+
+	// 逐条分析输入，转换后的SQL边分析边写入默认输出目录
+	transformedSQL := report.NewTransformedSQLFile(report.GenerateTransformedSQLPath(absPath, absReportPath))
+	result, err := analyzer.AnalyzeFile(absPath, transformedSQL, af.GetConfig().Input, sqlParser, checkers)
+	if closeErr := transformedSQL.Close(); closeErr != nil && err == nil {
+		return fmt.Errorf("保存转换SQL失败: %w", closeErr)
+	}
 	if err != nil {
 		return fmt.Errorf("分析输入失败: %w", err)
 	}
+	// 结果中不保留 SQL，报告中注明转换后的 SQL 所在的文件
+	if transformedSQL.Written() {
+		result.TransformedFile = transformedSQL.Path()
+	}
 
 	// 生成所有支持格式的报告
 	if err := report.GenerateReports(absReportPath, result, af.GetConfig(), checkers); err != nil {
 		return fmt.Errorf("生成报告失败: %w", err)
 	}
 
-	// 保存转换后的SQL到默认输出目录
-	transformedSQLPath := report.GenerateTransformedSQLPath(absPath, absReportPath)
-	if err := report.SaveTransformedSQL(result, transformedSQLPath); err != nil {
-		return fmt.Errorf("保存转换SQL失败: %w", err)
+	if !transformedSQL.Written() {
+		return fmt.Errorf("保存转换SQL失败: 没有转换后的SQL需要保存")
 	}
 
 	fmt.Println("分析完成！报告已生成")
@@ -71,6 +85,40 @@ func run(absConfigPath, absPath, absReportPath string) error {
 	return nil
 }
 
+// runDirectory 分析目录或归档输入
+// 每个文件保留独立的分析结果，报告由所有文件结果合并生成，
+// 转换后的 SQL 边分析边写入报告目录下与输入目录结构一致的位置。
+func runDirectory(absPath, absReportPath string, af *analyzer.Factory, sqlParser sqlparser.SQLParser, checkers []checker.Checker) error {
+	tree := report.NewTransformedSQLTree(absPath, absReportPath)
+	outputs := make(map[string]*report.TransformedSQLFile)
+	results, err := analyzer.AnalyzeDirectoryStream(absPath, func(path string) (io.WriteCloser, error) {
+		file, err := tree.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		outputs[path] = file
+		return file, nil
+	}, af.GetConfig().Input, sqlParser, checkers)
+	if err != nil {
+		return fmt.Errorf("分析目录失败: %w", err)
+	}
+	for i := range results {
+		if file, ok := outputs[results[i].Source]; ok && file.Written() {
+			results[i].TransformedFile = file.Path()
+		}
+	}
+
+	if err := report.GenerateReportsFromMultiple(absReportPath, results, af.GetConfig(), checkers); err != nil {
+		return fmt.Errorf("生成报告失败: %w", err)
+	}
+
+	saved := tree.Saved()
+
+	fmt.Printf("分析完成！共分析 %d 个文件，保存 %d 个转换文件，报告已生成\n", len(results), len(saved))
+
+	return nil
+}
+
 func main() {
 	absConfigPath, absPath, absReportPath := parseFlags()
 
@@ -86,7 +134,7 @@ func parseFlags() (absConfigPath, absPath, absReportPath string) {
 
 	// 定义命令行参数
 	flag.StringVar(&configPath, "config", "", "配置文件路径（YAML）。若未指定，则自动查找默认位置。")
-	flag.StringVar(&path, "path", "", "待分析的SQL文件、日志文件或目录路径（可选，若未提供则从 args[0] 参数读取）。")
+	flag.StringVar(&path, "path", "", "待分析的SQL文件、日志文件、目录或归档（.tar.gz、.zip 等）路径（可选，若未提供则从 args[0] 参数读取）。")
 	flag.StringVar(&reportPath, "reportPath", "", "分析报告输出目录。若未指定，默认为 ./output-report。")
 
 	// 自定义帮助信息
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/example/ybMigration/internal/analyzer"
+	"github.com/example/ybMigration/internal/config"
 	"github.com/example/ybMigration/internal/report"
 	sqlparser "github.com/example/ybMigration/internal/sql-parser"
 	"github.com/example/ybMigration/internal/testutils"
@@ -45,7 +46,7 @@ func TestMain_Integration_ValidSQLFile(t *testing.T) {
 	require.NoError(t, err)
 	sqlParser := sqlparser.NewSQLParser()
 	require.NotNil(t, sqlParser)
-	result, err := analyzer.AnalyzeInput(sqlPath, sqlParser, checkers)
+	result, err := analyzer.AnalyzeInput(sqlPath, config.InputConfig{}, sqlParser, checkers)
 	require.NoError(t, err)
 	err = report.GenerateReports(reportPath, result, af.GetConfig(), checkers)
 	require.NoError(t, err)
@@ -130,7 +131,7 @@ func TestMain_Integration_LogFile(t *testing.T) {
 	require.NoError(t, err)
 	sqlParser := sqlparser.NewSQLParser()
 	require.NotNil(t, sqlParser)
-	result, err := analyzer.AnalyzeInput(logPath, sqlParser, checkers)
+	result, err := analyzer.AnalyzeInput(logPath, config.InputConfig{}, sqlParser, checkers)
 	require.NoError(t, err)
 	err = report.GenerateReports(reportPath, result, af.GetConfig(), checkers)
 	require.NoError(t, err)
@@ -194,9 +195,9 @@ func TestMain_Integration_Directory(t *testing.T) {
 	require.NoError(t, err)
 	sqlParser := sqlparser.NewSQLParser()
 	require.NotNil(t, sqlParser)
-	result, err := analyzer.AnalyzeInput(testDir, sqlParser, checkers)
+	dirResults, err := analyzer.AnalyzeDirectory(testDir, config.InputConfig{}, sqlParser, checkers)
 	require.NoError(t, err)
-	err = report.GenerateReports(reportPath, result, af.GetConfig(), checkers)
+	err = report.GenerateReportsFromMultiple(reportPath, dirResults, af.GetConfig(), checkers)
 	require.NoError(t, err)
 
 	// 验证报告文件生成
@@ -211,9 +212,10 @@ func TestMain_Integration_Directory(t *testing.T) {
 	err = json.Unmarshal(data, &summary)
 	require.NoError(t, err, "summary.json 应为有效 JSON")
 
-	// 验证从目录中分析到的问题
+	// 验证从目录中分析到的问题，每个文件一个结果
 	results, ok := summary["results"].([]interface{})
 	require.True(t, ok, "results 应为数组类型")
+	assert.Len(t, results, len(dirResults))
 	var allIssues []interface{}
 	for _, result := range results {
 		resultMap, ok := result.(map[string]interface{})
@@ -227,6 +229,80 @@ func TestMain_Integration_Directory(t *testing.T) {
 	t.Logf("目录分析报告生成在: %s", reportPath)
 }
 
+// TestMain_Integration_RunDirectory 测试 run 处理目录输入：逐文件结果、合并报告、镜像输出
+func TestMain_Integration_RunDirectory(t *testing.T) {
+	inputDir := t.TempDir()
+	reportPath := t.TempDir()
+
+	files := map[string]string{
+		"orders.sql":          "SELECT IFNULL(name, 'N/A') FROM orders",
+		"nested/users.sql":    "CREATE TABLE users (id INT AUTO_INCREMENT PRIMARY KEY, flag TINYINT)",
+		"nested/deep/log.sql": "UPDATE users SET flag = 1 WHERE id = 2",
+	}
+	for name, content := range files {
+		path := filepath.Join(inputDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	configPath := testutils.MustGetTestDataPath("../configs/default.yaml")
+	err := run(configPath, inputDir, reportPath)
+	require.NoError(t, err, "目录输入不应报错")
+
+	// 转换后的 SQL 按输入目录结构输出
+	assert.FileExists(t, filepath.Join(reportPath, "orders_transformed.sql"))
+	assert.FileExists(t, filepath.Join(reportPath, "nested", "users_transformed.sql"))
+	assert.FileExists(t, filepath.Join(reportPath, "nested", "deep", "log_transformed.sql"))
+
+	// 报告中每个文件一个结果
+	data, err := os.ReadFile(filepath.Join(reportPath, "summary.json")) //nolint:gosec
+	require.NoError(t, err, "应能读取 summary.json")
+
+	var summary map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &summary))
+	assert.EqualValues(t, len(files), summary["total_analyses"])
+	results, ok := summary["results"].([]interface{})
+	require.True(t, ok, "results 应为数组类型")
+	assert.Len(t, results, len(files))
+
+	// 结果中不输出空的 sql 字段，转换后的 SQL 以文件路径给出
+	for _, result := range results {
+		resultMap, ok := result.(map[string]interface{})
+		require.True(t, ok, "result 应为 map[string]interface{} 类型")
+		assert.NotContains(t, resultMap, "sql")
+		transformedFile, ok := resultMap["transformed_file"].(string)
+		require.True(t, ok, "transformed_file 应为字符串类型")
+		assert.FileExists(t, transformedFile)
+	}
+}
+
+// TestMain_Integration_RunFile 测试 run 处理单个文件：转换后的 SQL 流式写入文件，报告注明其位置
+func TestMain_Integration_RunFile(t *testing.T) {
+	reportPath := t.TempDir()
+	sqlPath := filepath.Join(t.TempDir(), "orders.sql")
+	require.NoError(t, os.WriteFile(sqlPath, []byte("SELECT IFNULL(name, 'N/A') FROM orders"), 0600))
+
+	configPath := testutils.MustGetTestDataPath("../configs/default.yaml")
+	require.NoError(t, run(configPath, sqlPath, reportPath))
+
+	data, err := os.ReadFile(filepath.Join(reportPath, "summary.json")) //nolint:gosec
+	require.NoError(t, err, "应能读取 summary.json")
+
+	var summary map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &summary))
+	results, ok := summary["results"].([]interface{})
+	require.True(t, ok, "results 应为数组类型")
+	require.Len(t, results, 1)
+	result, ok := results[0].(map[string]interface{})
+	require.True(t, ok, "result 应为 map[string]interface{} 类型")
+	assert.NotContains(t, result, "sql")
+	assert.Equal(t, filepath.Join(reportPath, "orders_transformed.sql"), result["transformed_file"])
+
+	markdown, err := os.ReadFile(filepath.Join(reportPath, "summary.md")) //nolint:gosec
+	require.NoError(t, err, "应能读取 summary.md")
+	assert.Contains(t, string(markdown), "orders_transformed.sql")
+}
+
 // TestMain_Integration_MultipleReportFormats 测试多种报告格式生成
 func TestMain_Integration_MultipleReportFormats(t *testing.T) {
 	// 创建输出目录 - 放在 yb-migration/output-report/formats 下
@@ -252,7 +328,7 @@ func TestMain_Integration_MultipleReportFormats(t *testing.T) {
 	require.NoError(t, err)
 	sqlParser := sqlparser.NewSQLParser()
 	require.NotNil(t, sqlParser)
-	result, err := analyzer.AnalyzeInput(sqlPath, sqlParser, checkers)
+	result, err := analyzer.AnalyzeInput(sqlPath, config.InputConfig{}, sqlParser, checkers)
 	require.NoError(t, err)
 	err = report.GenerateReports(reportPath, result, af.GetConfig(), checkers)
 	require.NoError(t, err)
@@ -3,8 +3,10 @@ module github.com/example/ybMigration
 go 1.25.1
 
 require (
+	github.com/klauspost/compress v1.18.0
 	github.com/pingcap/tidb/pkg/parser v0.0.0-20251219040447-0eb881e406a4
 	github.com/stretchr/testify v1.8.4
+	golang.org/x/text v0.21.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -33,7 +35,6 @@ require (
 	go.uber.org/zap v1.27.0 // indirect
 	golang.org/x/mod v0.17.0 // indirect
 	golang.org/x/net v0.34.0 // indirect
-	golang.org/x/text v0.21.0 // indirect
 	golang.org/x/tools v0.21.1-0.20240508182429-e35e4ccd0d2d // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
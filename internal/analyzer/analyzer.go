@@ -3,6 +3,8 @@
 package analyzer
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -10,12 +12,12 @@ import (
 	"strings"
 
 	"github.com/pingcap/tidb/pkg/parser/ast"
-	"github.com/pingcap/tidb/pkg/parser/format"
 
 	"github.com/example/ybMigration/internal/checker"
 	"github.com/example/ybMigration/internal/config"
 	inputparser "github.com/example/ybMigration/internal/input-parser"
 	"github.com/example/ybMigration/internal/model"
+	sqlemitter "github.com/example/ybMigration/internal/sql-emitter"
 	sqlparser "github.com/example/ybMigration/internal/sql-parser"
 )
 
@@ -51,9 +53,11 @@ func NewSQLAnalyzer(inputParser inputparser.InputParser, sqlParser sqlparser.SQL
 //   - error: 如果发生错误，返回具体的 AnalysisError
 //
 // 错误处理策略:
-//   - SQL 解析失败：返回 ErrorTypeParse 类型的 AnalysisError
+//   - 部分语句解析失败：记录为带行列号的 SQLParser 问题，其余语句照常检查和转换
+//   - 所有语句均解析失败：返回第一条失败语句的 ErrorTypeParse 类型 AnalysisError
 //   - 未找到有效 SQL：返回 ErrorTypeNoSQL 类型的 AnalysisError
-//   - 生成转换 SQL 失败：返回 ErrorTypeTransform 类型的 AnalysisError
+//   - 单条语句生成转换 SQL 失败：记录为 SQLEmitter 问题，该语句输出为注释
+//   - 相同指纹的语句产生的相同问题只保留首次出现的位置，Executions 为出现次数
 //   - 分析过程中的兼容性问题：记录在 result.Issues 中，不返回 error
 //
 // 使用示例：
@@ -67,137 +71,117 @@ func NewSQLAnalyzer(inputParser inputparser.InputParser, sqlParser sqlparser.SQL
 //				fmt.Printf("SQL 解析失败: %v\n", analysisErr)
 //			case ErrorTypeNoSQL:
 //				fmt.Printf("未找到有效 SQL: %v\n", analysisErr)
-//			case ErrorTypeTransform:
-//				fmt.Printf("SQL 转换失败: %v\n", analysisErr)
 //			}
 //		}
 //		return
 //	}
 func (a *SQLAnalyzer) AnalyzeSQL(sql string, source string) (model.AnalysisResult, error) {
-	// 解析 SQL 语句
-	stmts, err := a.sqlParser.ParseSQL(sql)
-	if err != nil {
-		return model.AnalysisResult{
-				SQL:    sql,
-				Source: source,
-			}, &model.AnalysisError{
-				Type:    model.ErrorTypeParse,
-				Message: "SQL 解析失败",
-				Source:  source,
-				SQL:     sql,
-				Cause:   err,
-			}
-	}
+	// 逐条解析 SQL 语句，单条语句解析失败不影响其余语句
+	var transformed strings.Builder
+	result, err := a.AnalyzeStream(strings.NewReader(sql), source, &transformed)
+	return captureResult(result, err, sql, transformed.String())
+}
 
-	if len(stmts) == 0 {
-		return model.AnalysisResult{
-				SQL:    sql,
-				Source: source,
-			}, &model.AnalysisError{
-				Type:    model.ErrorTypeNoSQL,
-				Message: "未找到有效的 SQL 语句",
-				Source:  source,
-				SQL:     sql,
+// AnalyzeSegments 分析由输入解析器提取的 SQL 片段
+// 片段先按语句指纹分组，每个指纹只分析首次出现的片段，然后将问题的行号换算回原始输入，
+// 并附加问题所在片段的元数据（如慢查询日志中的执行耗时）、指纹和累计执行次数。
+// 参数:
+//   - segments: SQL 片段列表
+//   - source: SQL 来源标识（文件路径等）
+//
+// 返回值:
+//   - model.AnalysisResult: 分析结果，SQL 为每个指纹首次出现的片段拼接后的文本
+//   - error: 与 AnalyzeSQL 相同
+func (a *SQLAnalyzer) AnalyzeSegments(segments []inputparser.Segment, source string) (model.AnalysisResult, error) {
+	var sql, transformed strings.Builder
+	result, err := a.analyzeSegmentStream(func(fn func(inputparser.Segment) error) error {
+		for _, seg := range segments {
+			if err := fn(seg); err != nil {
+				return err
 			}
+		}
+		return nil
+	}, source, &transformed, &sql)
+	return captureResult(result, err, sql.String(), transformed.String())
+}
+
+// rebasePosition 将语句内的行列号换算为原始文本中的行列号
+func rebasePosition(stmt sqlparser.Statement, line, column int) (int, int) {
+	if line == 1 {
+		column += stmt.Column - 1
 	}
+	return line + stmt.Line - 1, column
+}
 
-	// 使用 checker.Check 进行一次遍历完成分析和转换
-	checkResult := checker.Check(stmts, a.checkers...)
+// locateIssues 将问题的语句序号与行列号换算为原始输入中的位置
+// 检查器和生成器按解析成功的语句编号，行列号相对于语句文本；
+// 没有行号的问题（如生成器产生的问题）使用语句的起始位置。
+// 参数:
+//   - issues: 问题列表，原地修改
+//   - origins: 与解析成功的语句一一对应的来源位置
+func locateIssues(issues []model.Issue, origins []stmtOrigin) {
+	for i := range issues {
+		issue := &issues[i]
+		if issue.StatementIndex < 1 || issue.StatementIndex > len(origins) {
+			continue
+		}
+		origin := origins[issue.StatementIndex-1]
+		issue.StatementIndex = origin.index
 
-	// 生成转换后的SQL
-	transformedSQL, err := a.generateSQL(checkResult.TransformedStmts)
-	if err != nil {
-		return model.AnalysisResult{
-				SQL:    sql,
-				Source: source,
-				Issues: checkResult.Issues,
-			}, &model.AnalysisError{
-				Type:    model.ErrorTypeTransform,
-				Message: "生成转换SQL失败",
-				Source:  source,
-				SQL:     sql,
-				Cause:   err,
+		if issue.Line == 0 {
+			issue.Line, issue.Column = origin.stmt.Line, origin.stmt.Column
+			if issue.Snippet == "" {
+				issue.Snippet = sqlparser.Snippet(origin.stmt.Text, 0, sqlparser.ClauseEnd(origin.stmt.Text, 0))
 			}
+			continue
+		}
+		issue.Line, issue.Column = rebasePosition(origin.stmt, issue.Line, issue.Column)
 	}
-
-	return model.AnalysisResult{
-		SQL:            sql,
-		Source:         source,
-		Issues:         checkResult.Issues,
-		TransformedSQL: transformedSQL,
-	}, nil
 }
 
-// generateSQL 从AST节点生成SQL字符串（使用TiDB的Restore功能）
+// generateSQL 从AST节点生成 YSQL 字符串
 // 参数:
 //   - stmts: AST语句节点列表
+//   - hints: 检查器提供的输出提示，可以为 nil
 //
 // 返回值:
 //   - string: 生成的SQL字符串
+//   - []model.Issue: 无法输出为 YSQL 的节点
 //   - error: 生成过程中的错误
 //
 // 实现细节:
-//  1. 使用TiDB的Restore API将AST转换回SQL
-//  2. 使用strings.Builder提高字符串拼接性能
-//  3. 添加RestoreStringWithoutCharset标志去除字符集前缀
-//  4. 预分配slice容量避免多次扩容
+//  1. 使用 sqlemitter 将 AST 输出为 PostgreSQL/YSQL 语法，而不是 TiDB Restore 的 MySQL 语法
+//  2. 无法输出的节点以注释占位，并记录为 SQLEmitter 问题
 //
 // 注意事项:
 //   - 空语句列表返回空字符串
 //   - nil语句会被跳过
 //   - 语句间用分号和换行符分隔
-func (a *SQLAnalyzer) generateSQL(stmts []ast.StmtNode) (string, error) {
+func (a *SQLAnalyzer) generateSQL(stmts []ast.StmtNode, hints *sqlemitter.Hints) (string, []model.Issue, error) {
 	if len(stmts) == 0 {
-		return "", nil
+		return "", nil, nil
 	}
 
-	// 使用TiDB的Restore API生成SQL
-	// 预分配容量，提高性能
-	sqlParts := make([]string, 0, len(stmts))
-	for _, stmt := range stmts {
-		if stmt != nil {
-			// 创建strings.Builder作为RestoreWriter，避免字符串拼接性能问题
-			var builder strings.Builder
-
-			// 创建RestoreCtx，配置恢复标志
-			// DefaultRestoreFlags = RestoreStringSingleQuotes | RestoreKeyWordUppercase | RestoreNameBackQuotes
-			// 添加 RestoreStringWithoutCharset 标志来去除 _UTF8MB4 等字符集前缀
-			// 移除 RestoreNameBackQuotes 标志，避免自动添加反引号
-			flags := format.RestoreStringSingleQuotes | format.RestoreKeyWordUppercase | format.RestoreStringWithoutCharset
-			ctx := format.NewRestoreCtx(flags, &builder)
-
-			// 调用TiDB的Restore方法生成SQL
-			// 这里可能会因为AST节点损坏而失败，需要错误处理
-			err := stmt.Restore(ctx)
-			if err != nil {
-				// Restore失败时返回错误，不使用panic
-				return "", fmt.Errorf("TiDB Restore失败: %w", err)
-			}
-
-			sqlStr := builder.String()
-			if sqlStr != "" {
-				sqlParts = append(sqlParts, sqlStr)
-			}
-		}
+	sql, issues, err := sqlemitter.NewEmitter().Emit(stmts, hints)
+	if err != nil {
+		return "", issues, fmt.Errorf("YSQL 生成失败: %w", err)
 	}
-
-	// 用分号和换行符连接多个SQL语句
-	return strings.Join(sqlParts, ";\n"), nil
+	return sql, issues, nil
 }
 
 // isSupportedFileExt 检查文件扩展名是否受支持
 // 参数:
-//   - ext: 文件扩展名（包含点号，如 ".sql"）
+//   - ext: 文件扩展名（包含点号，如 ".sql"），压缩文件与归档成员应传入 inputparser.InputExt 的结果
 //
 // 返回值:
 //   - bool: true表示支持，false表示不支持
 //
 // 说明:
 //
-//	支持的文件类型: .sql, .log
+//	支持的文件类型: .sql, .log, .xml（MyBatis mapper）, .csv 与 .json（performance_schema 语句摘要导出）
 func isSupportedFileExt(ext string) bool {
 	switch strings.ToLower(ext) {
-	case ".sql", ".log":
+	case ".sql", ".log", ".xml", ".csv", ".json":
 		return true
 	default:
 		return false
@@ -216,15 +200,76 @@ func isSupportedFileExt(ext string) bool {
 //
 //	.sql 文件 -> SQLFileParser
 //	.log 文件 -> GeneralLogFileParser
+//	.xml 文件 -> MapperFileParser
+//	.csv、.json 文件 -> DigestFileParser
 func newFileParserForExt(ext string) (inputparser.InputParser, error) {
 	switch strings.ToLower(ext) {
 	case ".sql":
 		return inputparser.NewSQLFileParser(), nil
 	case ".log":
 		return inputparser.NewGeneralLogFileParser(), nil
+	case ".xml":
+		return inputparser.NewMapperFileParser(), nil
+	case ".csv", ".json":
+		return inputparser.NewDigestFileParser(), nil
 	default:
-		return nil, fmt.Errorf("不支持的文件类型: %s，仅支持 .sql 和 .log 文件", ext)
+		return nil, fmt.Errorf("不支持的文件类型: %s，仅支持 .sql、.log、MyBatis mapper .xml 和语句摘要导出 .csv、.json 文件", ext)
+	}
+}
+
+// sniffBytes 识别输入格式时预读的字节数
+const sniffBytes = 64 * 1024
+
+// newFileParser 根据文件路径和开头的内容创建对应的解析器
+// 参数:
+//   - filePath: 文件路径或归档成员的虚拟路径
+//   - head: 输入开头的内容，用于识别日志与导出格式
+//   - input: 输入解析配置
+//
+// 返回值:
+//   - inputparser.InputParser: 对应的输入解析器实例
+//   - error: 不支持的文件类型或配置无效时返回错误
+//
+// 说明:
+//
+//	扩展名按 inputparser.InputExt 判断，dump.sql.gz、general.log.1.gz 分别视为 .sql 和 .log；
+//	.sql 和 .log 文件包含 mysqlbinlog 事件头部时使用 BinlogFileParser；
+//	.log 文件根据内容区分慢查询日志和 general log，
+//	包含 # Query_time 或 # User@Host 头部的使用 SlowLogFileParser；
+//	.sql 文件按 input.mysqldump 配置决定是否使用 MysqldumpFileParser，
+//	auto 模式下以 "-- MySQL dump" 文件头识别；
+//	.xml 文件必须以 <mapper> 为根元素；
+//	.csv、.json 文件必须是包含 DIGEST_TEXT 列的 performance_schema 语句摘要导出
+func newFileParser(filePath string, head []byte, input config.InputConfig) (inputparser.InputParser, error) {
+	ext := inputparser.InputExt(filePath)
+	switch {
+	case (ext == ".sql" || ext == ".log") && inputparser.IsBinlog(bytes.NewReader(head)):
+		return inputparser.NewBinlogFileParser(), nil
+	case ext == ".log" && inputparser.IsSlowLog(bytes.NewReader(head)):
+		return inputparser.NewSlowLogFileParser(), nil
+	case ext == ".sql" && isMysqldump(head, input.Mysqldump):
+		version, err := input.MysqlVersion()
+		if err != nil {
+			return nil, fmt.Errorf("输入配置无效: %w", err)
+		}
+		return inputparser.NewMysqldumpFileParser(version), nil
+	case ext == ".xml" && !inputparser.IsMapper(bytes.NewReader(head)):
+		return nil, fmt.Errorf("不是 MyBatis mapper 文件: 根元素不是 <mapper>")
+	case (ext == ".csv" || ext == ".json") && !inputparser.IsDigestExport(bytes.NewReader(head)):
+		return nil, fmt.Errorf("不是 performance_schema 语句摘要导出文件: 缺少 DIGEST_TEXT 列")
+	}
+	return newFileParserForExt(ext)
+}
+
+// isMysqldump 根据预处理模式判断 .sql 输入是否按 mysqldump 输出处理
+func isMysqldump(head []byte, mode string) bool {
+	switch strings.ToLower(mode) {
+	case config.DumpModeOn:
+		return true
+	case config.DumpModeOff:
+		return false
 	}
+	return inputparser.IsMysqldump(bytes.NewReader(head))
 }
 
 // ============================================================================
@@ -287,6 +332,36 @@ func (f *Factory) CreateCheckers(categories ...string) ([]checker.Checker, error
 				return nil, fmt.Errorf("创建字符集检查器失败: %w", err)
 			}
 			checkers = append(checkers, charsetChecker)
+		case "dml":
+			dmlChecker, err := checker.NewDMLChecker(f.config)
+			if err != nil {
+				return nil, fmt.Errorf("创建DML检查器失败: %w", err)
+			}
+			checkers = append(checkers, dmlChecker)
+		case "multitable":
+			multiTableChecker, err := checker.NewMultiTableChecker(f.config)
+			if err != nil {
+				return nil, fmt.Errorf("创建多表DML检查器失败: %w", err)
+			}
+			checkers = append(checkers, multiTableChecker)
+		case "trigger":
+			triggerChecker, err := checker.NewTriggerChecker(f.config)
+			if err != nil {
+				return nil, fmt.Errorf("创建触发器检查器失败: %w", err)
+			}
+			checkers = append(checkers, triggerChecker)
+		case "index":
+			indexChecker, err := checker.NewIndexChecker(f.config)
+			if err != nil {
+				return nil, fmt.Errorf("创建索引检查器失败: %w", err)
+			}
+			checkers = append(checkers, indexChecker)
+		case "sharding":
+			shardingChecker, err := checker.NewShardingChecker(f.config)
+			if err != nil {
+				return nil, fmt.Errorf("创建分片检查器失败: %w", err)
+			}
+			checkers = append(checkers, shardingChecker)
 		default:
 			return nil, fmt.Errorf("不支持的检查器类别: %s", category)
 		}
@@ -331,10 +406,29 @@ func (f *Factory) GetConfig() *config.Config {
 // 便捷分析函数
 // ============================================================================
 
+// AnalyzeFile 流式分析单个文件，转换后的 SQL 逐条写入 out。
+// 与 analyzeFile 不同，结果中不保留原始 SQL 与转换后的 SQL，内存占用与文件大小无关，
+// 适合分析多 GB 的 mysqldump 文件和长时间积累的日志。
+// 参数:
+//   - filePath: 文件路径，必须是有效的文件路径
+//   - out: 转换后 SQL 的输出位置，为 nil 时丢弃
+//   - input: 输入解析配置，如 mysqldump 预处理模式和服务器版本
+//   - sqlParser: SQL 解析器实例，用于将 SQL 文本解析为 AST
+//   - checkers: 检查器列表，用于检测兼容性问题和执行转换
+//
+// 返回:
+//   - model.AnalysisResult: 分析结果，包含发现的问题
+//   - error: 与 analyzeFile 相同
+func AnalyzeFile(filePath string, out io.Writer, input config.InputConfig, sqlParser sqlparser.SQLParser, checkers []checker.Checker) (model.AnalysisResult, error) {
+	return analyzeFileTo(filePath, out, nil, input, sqlParser, checkers)
+}
+
 // analyzeFile 分析单个文件（私有函数）。
-// 根据 input-parser 包的设计，仅支持 .sql 和 .log 文件类型。
+// 根据 input-parser 包的设计，仅支持 .sql、.log、MyBatis mapper .xml 和语句摘要导出 .csv、.json 文件类型，
+// 包括 .gz、.zst 压缩文件和以虚拟路径（如 archive.tar.gz!/db/users.sql）表示的归档成员。
 // 参数:
 //   - filePath: 文件路径，必须是有效的文件路径
+//   - input: 输入解析配置，如 mysqldump 预处理模式、服务器版本和输入编码
 //   - sqlParser: SQL 解析器实例，用于将 SQL 文本解析为 AST
 //   - checkers: 检查器列表，用于检测兼容性问题和执行转换
 //
@@ -342,67 +436,257 @@ func (f *Factory) GetConfig() *config.Config {
 //   - model.AnalysisResult: 分析结果，包含原始 SQL、发现的问题和转换后的 SQL
 //   - error: 如果文件读取失败、创建分析器失败或 SQL 解析失败，返回错误
 //
+// 原始 SQL 与转换后的 SQL 完整保存在内存中，内存占用与文件大小成正比；
+// 流式分析请使用 AnalyzeFile。
+//
 // 文件类型识别规则:
 //   - .sql: 使用 SQL 文件解析器
 //   - .log: 使用日志文件解析器
+//   - .xml: 使用 MyBatis mapper 解析器
+//   - .csv、.json: 使用语句摘要导出解析器
 //   - 其他扩展名: 返回错误，不支持
-func analyzeFile(filePath string, sqlParser sqlparser.SQLParser, checkers []checker.Checker) (model.AnalysisResult, error) {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	inputParser, err := newFileParserForExt(ext)
-	if err != nil {
+func analyzeFile(filePath string, input config.InputConfig, sqlParser sqlparser.SQLParser, checkers []checker.Checker) (model.AnalysisResult, error) {
+	var sql, transformed strings.Builder
+	result, err := analyzeFileTo(filePath, &transformed, &sql, input, sqlParser, checkers)
+	return captureResult(result, err, sql.String(), transformed.String())
+}
+
+// analyzeFileTo 流式分析单个文件
+// 转换后的 SQL 写入 out；echo 不为 nil 时写入实际分析的原始 SQL。
+func analyzeFileTo(filePath string, out, echo io.Writer, input config.InputConfig, sqlParser sqlparser.SQLParser, checkers []checker.Checker) (model.AnalysisResult, error) {
+	if _, err := newFileParserForExt(inputparser.InputExt(filePath)); err != nil {
 		return model.AnalysisResult{Source: filePath}, err
 	}
 
+	// 压缩文件边读边解压，归档成员从归档中直接读取
+	file, err := inputparser.OpenFile(filePath)
+	if err != nil {
+		return model.AnalysisResult{
+			Source: filePath,
+		}, fmt.Errorf("解析输入失败: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "关闭文件 %s 失败: %v\n", filePath, err)
+		}
+	}()
+
+	return analyzeReaderTo(filePath, file, out, echo, input, sqlParser, checkers)
+}
+
+// analyzeMember 分析已打开的输入（私有函数），结果中保留原始 SQL 与转换后的 SQL
+// 用于 AnalyzeDirectory 遍历的文件和归档成员，内存占用与文件大小成正比。
+func analyzeMember(name string, r io.Reader, input config.InputConfig, sqlParser sqlparser.SQLParser, checkers []checker.Checker) (model.AnalysisResult, error) {
+	var sql, transformed strings.Builder
+	result, err := analyzeReaderTo(name, r, &transformed, &sql, input, sqlParser, checkers)
+	return captureResult(result, err, sql.String(), transformed.String())
+}
+
+// analyzeReaderTo 流式分析已打开的输入
+// name 为文件路径或归档成员的虚拟路径，用于选择解析器和标记问题所在的文件；
+// 输入先按 input.Encoding 转换为 UTF-8，结果记录识别出的编码，无法解码的字节序列作为问题报告；
+// 解析器根据预读的开头内容选择，预读的内容仍会交给解析器。
+func analyzeReaderTo(name string, r io.Reader, out, echo io.Writer, input config.InputConfig, sqlParser sqlparser.SQLParser, checkers []checker.Checker) (model.AnalysisResult, error) {
+	decoder, err := inputparser.NewDecodeReader(r, input.Encoding)
+	if err != nil {
+		return model.AnalysisResult{Source: name}, fmt.Errorf("输入配置无效: %w", err)
+	}
+	reader := bufio.NewReaderSize(decoder, sniffBytes)
+	head, _ := reader.Peek(sniffBytes)
+
+	inputParser, err := newFileParser(name, head, input)
+	if err != nil {
+		return model.AnalysisResult{Source: name, Encoding: decoder.Encoding()}, err
+	}
+
 	// 使用对应的 inputParser 创建分析器
 	analyzer, err := NewSQLAnalyzer(inputParser, sqlParser, checkers)
 	if err != nil {
 		return model.AnalysisResult{}, fmt.Errorf("创建分析器失败: %w", err)
 	}
 
-	// 直接调用 inputParser.Parse() 和 AnalyzeSQL()
-	content, err := inputParser.Parse(filePath)
-	if err != nil {
-		return model.AnalysisResult{
-			Source: filePath,
-		}, fmt.Errorf("解析输入失败: %w", err)
+	var result model.AnalysisResult
+	switch p := inputParser.(type) {
+	case inputparser.SegmentStreamer:
+		// 日志类输入按片段分析，问题位置与元数据对应回原始日志
+		result, err = analyzer.analyzeSegmentStream(func(fn func(inputparser.Segment) error) error {
+			return p.ReadSegments(reader, fn)
+		}, name, out, echo)
+
+	case inputparser.StatementStreamer:
+		// mysqldump 等需要预处理的输入逐条分析，问题附带语句所在的导出分段
+		result, err = analyzer.analyzeStatementStream(func(fn func(sqlparser.Statement, map[string]string) error) error {
+			return p.ReadStatements(reader, fn)
+		}, name, out, echo)
+
+	default:
+		// SQL 文件逐条读取语句，不一次性载入内存
+		var src io.Reader = reader
+		if echo != nil {
+			src = io.TeeReader(reader, echo)
+		}
+		result, err = analyzer.AnalyzeStream(src, name, out)
 	}
 
-	result, err := analyzer.AnalyzeSQL(content, filePath)
+	result.Encoding = decoder.Encoding()
+	result.Issues = append(result.Issues, encodingIssues(decoder)...)
 	if result.Issues == nil {
 		result.Issues = []model.Issue{}
 	}
+	for i := range result.Issues {
+		result.Issues[i].File = name
+	}
 	return result, err
 }
 
-// analyzeDirectory 分析目录中的所有 SQL 相关文件（私有函数）。
-// 递归遍历目录，分析所有 .sql、.log文件，并汇总所有发现的问题。
+// encodingIssues 将输入中无法解码的字节序列转换为问题，每行一个
+func encodingIssues(decoder *inputparser.DecodeReader) []model.Issue {
+	invalid, dropped := decoder.Invalid()
+	var issues []model.Issue
+	for _, seq := range invalid {
+		issues = append(issues, model.Issue{
+			Checker: "Encoding",
+			Message: fmt.Sprintf("第 %d 行有 %d 处字节序列无法按 %s 编码解码，已替换为 U+FFFD", seq.Line, seq.Count, decoder.Encoding()),
+			Line:    seq.Line,
+			Meta: map[string]string{
+				inputparser.EncodingMetaName:   decoder.Encoding(),
+				inputparser.EncodingMetaSource: decoder.Source(),
+			},
+		})
+	}
+	if dropped > 0 {
+		issues = append(issues, model.Issue{
+			Checker: "Encoding",
+			Message: fmt.Sprintf("另有 %d 行包含无法按 %s 编码解码的字节序列，请检查 input.encoding 配置", dropped, decoder.Encoding()),
+		})
+	}
+	return issues
+}
+
+// AnalyzeDirectory 分析目录中的所有 SQL 相关文件，并为每个文件保留独立的分析结果。
+// 递归遍历目录，按文件路径顺序分析所有 .sql、.log、MyBatis mapper .xml、语句摘要导出 .csv、.json 文件
+// 及其 .gz、.zst 压缩文件；
+// .tar、.tar.gz、.tgz、.tar.zst、.zip 归档中的成员按归档内的顺序逐个分析，
+// Source 为虚拟路径，如 archive.tar.gz!/db/users.sql。
+// 参数:
+//   - dirPath: 目录路径，必须是有效的目录路径或归档文件
+//   - input: 输入解析配置，对目录中的每个文件生效
+//   - sqlParser: SQL 解析器实例，用于将 SQL 文本解析为 AST
+//   - checkers: 检查器列表，用于检测兼容性问题和执行转换
+//
+// 返回:
+//   - []model.AnalysisResult: 每个文件一个分析结果，Source 为文件路径
+//   - error: 如果目录访问失败或遍历目录时出错，返回错误
+//
+// 注意:
+//   - 每个文件的原始 SQL 与转换后的 SQL 完整保存在结果中，内存占用与目录中所有文件的总大小成正比，
+//     分析大型目录或归档时请使用 AnalyzeDirectoryStream
+//   - 单个文件分析失败不会中断整个目录遍历，错误会记录到该文件结果的 issues 中
+//   - 支持的文件类型：.sql（SQL 文件）、.log（日志文件）、.xml（MyBatis mapper，其他 XML 文件跳过）、
+//     .csv 和 .json（performance_schema 语句摘要导出，其他 CSV、JSON 文件跳过），
+//     压缩与归档不会产生临时文件
+func AnalyzeDirectory(dirPath string, input config.InputConfig, sqlParser sqlparser.SQLParser, checkers []checker.Checker) ([]model.AnalysisResult, error) {
+	return walkDirectory(dirPath, func(path string, r io.Reader) (model.AnalysisResult, error) {
+		return analyzeMember(path, r, input, sqlParser, checkers)
+	})
+}
+
+// AnalyzeDirectoryStream 流式分析目录中的所有 SQL 相关文件，转换后的 SQL 逐条写入各文件对应的输出。
+// 文件遍历规则与 AnalyzeDirectory 相同，结果中不保留原始 SQL 与转换后的 SQL。
 // 参数:
-//   - dirPath: 目录路径，必须是有效的目录路径
+//   - dirPath: 目录路径，必须是有效的目录路径或归档文件
+//   - output: 为每个文件（或归档成员的虚拟路径）返回转换结果的输出位置，该输出在文件分析完成后关闭
+//   - input: 输入解析配置
 //   - sqlParser: SQL 解析器实例，用于将 SQL 文本解析为 AST
 //   - checkers: 检查器列表，用于检测兼容性问题和执行转换
 //
 // 返回:
-//   - model.AnalysisResult: 分析结果，包含目录路径和所有文件的问题汇总
+//   - []model.AnalysisResult: 每个文件一个分析结果，Source 为文件路径
 //   - error: 如果目录访问失败或遍历目录时出错，返回错误
 //
 // 注意:
-//   - 单个文件分析失败不会中断整个目录遍历，错误会记录到 issues 中
-//   - 支持的文件类型：.sql（SQL 文件）、.log（日志文件）
-func analyzeDirectory(dirPath string, sqlParser sqlparser.SQLParser, checkers []checker.Checker) (model.AnalysisResult, error) {
+//   - 打开或关闭输出失败与文件分析失败一样记录到该文件结果的 issues 中
+func AnalyzeDirectoryStream(dirPath string, output func(path string) (io.WriteCloser, error), input config.InputConfig, sqlParser sqlparser.SQLParser, checkers []checker.Checker) ([]model.AnalysisResult, error) {
+	return walkDirectory(dirPath, func(path string, r io.Reader) (model.AnalysisResult, error) {
+		out, err := output(path)
+		if err != nil {
+			return model.AnalysisResult{Source: path}, fmt.Errorf("打开输出失败: %w", err)
+		}
+		result, err := analyzeReaderTo(path, r, out, nil, input, sqlParser, checkers)
+		if closeErr := out.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("保存转换SQL失败: %w", closeErr)
+		}
+		return result, err
+	})
+}
+
+// walkDirectory 递归遍历目录，按文件路径顺序分析所有受支持的文件和归档成员
+// dirPath 为归档文件时只遍历该归档中的成员。
+// 单个文件分析失败不会中断遍历，错误记录到该文件结果的 issues 中；
+// 归档读取失败时记录到以归档路径为 Source 的结果中，已分析的成员结果保留。
+func walkDirectory(dirPath string, analyze func(path string, r io.Reader) (model.AnalysisResult, error)) ([]model.AnalysisResult, error) {
 	fileInfo, err := os.Stat(dirPath)
 	if err != nil {
-		return model.AnalysisResult{
-			Source: dirPath,
-		}, fmt.Errorf("访问目录失败: %w", err)
+		return nil, fmt.Errorf("访问目录失败: %w", err)
 	}
 
-	if !fileInfo.IsDir() {
-		return model.AnalysisResult{
-			Source: dirPath,
-		}, fmt.Errorf("%s 不是目录", dirPath)
+	var results []model.AnalysisResult
+
+	// record 记录一个文件的分析结果，错误记录到该文件的 issues 但不中断遍历
+	record := func(path string, result model.AnalysisResult, err error) {
+		result.Source = path
+		if err != nil {
+			result.Issues = append(result.Issues, model.Issue{
+				Checker: "Error",
+				Message: fmt.Sprintf("分析文件失败: %v", err),
+				Line:    0,
+				File:    path,
+			})
+		}
+		results = append(results, result)
+	}
+
+	// visit 分析一个输入，不是 MyBatis mapper 的 .xml 文件（如 pom.xml）
+	// 和不是语句摘要导出的 .csv、.json 文件（如 package.json）直接跳过
+	visit := func(path string, r io.Reader) {
+		reader := bufio.NewReaderSize(r, sniffBytes)
+		switch inputparser.InputExt(path) {
+		case ".xml":
+			head, _ := reader.Peek(sniffBytes)
+			if !inputparser.IsMapper(bytes.NewReader(head)) {
+				return
+			}
+		case ".csv", ".json":
+			head, _ := reader.Peek(sniffBytes)
+			if !inputparser.IsDigestExport(bytes.NewReader(head)) {
+				return
+			}
+		}
+		result, err := analyze(path, reader)
+		record(path, result, err)
+	}
+
+	// walkArchive 逐个分析归档中受支持的成员，嵌套的归档不展开
+	walkArchive := func(archivePath string) {
+		err := inputparser.WalkArchive(archivePath, func(member string, r io.Reader) error {
+			if inputparser.IsArchive(member) || !isSupportedFileExt(inputparser.InputExt(member)) {
+				return nil
+			}
+			visit(member, r)
+			return nil
+		})
+		if err != nil {
+			record(archivePath, model.AnalysisResult{Issues: []model.Issue{}}, err)
+		}
 	}
 
-	var allIssues []model.Issue
+	if !fileInfo.IsDir() {
+		if !inputparser.IsArchive(dirPath) {
+			return nil, fmt.Errorf("%s 不是目录", dirPath)
+		}
+		walkArchive(dirPath)
+		return results, nil
+	}
 
 	// 遍历目录
 	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
@@ -414,90 +698,102 @@ func analyzeDirectory(dirPath string, sqlParser sqlparser.SQLParser, checkers []
 			return nil
 		}
 
-		ext := strings.ToLower(filepath.Ext(path))
-		if !isSupportedFileExt(ext) {
+		if inputparser.IsArchive(path) {
+			walkArchive(path)
 			return nil
 		}
 
-		// 分析文件，错误记录到 issues 但不中断遍历
-		result, err := analyzeFile(path, sqlParser, checkers)
-		if err != nil {
-			// 记录错误但不中断目录遍历
-			allIssues = append(allIssues, model.Issue{
-				Checker: "Error",
-				Message: fmt.Sprintf("分析文件失败: %v", err),
-				Line:    0,
-				File:    path,
-			})
+		if !isSupportedFileExt(inputparser.InputExt(path)) {
 			return nil
 		}
 
-		allIssues = append(allIssues, result.Issues...)
+		// 分析文件，压缩文件边读边解压
+		file, err := inputparser.OpenFile(path)
+		if err != nil {
+			record(path, model.AnalysisResult{Issues: []model.Issue{}}, err)
+			return nil
+		}
+		visit(path, file)
+		if closeErr := file.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "关闭文件 %s 失败: %v\n", path, closeErr)
+		}
 		return nil
 	})
 
 	if err != nil {
-		return model.AnalysisResult{
-			Source: dirPath,
-			Issues: allIssues,
-		}, fmt.Errorf("遍历目录时出错: %w", err)
+		return results, fmt.Errorf("遍历目录时出错: %w", err)
 	}
 
-	return model.AnalysisResult{
-		Source: dirPath,
-		Issues: allIssues,
-	}, nil
+	return results, nil
 }
 
 // AnalyzeInput 分析输入源并返回结果。
-// 这是分析功能的统一入口，支持多种输入类型：文件路径、目录路径、SQL 字符串、io.Reader。
-// 函数会自动识别输入类型并调用相应的分析逻辑。
+// 这是单个输入的统一入口，支持多种输入类型：文件路径、SQL 字符串、io.Reader。
+// 函数会自动识别输入类型并调用相应的分析逻辑；目录与归档请使用 AnalyzeDirectory，每个文件返回独立的结果。
 // 参数:
 //   - source: 输入源，支持以下类型：
-//   - string: 文件路径、目录路径或 SQL 字符串（自动识别）
-//   - io.Reader: 流式输入，从 Reader 读取内容后分析
+//   - string: 文件路径、归档成员的虚拟路径或 SQL 字符串（自动识别）
+//   - io.Reader: 流式输入，从 Reader 逐条读取语句后分析
+//   - input: 输入解析配置，对文件与目录输入生效
 //   - sqlParser: SQL 解析器实例，用于将 SQL 文本解析为 AST
 //   - checkers: 检查器列表，用于检测兼容性问题和执行转换
 //
 // 返回:
 //   - model.AnalysisResult: 分析结果，包含原始 SQL、发现的问题和转换后的 SQL
-//   - error: 如果输入类型不支持、文件/目录访问失败、创建分析器失败或 SQL 解析失败，返回错误
+//   - error: 如果输入类型不支持、输入是目录或归档、文件访问失败、创建分析器失败或 SQL 解析失败，返回错误
 //
 // 输入类型识别规则:
 //   - string 类型：
-//   - 如果路径存在且是目录：调用 analyzeDirectory
-//   - 如果路径存在且是文件：调用 analyzeFile
+//   - 如果路径存在且是目录或归档文件（.tar、.tar.gz、.tgz、.tar.zst、.zip）：返回错误，请使用 AnalyzeDirectory
+//   - 如果路径存在且是文件：调用 analyzeFile，.gz、.zst 压缩文件透明解压
 //   - .log: 使用日志文件解析器
+//   - .xml: 使用 MyBatis mapper 解析器
+//   - .csv、.json: 使用语句摘要导出解析器
 //   - 其他: 返回错误（不支持的文件类型）
+//   - 如果是归档成员的虚拟路径（如 archive.tar.gz!/db/users.sql）且归档存在：调用 analyzeFile
 //   - 如果路径不存在：作为 SQL 字符串处理
-//   - io.Reader: 读取内容后作为 SQL 字符串分析
+//   - io.Reader: 逐条读取语句并分析，结果中保留读取到的 SQL 与转换后的 SQL
+//
+// 注意:
+//   - 文件与 io.Reader 输入的原始 SQL 与转换后的 SQL 完整保存在结果中，内存占用与输入大小成正比，没有上限；
+//     分析多 GB 的导出文件或日志时请使用 AnalyzeInputTo，转换后的 SQL 逐条写入指定输出
 //
 // 示例:
 //
 //	// 分析文件
-//	result, err := AnalyzeInput("/path/to/file.sql", sqlParser, checkers)
+//	result, err := AnalyzeInput("/path/to/file.sql", cfg.Input, sqlParser, checkers)
 //
-//	// 分析目录
-//	result, err := AnalyzeInput("/path/to/dir", sqlParser, checkers)
+//	// 分析目录，每个文件一个结果
+//	results, err := AnalyzeDirectory("/path/to/dir", cfg.Input, sqlParser, checkers)
+//
+//	// 分析归档中的单个文件
+//	result, err := AnalyzeInput("/path/to/backup.tar.gz!/db/users.sql", cfg.Input, sqlParser, checkers)
 //
 //	// 分析 SQL 字符串
-//	result, err := AnalyzeInput("CREATE TABLE test (id INT)", sqlParser, checkers)
+//	result, err := AnalyzeInput("CREATE TABLE test (id INT)", cfg.Input, sqlParser, checkers)
 //
 //	// 分析流输入
-//	result, err := AnalyzeInput(strings.NewReader("SELECT * FROM users"), sqlParser, checkers)
-func AnalyzeInput(source any, sqlParser sqlparser.SQLParser, checkers []checker.Checker) (model.AnalysisResult, error) {
+//	result, err := AnalyzeInput(strings.NewReader("SELECT * FROM users"), cfg.Input, sqlParser, checkers)
+func AnalyzeInput(source any, input config.InputConfig, sqlParser sqlparser.SQLParser, checkers []checker.Checker) (model.AnalysisResult, error) {
 	switch v := source.(type) {
 	case string:
 		// 检查是文件、目录还是SQL字符串
 		fileInfo, err := os.Stat(v)
 		if err == nil {
-			// 路径存在，判断是目录还是文件
-			if fileInfo.IsDir() {
-				return analyzeDirectory(v, sqlParser, checkers)
+			// 路径存在，目录（或归档）包含多个文件，不能合并为单个结果
+			if fileInfo.IsDir() || inputparser.IsArchive(v) {
+				return model.AnalysisResult{Source: v}, fmt.Errorf("不支持目录输入: %s，请使用 AnalyzeDirectory", v)
 			}
 
 			// 是文件，直接使用 analyzeFile，它已经支持自动识别文件类型
-			return analyzeFile(v, sqlParser, checkers)
+			return analyzeFile(v, input, sqlParser, checkers)
+		}
+
+		// 归档成员的虚拟路径
+		if archive, member := inputparser.SplitVirtualPath(v); member != "" {
+			if info, statErr := os.Stat(archive); statErr == nil && !info.IsDir() {
+				return analyzeFile(v, input, sqlParser, checkers)
+			}
 		}
 
 		// 路径不存在，作为 SQL 字符串处理
@@ -508,19 +804,64 @@ func AnalyzeInput(source any, sqlParser sqlparser.SQLParser, checkers []checker.
 		return analyzer.AnalyzeSQL(v, "input_string")
 
 	case io.Reader:
-		// 读取内容并分析
-		content, err := io.ReadAll(v)
+		// 逐条读取语句并分析
+		analyzer, err := NewSQLAnalyzer(inputparser.NewStringParser(), sqlParser, checkers)
 		if err != nil {
-			return model.AnalysisResult{
-				Source: "io.Reader",
-			}, fmt.Errorf("读取输入流失败: %w", err)
+			return model.AnalysisResult{Source: "io.Reader"}, fmt.Errorf("创建分析器失败: %w", err)
 		}
+		return analyzer.analyzeReader(v, "io.Reader")
 
+	default:
+		return model.AnalysisResult{
+			Source: "unknown",
+		}, fmt.Errorf("不支持的输入类型: %T，仅支持 string 或 io.Reader", source)
+	}
+}
+
+// AnalyzeInputTo 流式分析输入源，转换后的 SQL 逐条写入 out。
+// 输入类型识别规则与 AnalyzeInput 相同，但结果中不保留原始 SQL 与转换后的 SQL，
+// 文件与 io.Reader 输入的内存占用与输入大小无关，适合在库中分析多 GB 的导出文件和日志。
+// 参数:
+//   - source: 输入源，支持文件路径、归档成员的虚拟路径、SQL 字符串和 io.Reader
+//   - out: 转换后 SQL 的输出位置，为 nil 时丢弃
+//   - input: 输入解析配置，对文件输入生效
+//   - sqlParser: SQL 解析器实例，用于将 SQL 文本解析为 AST
+//   - checkers: 检查器列表，用于检测兼容性问题和执行转换
+//
+// 返回:
+//   - model.AnalysisResult: 分析结果，包含发现的问题
+//   - error: 与 AnalyzeInput 相同；目录与归档输入返回错误，请使用 AnalyzeDirectoryStream 逐文件输出
+func AnalyzeInputTo(source any, out io.Writer, input config.InputConfig, sqlParser sqlparser.SQLParser, checkers []checker.Checker) (model.AnalysisResult, error) {
+	switch v := source.(type) {
+	case string:
+		fileInfo, err := os.Stat(v)
+		if err == nil {
+			if fileInfo.IsDir() || inputparser.IsArchive(v) {
+				return model.AnalysisResult{Source: v}, fmt.Errorf("不支持目录输入: %s，请使用 AnalyzeDirectoryStream", v)
+			}
+			return analyzeFileTo(v, out, nil, input, sqlParser, checkers)
+		}
+
+		// 归档成员的虚拟路径
+		if archive, member := inputparser.SplitVirtualPath(v); member != "" {
+			if info, statErr := os.Stat(archive); statErr == nil && !info.IsDir() {
+				return analyzeFileTo(v, out, nil, input, sqlParser, checkers)
+			}
+		}
+
+		// 路径不存在，作为 SQL 字符串处理
+		analyzer, err := NewSQLAnalyzer(inputparser.NewStringParser(), sqlParser, checkers)
+		if err != nil {
+			return model.AnalysisResult{Source: v}, fmt.Errorf("创建分析器失败: %w", err)
+		}
+		return analyzer.AnalyzeStream(strings.NewReader(v), "input_string", out)
+
+	case io.Reader:
 		analyzer, err := NewSQLAnalyzer(inputparser.NewStringParser(), sqlParser, checkers)
 		if err != nil {
 			return model.AnalysisResult{Source: "io.Reader"}, fmt.Errorf("创建分析器失败: %w", err)
 		}
-		return analyzer.AnalyzeSQL(string(content), "io.Reader")
+		return analyzer.AnalyzeStream(v, "io.Reader", out)
 
 	default:
 		return model.AnalysisResult{
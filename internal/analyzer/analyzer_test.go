@@ -1,17 +1,24 @@
 package analyzer
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/simplifiedchinese"
 
 	"github.com/example/ybMigration/internal/checker"
+	"github.com/example/ybMigration/internal/config"
 	inputparser "github.com/example/ybMigration/internal/input-parser"
 	"github.com/example/ybMigration/internal/model"
 	"github.com/example/ybMigration/internal/report"
@@ -168,6 +175,83 @@ func TestSQLAnalyzer_AnalyzeSQL(t *testing.T) {
 		assert.Equal(t, sql, result.SQL)
 		assert.Equal(t, "test", result.Source)
 	})
+
+	t.Run("partial_parse_failure", func(t *testing.T) {
+		sql := "CREATE TABLE a (id TINYINT);\nSELEC id FROM a;\n  SELECT GROUP_CONCAT(name) FROM users;"
+		result, err := analyzer.AnalyzeSQL(sql, "test")
+		require.NoError(t, err, "单条语句解析失败不应使整个输入失败")
+
+		// 失败语句记录为带位置的问题，其余语句照常转换
+		var parseIssues []model.Issue
+		for _, issue := range result.Issues {
+			if issue.Checker == "SQLParser" {
+				parseIssues = append(parseIssues, issue)
+			}
+		}
+		require.Len(t, parseIssues, 1)
+		assert.Equal(t, 2, parseIssues[0].Line)
+		assert.Equal(t, 5, parseIssues[0].Column)
+		assert.Greater(t, len(result.Issues), 1, "其余语句的问题应被检测到")
+		assert.Equal(t, 2, strings.Count(result.TransformedSQL, ";\n")+1)
+	})
+
+	t.Run("issue_positions", func(t *testing.T) {
+		sql := "-- header\nSELEC 1;\nSELECT id,\n    GROUP_CONCAT(name) FROM users; CREATE TABLE t (id TINYINT)"
+		result, err := analyzer.AnalyzeSQL(sql, "test")
+		require.NoError(t, err)
+
+		byChecker := make(map[string]model.Issue)
+		for _, issue := range result.Issues {
+			if _, ok := byChecker[issue.Checker]; !ok {
+				byChecker[issue.Checker] = issue
+			}
+		}
+
+		// 位置为原始输入中的位置，语句序号包含解析失败的语句
+		parse := byChecker["SQLParser"]
+		assert.Equal(t, 1, parse.StatementIndex)
+		assert.Equal(t, 2, parse.Line)
+		assert.Equal(t, "SELEC 1", parse.Snippet)
+
+		groupConcat := byChecker["FunctionChecker"]
+		assert.Equal(t, 2, groupConcat.StatementIndex)
+		assert.Equal(t, 4, groupConcat.Line)
+		assert.Equal(t, 5, groupConcat.Column)
+		assert.Equal(t, "GROUP_CONCAT(name)", groupConcat.Snippet)
+
+		tinyint := byChecker["DataTypeChecker"]
+		assert.Equal(t, 3, tinyint.StatementIndex)
+		assert.Equal(t, 4, tinyint.Line)
+		assert.Equal(t, 52, tinyint.Column)
+		assert.Equal(t, "id TINYINT", tinyint.Snippet)
+	})
+
+	t.Run("unparsable_placeholders", func(t *testing.T) {
+		result, err := analyzer.AnalyzeSQL("CREATE TABLE t (a INT DEFAULT ?)", "test")
+		require.NoError(t, err, "占位符替换为 NULL 后应能解析")
+
+		require.NotEmpty(t, result.Issues)
+		assert.Equal(t, "SQLParser", result.Issues[0].Checker)
+		assert.Contains(t, result.Issues[0].Message, "已替换为 NULL")
+		assert.Contains(t, result.TransformedSQL, "DEFAULT NULL")
+	})
+
+	t.Run("all_statements_fail", func(t *testing.T) {
+		sql := "SELECT 1;\n\nSELEC 2"
+		_, err := analyzer.AnalyzeSQL("SELEC 1;\n\n  SELEC 2", "test")
+		require.Error(t, err)
+
+		var analysisErr *model.AnalysisError
+		require.True(t, errors.As(err, &analysisErr))
+		assert.Equal(t, model.ErrorTypeParse, analysisErr.Type)
+		assert.Equal(t, 1, analysisErr.Line)
+		assert.Equal(t, 5, analysisErr.Column)
+
+		result, err := analyzer.AnalyzeSQL(sql, "test")
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Issues)
+		assert.Equal(t, 3, result.Issues[0].Line)
+	})
 }
 
 // TestNewAnalyzerFactory 测试分析器工厂创建
@@ -211,9 +295,9 @@ func TestAnalyzerFactory_CreateCheckers(t *testing.T) {
 	})
 
 	t.Run("create_multiple_checkers", func(t *testing.T) {
-		checkers, err := factory.CreateCheckers("datatype", "function", "syntax", "charset")
+		checkers, err := factory.CreateCheckers("datatype", "function", "syntax", "charset", "dml", "multitable", "trigger", "index", "sharding")
 		require.NoError(t, err)
-		assert.Len(t, checkers, 4)
+		assert.Len(t, checkers, 9)
 	})
 
 	t.Run("create_no_checkers", func(t *testing.T) {
@@ -239,11 +323,11 @@ func TestAnalyzerFactory_CreateCheckersFromConfig(t *testing.T) {
 		checkers, err := factory.CreateCheckersFromConfig()
 		require.NoError(t, err)
 
-		// 默认配置包含所有类别，应该创建4个检查器
-		assert.Len(t, checkers, 4)
+		// 默认配置包含所有类别，应该创建9个检查器
+		assert.Len(t, checkers, 9)
 
 		// 验证检查器类型（顺序可能不同，用类型断言检查）
-		var foundDatatype, foundFunction, foundSyntax, foundCharset bool
+		var foundDatatype, foundFunction, foundSyntax, foundCharset, foundDML, foundMultiTable, foundTrigger, foundIndex, foundSharding bool
 		for _, ch := range checkers {
 			switch ch.(type) {
 			case *checker.DataTypeChecker:
@@ -254,12 +338,27 @@ func TestAnalyzerFactory_CreateCheckersFromConfig(t *testing.T) {
 				foundSyntax = true
 			case *checker.CharsetChecker:
 				foundCharset = true
+			case *checker.DMLChecker:
+				foundDML = true
+			case *checker.MultiTableChecker:
+				foundMultiTable = true
+			case *checker.TriggerChecker:
+				foundTrigger = true
+			case *checker.IndexChecker:
+				foundIndex = true
+			case *checker.ShardingChecker:
+				foundSharding = true
 			}
 		}
 		assert.True(t, foundDatatype, "应该包含 DataTypeChecker")
 		assert.True(t, foundFunction, "应该包含 FunctionChecker")
 		assert.True(t, foundSyntax, "应该包含 SyntaxChecker")
 		assert.True(t, foundCharset, "应该包含 CharsetChecker")
+		assert.True(t, foundDML, "应该包含 DMLChecker")
+		assert.True(t, foundMultiTable, "应该包含 MultiTableChecker")
+		assert.True(t, foundTrigger, "应该包含 TriggerChecker")
+		assert.True(t, foundIndex, "应该包含 IndexChecker")
+		assert.True(t, foundSharding, "应该包含 ShardingChecker")
 	})
 
 	t.Run("extract_categories_from_config", func(t *testing.T) {
@@ -267,10 +366,15 @@ func TestAnalyzerFactory_CreateCheckersFromConfig(t *testing.T) {
 
 		// 默认配置应该包含所有类别
 		expectedCategories := map[string]bool{
-			"datatype": false,
-			"function": false,
-			"syntax":   false,
-			"charset":  false,
+			"datatype":   false,
+			"function":   false,
+			"syntax":     false,
+			"charset":    false,
+			"dml":        false,
+			"multitable": false,
+			"trigger":    false,
+			"index":      false,
+			"sharding":   false,
 		}
 
 		for _, cat := range categories {
@@ -300,7 +404,7 @@ func TestAnalyzeInput_File(t *testing.T) {
 	t.Run("analyze_sql_file", func(t *testing.T) {
 		// 使用绝对路径
 		testDataPath := "../../testdata/mysql_queries.sql"
-		result, err := AnalyzeInput(testDataPath, sqlParser, checkers)
+		result, err := AnalyzeInput(testDataPath, config.InputConfig{}, sqlParser, checkers)
 		require.NoError(t, err)
 
 		assert.NotEmpty(t, result.Source)
@@ -309,12 +413,274 @@ func TestAnalyzeInput_File(t *testing.T) {
 	})
 
 	t.Run("analyze_nonexistent_file", func(t *testing.T) {
-		_, err := AnalyzeInput("nonexistent.sql", sqlParser, checkers)
+		_, err := AnalyzeInput("nonexistent.sql", config.InputConfig{}, sqlParser, checkers)
 		require.Error(t, err) // AnalyzeInput对于不存在的文件会作为SQL字符串处理，但会返回解析错误
 
 		// 不存在的文件会被当作SQL字符串处理，所以错误信息可能是SQL解析失败
 		assert.True(t, strings.Contains(err.Error(), "读取SQL文件失败") || strings.Contains(err.Error(), "SQL 解析失败"))
 	})
+
+	t.Run("analyze_slow_log", func(t *testing.T) {
+		result, err := AnalyzeInput("../../testdata/slow_query_example.log", config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+
+		bySnippet := make(map[string]model.Issue)
+		for _, issue := range result.Issues {
+			bySnippet[issue.Snippet] = issue
+		}
+
+		// 问题行号为日志文件中的行号，并带有该条日志的执行耗时等元数据
+		groupConcat, ok := bySnippet["GROUP_CONCAT(product_name)"]
+		require.True(t, ok)
+		assert.Equal(t, 15, groupConcat.Line)
+		assert.Equal(t, 8, groupConcat.Column)
+		assert.Equal(t, "5.512000", groupConcat.Meta[inputparser.SlowMetaQueryTime])
+		assert.Equal(t, "report", groupConcat.Meta[inputparser.SlowMetaUser])
+
+		for _, issue := range result.Issues {
+			if issue.Line == 9 {
+				assert.Equal(t, "shop", issue.Meta[inputparser.SlowMetaSchema])
+				assert.Equal(t, "120000", issue.Meta[inputparser.SlowMetaRowsExamined])
+			}
+		}
+	})
+
+	t.Run("analyze_mysqldump", func(t *testing.T) {
+		result, err := AnalyzeInput("../../testdata/mysqldump_example.sql", config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+
+		// 版本注释已展开，LOCK TABLES 已去除
+		assert.Contains(t, result.TransformedSQL, "CREATE SCHEMA IF NOT EXISTS shop")
+		assert.Contains(t, result.TransformedSQL, "INSERT INTO users VALUES (1,'alice','a;b')")
+		assert.NotContains(t, result.TransformedSQL, "LOCK")
+		assert.NotContains(t, result.SQL, "/*!")
+
+		// 问题带有语句所在的导出分段，触发器定义体作为一条语句解析
+		var trigger *model.Issue
+		for i, issue := range result.Issues {
+			if issue.Line == 44 {
+				trigger = &result.Issues[i]
+			}
+		}
+		require.NotNil(t, trigger)
+		assert.Equal(t, "SQLParser", trigger.Checker)
+		assert.Equal(t, inputparser.DumpSectionTrigger, trigger.Meta[inputparser.DumpMetaSection])
+		assert.Equal(t, "users", trigger.Meta[inputparser.DumpMetaTable])
+	})
+
+	t.Run("analyze_prepared_statements", func(t *testing.T) {
+		result, err := AnalyzeInput("../../testdata/general_log_prepared.log", config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+
+		// 占位符转换为 $n，执行次数附加在问题上
+		assert.Contains(t, result.TransformedSQL, "COALESCE(nickname, $1)")
+		assert.Contains(t, result.TransformedSQL, "VALUES ($1,$2)")
+
+		executions := make(map[int]string)
+		counts := make(map[int]int)
+		for _, issue := range result.Issues {
+			executions[issue.Line] = issue.Meta[inputparser.GeneralMetaExecutions]
+			counts[issue.Line] = issue.Executions
+		}
+		assert.Equal(t, "3", executions[2])
+		// 未匹配到 Prepare 的 Execute 按语句指纹合并：Close stmt 之后的执行计入同指纹的预处理语句
+		assert.Equal(t, 4, counts[2])
+		assert.Equal(t, 2, counts[12])
+	})
+
+	t.Run("fingerprint_grouping", func(t *testing.T) {
+		var content strings.Builder
+		for i := 1; i <= 5; i++ {
+			fmt.Fprintf(&content, "2023-12-23T08:00:0%d.000000Z\t   %d Query\tSELECT IFNULL(name, 'n%d') FROM users WHERE id = %d\n", i, i, i, i)
+		}
+		content.WriteString("2023-12-23T08:00:06.000000Z\t   1 Query\tSELECT GROUP_CONCAT(name) FROM users\n")
+		path := filepath.Join(t.TempDir(), "repeated.log")
+		require.NoError(t, os.WriteFile(path, []byte(content.String()), 0600))
+
+		result, err := AnalyzeInput(path, config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+
+		// 相同指纹的语句只分析一次，问题记录累计执行次数
+		assert.Equal(t, 2, strings.Count(result.SQL, ";\n"))
+		for _, issue := range result.Issues {
+			require.NotEmpty(t, issue.Fingerprint)
+			switch issue.Line {
+			case 1:
+				assert.Equal(t, 5, issue.Executions)
+			case 6:
+				assert.Equal(t, 1, issue.Executions)
+			default:
+				t.Fatalf("unexpected issue line %d", issue.Line)
+			}
+		}
+
+		// 报告按执行次数排序
+		r := report.GenerateReport(result, nil, checkers)
+		require.NotEmpty(t, r.RankedIssues)
+		assert.Contains(t, r.RankedIssues[0].Message, "IFNULL")
+		assert.Equal(t, 5, r.RankedIssues[0].Executions)
+		assert.Equal(t, 1, r.RankedIssues[len(r.RankedIssues)-1].Executions)
+
+		// 目录中多个日志文件的相同语句分别计数后相加
+		second := filepath.Join(filepath.Dir(path), "repeated.log.1")
+		require.NoError(t, os.WriteFile(second, []byte(content.String()), 0600))
+		results, err := AnalyzeDirectory(filepath.Dir(path), config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		r = report.GenerateReportFromMultiple(results, nil, checkers)
+		require.NotEmpty(t, r.RankedIssues)
+		assert.Contains(t, r.RankedIssues[0].Message, "IFNULL")
+		assert.Equal(t, 10, r.RankedIssues[0].Executions)
+		assert.Equal(t, 1, r.RankedIssues[0].Fingerprints, "相同指纹只计为一种语句")
+	})
+
+	t.Run("analyze_binlog", func(t *testing.T) {
+		syntaxCheckers, err := factory.CreateCheckers("syntax")
+		require.NoError(t, err)
+		result, err := AnalyzeInput("../../testdata/binlog_example.sql", config.InputConfig{}, sqlParser, syntaxCheckers)
+		require.NoError(t, err)
+
+		// 行事件的伪 SQL 转换为可执行的语句
+		assert.Contains(t, result.TransformedSQL, "INSERT INTO shop.users (col_1,col_2) VALUES (1,'alice')")
+		assert.Contains(t, result.TransformedSQL, "DELETE FROM shop.users WHERE id=2 AND name IS NULL")
+		assert.NotContains(t, result.TransformedSQL, "BINLOG")
+
+		// 问题附带事件位置与 GTID
+		require.NotEmpty(t, result.Issues)
+		for _, issue := range result.Issues {
+			assert.Equal(t, "234", issue.Meta[inputparser.BinlogMetaPosition], "AUTO_INCREMENT 位于第一个 Query 事件")
+			assert.Equal(t, "3e11fa47-71ca-11e1-9e33-c80aa9429562:1", issue.Meta[inputparser.BinlogMetaGTID])
+		}
+	})
+
+	t.Run("analyze_mybatis_mapper", func(t *testing.T) {
+		result, err := AnalyzeInput("../../testdata/UserMapper.xml", config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+
+		// 动态 SQL 的完整与最简展开都被转换
+		assert.Contains(t, result.TransformedSQL, "WHERE name LIKE CONCAT('%', $1, '%') AND id IN ($2)")
+		assert.Contains(t, result.TransformedSQL, "FROM users ORDER BY $1")
+		assert.Contains(t, result.TransformedSQL, "UPDATE users SET updated_at=CURRENT_TIMESTAMP WHERE id=$1")
+
+		// 问题指向 mapper 文件中的语句 id 与行号，多种展开方式中的相同问题只报告一次
+		lines := make(map[int]int)
+		for _, issue := range result.Issues {
+			lines[issue.Line]++
+			if issue.Line == 9 {
+				assert.Contains(t, issue.Message, "IFNULL")
+				assert.Equal(t, "findUsers", issue.Meta[inputparser.MapperMetaStatement])
+				assert.Equal(t, "com.example.mapper.UserMapper", issue.Meta[inputparser.MapperMetaNamespace])
+			}
+		}
+		assert.Equal(t, 1, lines[9])
+		assert.Equal(t, 1, lines[40], "SET 中的 NOW() 位于 updateUser 的第 5 行")
+	})
+
+	t.Run("skip_non_mapper_xml", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "pom.xml"), []byte("<project></project>"), 0600))
+		mapper, err := os.ReadFile("../../testdata/UserMapper.xml")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "UserMapper.xml"), mapper, 0600))
+
+		results, err := AnalyzeDirectory(dir, config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+		require.Len(t, results, 1, "非 mapper 的 XML 文件应被跳过")
+		assert.Equal(t, filepath.Join(dir, "UserMapper.xml"), results[0].Source)
+
+		_, err = AnalyzeInput(filepath.Join(dir, "pom.xml"), config.InputConfig{}, sqlParser, checkers)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "不是 MyBatis mapper 文件")
+	})
+
+	t.Run("analyze_digest_export", func(t *testing.T) {
+		result, err := AnalyzeInput("../../testdata/digest_example.csv", config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+
+		// 问题附带 COUNT_STAR 与 SUM_TIMER_WAIT 换算的执行次数和耗时
+		var ifnull *model.Issue
+		for i, issue := range result.Issues {
+			if strings.Contains(issue.Message, "IFNULL") {
+				ifnull = &result.Issues[i]
+			}
+		}
+		require.NotNil(t, ifnull)
+		assert.Equal(t, 2, ifnull.Line)
+		assert.Equal(t, 1520, ifnull.Executions)
+		assert.InDelta(t, 98.0, ifnull.TotalLatency, 1e-9)
+		assert.Equal(t, "shop", ifnull.Meta[inputparser.DigestMetaSchema])
+
+		// 报告优先按累计耗时排序
+		r := report.GenerateReport(result, nil, checkers)
+		require.NotEmpty(t, r.RankedIssues)
+		assert.Contains(t, r.RankedIssues[0].Message, "IFNULL")
+		assert.InDelta(t, 98.0, r.RankedIssues[0].TotalLatency, 1e-9)
+
+		// 目录中不是语句摘要导出的 JSON、CSV 文件被跳过
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"name": "web"}`), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte("id,name\n1,alice\n"), 0600))
+		results, err := AnalyzeDirectory(dir, config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+
+		_, err = AnalyzeInput(filepath.Join(dir, "users.csv"), config.InputConfig{}, sqlParser, checkers)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "不是 performance_schema 语句摘要导出文件")
+	})
+
+	t.Run("analyze_legacy_encoding", func(t *testing.T) {
+		dir := t.TempDir()
+		gbk, err := simplifiedchinese.GBK.NewEncoder().String("-- 用户昵称\nSELECT IFNULL(nickname, '匿名') FROM users;\n")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "legacy.sql"), []byte(gbk), 0600))
+
+		// GBK 文件转换为 UTF-8 后分析，结果记录识别出的编码
+		result, err := AnalyzeInput(filepath.Join(dir, "legacy.sql"), config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+		assert.Equal(t, inputparser.EncodingGBK, result.Encoding)
+		assert.Contains(t, result.SQL, "-- 用户昵称")
+		assert.Contains(t, result.TransformedSQL, "'匿名'")
+
+		// 没有声明编码、也不是合法 UTF-8 或 GBK 的内容按 latin1 处理
+		broken := filepath.Join(dir, "broken.sql")
+		require.NoError(t, os.WriteFile(broken, []byte("SELECT 1;\nSELECT 'a\xFFb';\n"), 0600))
+		results, err := AnalyzeDirectory(dir, config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, inputparser.EncodingLatin1, results[0].Encoding)
+		assert.Equal(t, inputparser.EncodingGBK, results[1].Encoding)
+
+		// 按配置的编码无法解码的字节序列作为问题报告
+		result, err = AnalyzeFile(broken, io.Discard, config.InputConfig{Encoding: "utf8mb4"}, sqlParser, checkers)
+		require.NoError(t, err)
+		assert.Equal(t, inputparser.EncodingUTF8, result.Encoding)
+		var encodingIssues []model.Issue
+		for _, issue := range result.Issues {
+			if issue.Checker == "Encoding" {
+				encodingIssues = append(encodingIssues, issue)
+			}
+		}
+		require.Len(t, encodingIssues, 1)
+		assert.Equal(t, 2, encodingIssues[0].Line)
+		assert.Equal(t, broken, encodingIssues[0].File)
+		assert.Equal(t, inputparser.EncodingSourceConfig, encodingIssues[0].Meta[inputparser.EncodingMetaSource])
+
+		// 目录与统一入口同样按配置的编码解码
+		input := config.InputConfig{Encoding: "utf8mb4"}
+		results, err = AnalyzeDirectory(dir, input, sqlParser, checkers)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, inputparser.EncodingUTF8, results[0].Encoding)
+		assert.Equal(t, inputparser.EncodingUTF8, results[1].Encoding)
+		result, err = AnalyzeInput(broken, input, sqlParser, checkers)
+		require.NoError(t, err)
+		assert.Equal(t, inputparser.EncodingUTF8, result.Encoding)
+
+		_, err = AnalyzeFile(broken, io.Discard, config.InputConfig{Encoding: "ebcdic"}, sqlParser, checkers)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "不支持的输入编码")
+	})
 }
 
 // TestAnalyzeInput 测试通用输入分析功能
@@ -331,7 +697,7 @@ func TestAnalyzeInput(t *testing.T) {
 
 	t.Run("analyze_sql_string", func(t *testing.T) {
 		sql := "CREATE TABLE test (id TINYINT, name VARCHAR(255))"
-		result, err := AnalyzeInput(sql, sqlParser, checkers)
+		result, err := AnalyzeInput(sql, config.InputConfig{}, sqlParser, checkers)
 		require.NoError(t, err)
 
 		assert.Equal(t, sql, result.SQL)
@@ -340,13 +706,325 @@ func TestAnalyzeInput(t *testing.T) {
 	})
 
 	t.Run("analyze_unsupported_type", func(t *testing.T) {
-		result, err := AnalyzeInput(123, sqlParser, checkers)
+		result, err := AnalyzeInput(123, config.InputConfig{}, sqlParser, checkers)
 		require.Error(t, err) // AnalyzeInput对于不支持类型确实返回错误
 
 		assert.Equal(t, "unknown", result.Source)
 		assert.Contains(t, err.Error(), "不支持的输入类型")
 		// 移除 result.Error 检查，因为现在错误通过返回值处理
 	})
+
+	t.Run("analyze_to_writer", func(t *testing.T) {
+		var out strings.Builder
+		sql := "CREATE TABLE test (id TINYINT, name VARCHAR(255))"
+		result, err := AnalyzeInputTo(strings.NewReader(sql), &out, config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+
+		// 转换结果写入输出，结果中不保留 SQL
+		assert.Empty(t, result.SQL)
+		assert.Empty(t, result.TransformedSQL)
+		assert.Contains(t, out.String(), "CREATE TABLE")
+		assert.NotEmpty(t, result.Issues)
+
+		out.Reset()
+		result, err = AnalyzeInputTo("../../testdata/mysql_queries.sql", &out, config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+		assert.Equal(t, "../../testdata/mysql_queries.sql", result.Source)
+		assert.Empty(t, result.SQL)
+		assert.NotEmpty(t, out.String())
+
+		_, err = AnalyzeInputTo("../../testdata", &out, config.InputConfig{}, sqlParser, checkers)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "AnalyzeDirectoryStream")
+	})
+}
+
+// TestAnalyzeDirectory 测试目录逐文件分析与镜像输出
+func TestAnalyzeDirectory(t *testing.T) {
+	sqlParser := sqlparser.NewSQLParser()
+	factory, err := NewAnalyzerFactory("")
+	require.NoError(t, err)
+	checkers, err := factory.CreateCheckers("datatype")
+	require.NoError(t, err)
+
+	inputDir := t.TempDir()
+	files := map[string]string{
+		"a.sql":          "CREATE TABLE a (id TINYINT)",
+		"sub/b.sql":      "SELECT id FROM b",
+		"sub/broken.sql": "SELEC id FROM c",
+		"notes.txt":      "ignored",
+	}
+	for name, content := range files {
+		path := filepath.Join(inputDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	results, err := AnalyzeDirectory(inputDir, config.InputConfig{}, sqlParser, checkers)
+	require.NoError(t, err)
+	require.Len(t, results, 3, "应为每个受支持的文件生成一个结果")
+
+	bySource := make(map[string]model.AnalysisResult)
+	for _, result := range results {
+		rel, err := filepath.Rel(inputDir, result.Source)
+		require.NoError(t, err)
+		bySource[filepath.ToSlash(rel)] = result
+	}
+
+	assert.NotEmpty(t, bySource["a.sql"].TransformedSQL)
+	assert.NotEmpty(t, bySource["a.sql"].Issues, "TINYINT 应被检测到")
+	assert.NotEmpty(t, bySource["sub/b.sql"].TransformedSQL)
+
+	broken := bySource["sub/broken.sql"]
+	assert.Empty(t, broken.TransformedSQL)
+	require.NotEmpty(t, broken.Issues)
+	assert.Equal(t, "Error", broken.Issues[len(broken.Issues)-1].Checker)
+	for _, issue := range broken.Issues {
+		assert.Equal(t, broken.Source, issue.File, "文件中的问题应记录文件路径")
+	}
+
+	t.Run("mirrored_output", func(t *testing.T) {
+		outputDir := t.TempDir()
+		saved, err := report.SaveTransformedSQLTree(results, inputDir, outputDir)
+		require.NoError(t, err)
+		assert.Len(t, saved, 2, "解析失败的文件不应输出")
+		assert.FileExists(t, filepath.Join(outputDir, "a_transformed.sql"))
+		assert.FileExists(t, filepath.Join(outputDir, "sub", "b_transformed.sql"))
+	})
+
+	t.Run("stream_output", func(t *testing.T) {
+		outputDir := t.TempDir()
+		tree := report.NewTransformedSQLTree(inputDir, outputDir)
+		streamed, err := AnalyzeDirectoryStream(inputDir, func(path string) (io.WriteCloser, error) {
+			return tree.Open(path)
+		}, config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+		require.Len(t, streamed, 3)
+		assert.Len(t, tree.Saved(), 2, "解析失败的文件不应输出")
+
+		content, err := os.ReadFile(filepath.Join(outputDir, "a_transformed.sql"))
+		require.NoError(t, err)
+		assert.Equal(t, bySource["a.sql"].TransformedSQL, string(content))
+		assert.NoFileExists(t, filepath.Join(outputDir, "sub", "broken_transformed.sql"))
+	})
+
+	t.Run("same_base_name", func(t *testing.T) {
+		// 同名不同扩展名的文件分别输出，去除扩展名后仍然重名的文件报告冲突而不覆盖
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.sql"), []byte("SELECT 1 FROM a"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.log"),
+			[]byte("2024-01-15T10:30:45.123456Z\t   10 Query\tSELECT 2 FROM a\n"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.sql.gz"), gzipContent(t, "SELECT 3 FROM a;\n"), 0600))
+
+		outputDir := t.TempDir()
+		tree := report.NewTransformedSQLTree(dir, outputDir)
+		streamed, err := AnalyzeDirectoryStream(dir, func(path string) (io.WriteCloser, error) {
+			return tree.Open(path)
+		}, config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+		require.Len(t, streamed, 3)
+		assert.Len(t, tree.Saved(), 2)
+
+		content, err := os.ReadFile(filepath.Join(outputDir, "a_transformed.sql"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "SELECT 1 FROM a")
+		content, err = os.ReadFile(filepath.Join(outputDir, "a.log_transformed.sql"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "SELECT 2 FROM a")
+
+		conflict := streamed[2]
+		assert.Equal(t, filepath.Join(dir, "a.sql.gz"), conflict.Source)
+		require.NotEmpty(t, conflict.Issues)
+		assert.Contains(t, conflict.Issues[len(conflict.Issues)-1].Message, "为避免覆盖已跳过")
+	})
+
+	t.Run("not_a_directory", func(t *testing.T) {
+		_, err := AnalyzeDirectory(filepath.Join(inputDir, "a.sql"), config.InputConfig{}, sqlParser, checkers)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "不是目录")
+	})
+}
+
+// gzipContent 压缩测试内容
+func gzipContent(t *testing.T, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := io.WriteString(w, content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// TestAnalyzeDirectory_Archives 测试压缩文件与归档成员的透明分析
+func TestAnalyzeDirectory_Archives(t *testing.T) {
+	sqlParser := sqlparser.NewSQLParser()
+	factory, err := NewAnalyzerFactory("")
+	require.NoError(t, err)
+	checkers, err := factory.CreateCheckers("datatype")
+	require.NoError(t, err)
+
+	inputDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "dump.sql.gz"),
+		gzipContent(t, "CREATE TABLE a (id TINYINT);\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "general.log.1.gz"), gzipContent(t,
+		"2024-01-15T10:30:45.123456Z\t   10 Query\tSELECT id FROM t\n"), 0600))
+
+	// 按表导出的 tar.gz，包含一个不受支持的成员
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, member := range []struct{ name, content string }{
+		{"db/users.sql", "CREATE TABLE users (id TINYINT);\n"},
+		{"db/README", "ignored"},
+		{"db/orders.sql", "CREATE TABLE orders (id INT);\n"},
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: member.name, Mode: 0600, Size: int64(len(member.content))}))
+		_, err := io.WriteString(tw, member.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	archive := filepath.Join(inputDir, "archive.tar.gz")
+	require.NoError(t, os.WriteFile(archive, buf.Bytes(), 0600))
+
+	results, err := AnalyzeDirectory(inputDir, config.InputConfig{}, sqlParser, checkers)
+	require.NoError(t, err)
+
+	var sources []string
+	bySource := make(map[string]model.AnalysisResult)
+	for _, result := range results {
+		sources = append(sources, result.Source)
+		bySource[result.Source] = result
+	}
+	usersPath := archive + inputparser.VirtualPathSeparator + "db/users.sql"
+	assert.Equal(t, []string{
+		usersPath,
+		archive + inputparser.VirtualPathSeparator + "db/orders.sql",
+		filepath.Join(inputDir, "dump.sql.gz"),
+		filepath.Join(inputDir, "general.log.1.gz"),
+	}, sources, "归档成员按归档内顺序排列，不受支持的成员被跳过")
+
+	users := bySource[usersPath]
+	assert.Contains(t, users.TransformedSQL, "CREATE TABLE users")
+	require.NotEmpty(t, users.Issues, "TINYINT 应被检测到")
+	assert.Equal(t, usersPath, users.Issues[0].File)
+	assert.Equal(t, 1, users.Issues[0].Line)
+
+	assert.NotEmpty(t, bySource[filepath.Join(inputDir, "dump.sql.gz")].Issues)
+	assert.Contains(t, bySource[filepath.Join(inputDir, "general.log.1.gz")].SQL, "SELECT id FROM t", "轮转后压缩的日志按 general log 解析")
+
+	t.Run("analyze_input_virtual_path", func(t *testing.T) {
+		result, err := AnalyzeInput(usersPath, config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+		assert.Equal(t, usersPath, result.Source)
+		assert.Equal(t, users.TransformedSQL, result.TransformedSQL)
+	})
+
+	t.Run("analyze_input_archive", func(t *testing.T) {
+		// 归档包含多个文件，不合并为单个结果
+		result, err := AnalyzeInput(archive, config.InputConfig{}, sqlParser, checkers)
+		require.Error(t, err)
+		assert.Equal(t, archive, result.Source)
+		assert.Contains(t, err.Error(), "AnalyzeDirectory")
+
+		_, err = AnalyzeInput(inputDir, config.InputConfig{}, sqlParser, checkers)
+		require.Error(t, err)
+	})
+
+	t.Run("stream_output", func(t *testing.T) {
+		outputDir := t.TempDir()
+		tree := report.NewTransformedSQLTree(inputDir, outputDir)
+		_, err := AnalyzeDirectoryStream(inputDir, func(path string) (io.WriteCloser, error) {
+			return tree.Open(path)
+		}, config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(outputDir, "archive.tar.gz", "db", "users_transformed.sql"))
+		assert.FileExists(t, filepath.Join(outputDir, "dump_transformed.sql"))
+	})
+
+	t.Run("rotated_logs", func(t *testing.T) {
+		// 轮转后的日志保留轮转序号，压缩与未压缩的成员分别输出
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "general.log.1"),
+			[]byte("2024-01-15T10:30:45.123456Z\t   10 Query\tSELECT 1 FROM t\n"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "general.log.2.gz"), gzipContent(t,
+			"2024-01-14T10:30:45.123456Z\t   10 Query\tSELECT 2 FROM t\n"), 0600))
+
+		outputDir := t.TempDir()
+		tree := report.NewTransformedSQLTree(dir, outputDir)
+		streamed, err := AnalyzeDirectoryStream(dir, func(path string) (io.WriteCloser, error) {
+			return tree.Open(path)
+		}, config.InputConfig{}, sqlParser, checkers)
+		require.NoError(t, err)
+		require.Len(t, streamed, 2)
+		assert.Len(t, tree.Saved(), 2, "两个轮转日志都应输出")
+
+		content, err := os.ReadFile(filepath.Join(outputDir, "general.log.1_transformed.sql"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "SELECT 1 FROM t")
+		content, err = os.ReadFile(filepath.Join(outputDir, "general.log.2_transformed.sql"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "SELECT 2 FROM t")
+	})
+}
+
+// TestSQLAnalyzer_AnalyzeStream 测试逐条读取、分析并输出语句
+func TestSQLAnalyzer_AnalyzeStream(t *testing.T) {
+	factory, err := NewAnalyzerFactory("")
+	require.NoError(t, err)
+	checkers, err := factory.CreateCheckers("datatype", "function")
+	require.NoError(t, err)
+	analyzer, err := NewSQLAnalyzer(inputparser.NewStringParser(), sqlparser.NewSQLParser(), checkers)
+	require.NoError(t, err)
+
+	// 超过 64KB 的单行 INSERT 与其后的语句
+	values := strings.Repeat("(1,'x'),", 10000) + "(2,'y')"
+	input := "CREATE TABLE t (id TINYINT, name VARCHAR(10));\n" +
+		"INSERT INTO t VALUES " + values + ";\n" +
+		"SELEC broken;\n" +
+		"SELECT IFNULL(name, '') FROM t;\n"
+
+	var out strings.Builder
+	result, err := analyzer.AnalyzeStream(strings.NewReader(input), "dump.sql", &out)
+	require.NoError(t, err)
+	assert.Empty(t, result.SQL, "流式分析不保留原始 SQL")
+	assert.Empty(t, result.TransformedSQL, "转换结果写入输出而不是保留在结果中")
+
+	// 结果与一次性分析相同
+	whole, err := analyzer.AnalyzeSQL(input, "dump.sql")
+	require.NoError(t, err)
+	assert.Equal(t, whole.TransformedSQL, out.String())
+	assert.Equal(t, whole.Issues, result.Issues)
+
+	lines := make(map[int]string)
+	for _, issue := range result.Issues {
+		lines[issue.Line] = issue.Checker
+	}
+	assert.Equal(t, "SQLParser", lines[3])
+	assert.Equal(t, "FunctionChecker", lines[4])
+	assert.Equal(t, 2, strings.Count(out.String(), ";\n"), "三条成功解析的语句之间用分号分隔")
+
+	t.Run("repeated_statements", func(t *testing.T) {
+		// 同构语句的相同问题聚合为一条，记录首次出现的位置与出现次数
+		input := strings.Repeat("SELECT IFNULL(name, '') FROM t WHERE id = 1;\n", 1000) +
+			"SELECT IFNULL(name, 'x') FROM t WHERE id = 2 AND name = 'a';\n"
+		result, err := analyzer.AnalyzeStream(strings.NewReader(input), "dump.sql", nil)
+		require.NoError(t, err)
+		require.Len(t, result.Issues, 2)
+		assert.Equal(t, 1, result.Issues[0].Line)
+		assert.Equal(t, 1000, result.Issues[0].Executions)
+		assert.NotEmpty(t, result.Issues[0].Fingerprint)
+		assert.Equal(t, 1001, result.Issues[1].Line)
+		assert.Zero(t, result.Issues[1].Executions, "只出现一次的问题不附加执行次数")
+	})
+
+	t.Run("read_error", func(t *testing.T) {
+		_, err := analyzer.AnalyzeStream(iotest.ErrReader(errors.New("boom")), "dump.sql", nil)
+		var analysisErr *model.AnalysisError
+		require.ErrorAs(t, err, &analysisErr)
+		assert.Equal(t, model.ErrorTypeFile, analysisErr.Type)
+	})
 }
 
 func TestAnalyzeFile_UnsupportedFileType(t *testing.T) {
@@ -359,11 +1037,11 @@ func TestAnalyzeFile_UnsupportedFileType(t *testing.T) {
 
 	t.Run("unsupported_file_extension", func(t *testing.T) {
 		// 测试不支持的文件扩展名
-		result, err := analyzeFile("test.xyz", sqlParser, checkers)
+		result, err := analyzeFile("test.xyz", config.InputConfig{}, sqlParser, checkers)
 		require.Error(t, err)
 
 		assert.Equal(t, "test.xyz", result.Source)
-		assert.Contains(t, err.Error(), "不支持的文件类型: .xyz，仅支持 .sql 和 .log 文件")
+		assert.Contains(t, err.Error(), "不支持的文件类型: .xyz，仅支持 .sql、.log、MyBatis mapper .xml 和语句摘要导出 .csv、.json 文件")
 	})
 }
 
@@ -0,0 +1,407 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+
+	"github.com/example/ybMigration/internal/checker"
+	inputparser "github.com/example/ybMigration/internal/input-parser"
+	"github.com/example/ybMigration/internal/model"
+	sqlemitter "github.com/example/ybMigration/internal/sql-emitter"
+	sqlparser "github.com/example/ybMigration/internal/sql-parser"
+)
+
+// AnalyzeStream 从 io.Reader 中逐条读取、分析语句，并将转换结果逐条写入 out
+// 每条语句读取后立即完成解析、检查和转换，内存中只保留当前语句与按语句指纹聚合的问题，
+// 适合分析多 GB 的 mysqldump 文件。
+// 参数:
+//   - r: SQL 输入
+//   - source: SQL 来源标识（文件路径等）
+//   - out: 转换后 SQL 的输出位置，语句之间用分号和换行符分隔，为 nil 时丢弃
+//
+// 返回值:
+//   - model.AnalysisResult: 分析结果，不包含原始 SQL 与转换后的 SQL
+//   - error: 错误处理策略与 AnalyzeSQL 相同；读取输入或写入输出失败时返回 ErrorTypeFile 类型的 AnalysisError
+func (a *SQLAnalyzer) AnalyzeStream(r io.Reader, source string, out io.Writer) (model.AnalysisResult, error) {
+	s := a.newStatementStream(source, out)
+	s.aggregate = make(map[issueGroupKey]int)
+	scanner := sqlparser.NewStatementScanner(r)
+	for scanner.Scan() {
+		s.add(scanner.Statement())
+	}
+	if err := scanner.Err(); err != nil {
+		return model.AnalysisResult{Source: source, Issues: s.issues}, &model.AnalysisError{
+			Type:    model.ErrorTypeFile,
+			Message: "读取输入失败",
+			Source:  source,
+			Cause:   err,
+		}
+	}
+	return s.result()
+}
+
+// AnalyzeSegmentStream 逐个分析输入解析器产生的 SQL 片段，并将转换结果逐条写入 out
+// 片段按语句指纹分组，每个指纹只分析首次出现的片段，之后出现的片段只累计执行次数；
+// 问题的行号换算回原始输入，并附加所在片段的元数据、指纹、累计执行次数和累计耗时；
+// 同一位置的相同问题只报告一次。
+// 参数:
+//   - stream: 片段来源，通常为 inputparser.SegmentStreamer 的 StreamSegments
+//   - source: SQL 来源标识（文件路径等）
+//   - out: 转换后 SQL 的输出位置，为 nil 时丢弃
+//
+// 返回值:
+//   - model.AnalysisResult: 分析结果，不包含原始 SQL 与转换后的 SQL
+//   - error: 片段来源返回的错误，或与 AnalyzeStream 相同的分析错误
+func (a *SQLAnalyzer) AnalyzeSegmentStream(stream func(fn func(inputparser.Segment) error) error, source string, out io.Writer) (model.AnalysisResult, error) {
+	return a.analyzeSegmentStream(stream, source, out, nil)
+}
+
+// analyzeSegmentStream 逐个分析 SQL 片段，echo 不为 nil 时写入实际分析的片段文本
+func (a *SQLAnalyzer) analyzeSegmentStream(stream func(fn func(inputparser.Segment) error) error, source string, out, echo io.Writer) (model.AnalysisResult, error) {
+	var (
+		s        = a.newStatementStream(source, out)
+		groups   []*fingerprintGroup
+		index    = make(map[string]*fingerprintGroup)
+		owners   []*fingerprintGroup // 与 s.issues 一一对应的片段分组
+		reported = make(map[issueKey]bool)
+	)
+
+	err := stream(func(seg inputparser.Segment) error {
+		_, fingerprint := sqlparser.Fingerprint(seg.SQL)
+		g, ok := index[fingerprint]
+		if !ok {
+			g = &fingerprintGroup{segment: seg, fingerprint: fingerprint}
+			index[fingerprint] = g
+			groups = append(groups, g)
+		}
+		if seg.Deferred() {
+			g.deferred = append(g.deferred, seg)
+		} else {
+			g.executions += seg.Executions()
+		}
+		g.latency += seg.Latency()
+		if ok {
+			return nil
+		}
+
+		if echo != nil {
+			sql, _ := inputparser.JoinSegments([]inputparser.Segment{seg})
+			if _, err := io.WriteString(echo, sql); err != nil {
+				return err
+			}
+		}
+		before := len(s.issues)
+		for _, stmt := range sqlparser.SplitStatements(seg.SQL) {
+			// 语句行号相对于片段，换算为原始输入中的行号
+			stmt.Line += seg.Line - 1
+			s.add(stmt)
+		}
+		// 同一位置的相同问题只保留第一次（如 MyBatis 语句的多种展开方式）
+		kept := s.issues[:before]
+		for _, issue := range s.issues[before:] {
+			key := issueKey{issue.Line, issue.Column, issue.Checker, issue.Message}
+			if reported[key] {
+				continue
+			}
+			reported[key] = true
+			issue.Meta = seg.Meta
+			issue.Fingerprint = fingerprint
+			kept = append(kept, issue)
+			owners = append(owners, g)
+		}
+		s.issues = kept
+		return nil
+	})
+
+	// 执行次数在读取完所有片段后才能确定
+	for i, g := range owners {
+		s.issues[i].Executions = g.total()
+		s.issues[i].TotalLatency = g.latency
+	}
+
+	if err != nil {
+		return model.AnalysisResult{Source: source, Issues: s.issues}, fmt.Errorf("解析输入失败: %w", err)
+	}
+	return s.result()
+}
+
+// analyzeStatementStream 逐条分析输入解析器预处理后的语句
+// 每条语句的问题附加解析器提供的元数据，并与 AnalyzeStream 一样按语句指纹聚合；
+// echo 不为 nil 时写入预处理后的语句文本。
+func (a *SQLAnalyzer) analyzeStatementStream(stream func(fn func(sqlparser.Statement, map[string]string) error) error, source string, out, echo io.Writer) (model.AnalysisResult, error) {
+	s := a.newStatementStream(source, out)
+	s.aggregate = make(map[issueGroupKey]int)
+	err := stream(func(stmt sqlparser.Statement, meta map[string]string) error {
+		if echo != nil {
+			if _, err := io.WriteString(echo, inputparser.FormatStatement(stmt.Text)); err != nil {
+				return err
+			}
+		}
+		before := len(s.issues)
+		s.add(stmt)
+		for i := before; i < len(s.issues); i++ {
+			s.issues[i].Meta = meta
+		}
+		return nil
+	})
+	if err != nil {
+		return model.AnalysisResult{Source: source, Issues: s.issues}, fmt.Errorf("解析输入失败: %w", err)
+	}
+	return s.result()
+}
+
+// issueKey 判断问题是否重复的键：位置、检查器和描述
+type issueKey struct {
+	line, column int
+	checker      string
+	message      string
+}
+
+// issueGroupKey 聚合问题的键：语句指纹、检查器和描述
+type issueGroupKey struct {
+	fingerprint string
+	checker     string
+	message     string
+}
+
+// fingerprintGroup 指纹相同的一组片段
+type fingerprintGroup struct {
+	segment     inputparser.Segment   // 首次出现的片段，作为该组的分析对象
+	fingerprint string                // 语句指纹
+	executions  int                   // 组内片段的累计执行次数
+	latency     float64               // 组内片段的累计执行耗时（秒）
+	deferred    []inputparser.Segment // 执行次数由后续记录累计的片段（预处理语句）
+}
+
+// total 返回组内片段的累计执行次数
+func (g *fingerprintGroup) total() int {
+	n := g.executions
+	for _, seg := range g.deferred {
+		n += seg.Executions()
+	}
+	return n
+}
+
+// statementStream 逐条分析语句的过程状态
+type statementStream struct {
+	analyzer *SQLAnalyzer
+	source   string
+	out      io.Writer
+
+	index     int  // 已读取的语句数量
+	parsed    int  // 解析成功的语句节点数量
+	written   bool // 是否已输出过转换结果
+	issues    []model.Issue
+	aggregate map[issueGroupKey]int // 按语句指纹聚合时相同问题在 issues 中的下标，为 nil 时不聚合
+	parseErr  error                 // 第一条解析失败语句的错误
+	writeErr  error                 // 写入转换结果的错误
+}
+
+// newStatementStream 创建逐条分析的过程状态
+func (a *SQLAnalyzer) newStatementStream(source string, out io.Writer) *statementStream {
+	if out == nil {
+		out = io.Discard
+	}
+	return &statementStream{analyzer: a, source: source, out: out}
+}
+
+// add 分析一条语句并写出转换结果
+// 问题的语句序号与行列号换算为原始输入中的位置后加入结果；
+// 生成转换 SQL 失败时记录为问题，该语句输出为注释，不影响其余语句。
+func (s *statementStream) add(stmt sqlparser.Statement) {
+	s.index++
+	nodes, issues, err := s.analyzer.parseStatement(stmt, s.index, s.source)
+	s.collect(stmt, issues)
+	if err != nil {
+		if s.parseErr == nil {
+			s.parseErr = err
+		}
+		return
+	}
+	if len(nodes) == 0 {
+		return
+	}
+	s.parsed += len(nodes)
+
+	// 使用 checker.Check 进行一次遍历完成分析和转换
+	checkResult := checker.Check(nodes, s.analyzer.checkers...)
+
+	// 生成转换后的SQL，无法输出为 YSQL 的节点作为问题一并返回
+	transformedSQL, emitIssues, err := s.analyzer.generateSQL(checkResult.TransformedStmts, checkResult.Hints)
+	issues = append(checkResult.Issues, emitIssues...)
+	if err != nil {
+		issues = append(issues, model.Issue{
+			Checker:        sqlemitter.EmitterName,
+			Message:        fmt.Sprintf("语句转换失败，已输出为注释，需要人工改写: %v", err),
+			StatementIndex: 1,
+		})
+		transformedSQL = sqlemitter.CommentOut(strings.TrimRight(strings.TrimSpace(stmt.Text), ";"))
+	}
+
+	// 检查器与生成器的问题位置是相对于单条语句的，换算为原始输入中的位置
+	origins := make([]stmtOrigin, len(nodes))
+	for i := range origins {
+		origins[i] = stmtOrigin{index: s.index, stmt: stmt}
+	}
+	locateIssues(issues, origins)
+	s.collect(stmt, issues)
+
+	s.write(transformedSQL)
+}
+
+// collect 将一条语句的问题加入结果
+// 按语句指纹聚合时，指纹、检查器与描述都相同的问题只保留首次出现的位置，重复出现时累计到 Executions，
+// 大量同构语句（如 mysqldump 中的 INSERT）的问题数量不随语句数量增长。
+func (s *statementStream) collect(stmt sqlparser.Statement, issues []model.Issue) {
+	if s.aggregate == nil {
+		s.issues = append(s.issues, issues...)
+		return
+	}
+	if len(issues) == 0 {
+		return
+	}
+	_, fingerprint := sqlparser.Fingerprint(stmt.Text)
+	for _, issue := range issues {
+		key := issueGroupKey{fingerprint, issue.Checker, issue.Message}
+		if i, ok := s.aggregate[key]; ok {
+			first := &s.issues[i]
+			if first.Executions == 0 {
+				first.Fingerprint, first.Executions = fingerprint, 1
+			}
+			first.Executions++
+			continue
+		}
+		s.aggregate[key] = len(s.issues)
+		s.issues = append(s.issues, issue)
+	}
+}
+
+// write 写出一条语句的转换结果，写入失败后不再写入
+func (s *statementStream) write(sql string) {
+	if sql == "" || s.writeErr != nil {
+		return
+	}
+	if s.written {
+		sql = ";\n" + sql
+	}
+	if _, err := io.WriteString(s.out, sql); err != nil {
+		s.writeErr = err
+		return
+	}
+	s.written = true
+}
+
+// result 返回分析结果，错误处理策略与 AnalyzeSQL 相同
+func (s *statementStream) result() (model.AnalysisResult, error) {
+	result := model.AnalysisResult{Source: s.source, Issues: s.issues}
+
+	switch {
+	case s.parsed == 0 && s.parseErr != nil:
+		return result, s.parseErr
+	case s.parsed == 0:
+		return result, &model.AnalysisError{
+			Type:    model.ErrorTypeNoSQL,
+			Message: "未找到有效的 SQL 语句",
+			Source:  s.source,
+		}
+	case s.writeErr != nil:
+		return result, &model.AnalysisError{
+			Type:    model.ErrorTypeFile,
+			Message: "写入转换SQL失败",
+			Source:  s.source,
+			Cause:   s.writeErr,
+		}
+	}
+	return result, nil
+}
+
+// captureResult 补全返回完整结果的接口所需的原始 SQL 与转换后的 SQL
+// 与 AnalyzeSQL 的行为保持一致：出错时不返回转换后的 SQL，错误中缺少 SQL 时补充原始 SQL。
+func captureResult(result model.AnalysisResult, err error, sql, transformedSQL string) (model.AnalysisResult, error) {
+	result.SQL = sql
+	if err == nil {
+		result.TransformedSQL = transformedSQL
+	}
+	var analysisErr *model.AnalysisError
+	if errors.As(err, &analysisErr) && analysisErr.SQL == "" {
+		analysisErr.SQL = sql
+	}
+	return result, err
+}
+
+// stmtOrigin 解析成功的语句在原始输入中的来源
+type stmtOrigin struct {
+	index int                 // 语句在输入中的序号（从 1 开始，包含解析失败的语句）
+	stmt  sqlparser.Statement // 切分出的语句文本及起始位置
+}
+
+// parseStatement 解析一条切分出的语句
+// 参数:
+//   - stmt: 切分出的语句，行列号为原始输入中的位置
+//   - index: 语句在输入中的序号（从 1 开始）
+//   - source: SQL 来源，用于错误信息
+//
+// 返回值:
+//   - []ast.StmtNode: 解析出的语句节点
+//   - []model.Issue: 解析失败或替换占位符后才能解析时产生的带行列号的问题
+//   - error: 解析失败时返回 *model.AnalysisError
+func (a *SQLAnalyzer) parseStatement(stmt sqlparser.Statement, index int, source string) ([]ast.StmtNode, []model.Issue, error) {
+	nodes, err := a.sqlParser.ParseSQL(stmt.Text)
+	if err != nil {
+		// 预处理语句的占位符出现在 TiDB 不接受的位置时，替换为 NULL 后重试
+		if replaced, n := sqlparser.ReplacePlaceholders(stmt.Text, "NULL"); n > 0 {
+			if retried, retryErr := a.sqlParser.ParseSQL(replaced); retryErr == nil {
+				return retried, []model.Issue{{
+					Checker:        "SQLParser",
+					Message:        fmt.Sprintf("语句中的 %d 个 ? 占位符无法直接解析，已替换为 NULL 后分析，转换结果需人工补回参数", n),
+					StatementIndex: index,
+					Line:           stmt.Line,
+					Column:         stmt.Column,
+					Snippet:        sqlparser.Snippet(stmt.Text, 0, sqlparser.ClauseEnd(stmt.Text, 0)),
+				}}, nil
+			}
+		}
+	}
+	if err == nil {
+		return nodes, nil, nil
+	}
+
+	analysisErr := &model.AnalysisError{
+		Type:    model.ErrorTypeParse,
+		Message: "SQL 解析失败",
+		Source:  source,
+		SQL:     stmt.Text,
+		Line:    stmt.Line,
+		Column:  stmt.Column,
+		Cause:   err,
+	}
+	snippet := sqlparser.Snippet(stmt.Text, 0, sqlparser.ClauseEnd(stmt.Text, 0))
+	// 错误位置相对于语句文本，换算为原始文本中的位置
+	if line, column, ok := sqlparser.ParseErrorPosition(err); ok {
+		analysisErr.Line, analysisErr.Column = rebasePosition(stmt, line, column)
+		// TiDB 报告的列号指向出错记号的末尾，片段取该行内容便于定位
+		offset := sqlparser.LineOffset(stmt.Text, line, 1)
+		snippet = sqlparser.Snippet(stmt.Text, offset, sqlparser.ClauseEnd(stmt.Text, offset))
+	}
+
+	return nil, []model.Issue{{
+		Checker:        "SQLParser",
+		Message:        fmt.Sprintf("第 %d 行第 %d 列语句解析失败，已跳过: %v", analysisErr.Line, analysisErr.Column, err),
+		StatementIndex: index,
+		Line:           analysisErr.Line,
+		Column:         analysisErr.Column,
+		Snippet:        snippet,
+	}}, analysisErr
+}
+
+// analyzeReader 流式分析 io.Reader 输入，并在结果中保留原始 SQL 与转换后的 SQL
+// 供 AnalyzeInput 使用，内存占用与输入大小成正比；不需要保留 SQL 时请使用 AnalyzeStream。
+func (a *SQLAnalyzer) analyzeReader(r io.Reader, source string) (model.AnalysisResult, error) {
+	var sql, transformed strings.Builder
+	result, err := a.AnalyzeStream(io.TeeReader(r, &sql), source, &transformed)
+	return captureResult(result, err, sql.String(), transformed.String())
+}
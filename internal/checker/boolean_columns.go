@@ -0,0 +1,264 @@
+package checker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
+
+	"github.com/example/ybMigration/internal/model"
+	sqlemitter "github.com/example/ybMigration/internal/sql-emitter"
+)
+
+// patternBoolean TINYINT(1) 转换为 BOOLEAN 规则的 pattern
+const patternBoolean = "TINYINT(1)"
+
+// checkBooleanColumn 将 TINYINT(1) 列转换为 BOOLEAN
+// TINYINT(1) 在 MySQL 中约定用作布尔标志，转换为规则的目标类型，DEFAULT 0/1 转换为 FALSE/TRUE，
+// 并在表结构中标记该列，供后续 DML 转换 0/1 字面量。默认值不是 0/1 的列不转换，按整数类型处理。
+// 参数:
+//   - node: 列定义节点
+//
+// 返回:
+//   - bool: 列是否按 BOOLEAN 处理
+func (d *DataTypeChecker) checkBooleanColumn(node *ast.ColumnDef) bool {
+	name := node.Name.Name.L
+	schema := d.catalog.lookup(d.table)
+	rule, ok := d.GetRules()[patternBoolean]
+	if !ok || node.Tp.GetType() != mysql.TypeTiny || node.Tp.GetFlen() != 1 {
+		// MODIFY COLUMN 等修改为其他类型的列不再按 BOOLEAN 处理
+		if schema != nil {
+			schema.setBoolean(name, false)
+		}
+		return false
+	}
+	// ALTER TABLE 中的列定义可能被检查两次
+	if _, converted := d.hints.ColumnType(node); converted {
+		return true
+	}
+
+	detail := ""
+	for _, opt := range node.Options {
+		if opt.Tp != ast.ColumnOptionDefaultValue {
+			continue
+		}
+		value, isInt := literalInt(opt.Expr)
+		switch {
+		case !isInt:
+		case value == 0 || value == 1:
+			opt.Expr = boolLiteral(value == 1)
+			detail = fmt.Sprintf("，DEFAULT %d 转换为 %s", value, strings.ToUpper(strconv.FormatBool(value == 1)))
+		default:
+			d.AddIssue(model.Issue{
+				Checker: "DataTypeChecker",
+				Message: fmt.Sprintf("数据类型 %s: 列 %s 的默认值 %d 不是 0/1，不按 BOOLEAN 处理", patternBoolean, node.Name.Name.O, value),
+			})
+			if schema != nil {
+				schema.setBoolean(name, false)
+			}
+			return false
+		}
+	}
+
+	target := sqlemitter.FormatType(rule.Then.Target, node.Tp)
+	d.hints.SetColumnType(node, target)
+	if schema != nil {
+		schema.setBoolean(name, true)
+	}
+	d.AddIssue(model.Issue{
+		Checker: "DataTypeChecker",
+		Message: fmt.Sprintf("数据类型 %s: %s (建议: %s)，列 %s 转换为 %s%s；SUM(col) 等按整数使用该列的表达式需要人工改写",
+			patternBoolean, rule.Description, rule.Then.Target, node.Name.Name.O, target, detail),
+		AutoFix: model.AutoFix{
+			Available: true,
+			Action:    rule.Then.Action,
+			Code:      fmt.Sprintf("%s -> %s", patternBoolean, target),
+		},
+	})
+	return true
+}
+
+// enterStatement 记录语句中出现的表及其别名，用于确定 DML 中的列所属的表
+// 每次 `Check` 只处理一条语句，子查询等嵌套语句沿用最外层语句的记录
+func (d *DataTypeChecker) enterStatement(stmt ast.Node) {
+	if d.scope != nil {
+		return
+	}
+	collector := &tableCollector{tables: make(map[string]string)}
+	stmt.Accept(collector)
+	d.scope = collector.tables
+}
+
+// checkBooleanInsert 转换 INSERT 写入 BOOLEAN 列的 0/1 字面量
+// 未写列名列表时按表结构中的列顺序对应
+func (d *DataTypeChecker) checkBooleanInsert(n *ast.InsertStmt) {
+	schema := d.catalog.lookup(insertTable(n))
+	if schema != nil && len(schema.booleans) > 0 {
+		columns := schema.columns
+		if len(n.Columns) > 0 {
+			columns = make([]string, len(n.Columns))
+			for i, col := range n.Columns {
+				columns[i] = col.Name.L
+			}
+		}
+		for _, row := range n.Lists {
+			for i, expr := range row {
+				if i < len(columns) && schema.booleans[columns[i]] {
+					row[i] = d.booleanValue(schema, columns[i], expr)
+				}
+			}
+		}
+	}
+	d.checkBooleanAssignments(n.OnDuplicate)
+}
+
+// checkBooleanAssignments 转换 SET 子句中赋给 BOOLEAN 列的 0/1 字面量
+func (d *DataTypeChecker) checkBooleanAssignments(assigns []*ast.Assignment) {
+	for _, assign := range assigns {
+		if schema := d.booleanColumn(assign.Column); schema != nil {
+			assign.Expr = d.booleanValue(schema, assign.Column.Name.L, assign.Expr)
+		}
+	}
+}
+
+// checkBooleanComparison 转换 BOOLEAN 列与 0/1 字面量的相等比较，如 is_active = 1 转换为 is_active = TRUE
+func (d *DataTypeChecker) checkBooleanComparison(n *ast.BinaryOperationExpr) {
+	if n.Op != opcode.EQ && n.Op != opcode.NE && n.Op != opcode.NullEQ {
+		return
+	}
+	if col, ok := n.L.(*ast.ColumnNameExpr); ok {
+		if schema := d.booleanColumn(col.Name); schema != nil {
+			n.R = d.booleanValue(schema, col.Name.Name.L, n.R)
+		}
+	}
+	if col, ok := n.R.(*ast.ColumnNameExpr); ok {
+		if schema := d.booleanColumn(col.Name); schema != nil {
+			n.L = d.booleanValue(schema, col.Name.Name.L, n.L)
+		}
+	}
+}
+
+// checkBooleanIn 转换 BOOLEAN 列 IN 列表中的 0/1 字面量
+func (d *DataTypeChecker) checkBooleanIn(n *ast.PatternInExpr) {
+	col, ok := n.Expr.(*ast.ColumnNameExpr)
+	if !ok {
+		return
+	}
+	if schema := d.booleanColumn(col.Name); schema != nil {
+		for i, expr := range n.List {
+			n.List[i] = d.booleanValue(schema, col.Name.Name.L, expr)
+		}
+	}
+}
+
+// booleanColumn 确定列是否为当前语句中某个表的 BOOLEAN 列
+// 带表名（或别名）的列按名称匹配；不带表名的列只在语句中恰好一个已知的表包含该列时匹配。
+// 返回:
+//   - *tableSchema: 列所属的表，不是 BOOLEAN 列或无法确定时返回 nil
+func (d *DataTypeChecker) booleanColumn(col *ast.ColumnName) *tableSchema {
+	if col == nil {
+		return nil
+	}
+	if col.Table.L != "" {
+		if schema := d.catalog.tables[d.scope[col.Table.L]]; schema != nil && schema.booleans[col.Name.L] {
+			return schema
+		}
+		return nil
+	}
+
+	var owner *tableSchema
+	seen := make(map[string]bool)
+	for _, table := range d.scope {
+		schema := d.catalog.tables[table]
+		if seen[table] || schema == nil || !schema.hasColumn(col.Name.L) {
+			continue
+		}
+		seen[table] = true
+		if owner != nil {
+			return nil
+		}
+		owner = schema
+	}
+	if owner != nil && owner.booleans[col.Name.L] {
+		return owner
+	}
+	return nil
+}
+
+// booleanValue 将写入或比较 BOOLEAN 列的 0/1 字面量转换为 FALSE/TRUE
+// 0/1 以外的整数说明该列并不是布尔标志，每列报告一次
+func (d *DataTypeChecker) booleanValue(schema *tableSchema, column string, expr ast.ExprNode) ast.ExprNode {
+	value, ok := literalInt(expr)
+	switch {
+	case !ok:
+		return expr
+	case value == 0 || value == 1:
+		return boolLiteral(value == 1)
+	}
+
+	key := strings.ToLower(schema.name) + "." + column
+	if !d.flagged[key] {
+		d.flagged[key] = true
+		d.AddIssue(model.Issue{
+			Checker: "DataTypeChecker",
+			Message: fmt.Sprintf("数据类型 %s: 列 %s.%s 已转换为 BOOLEAN，但写入或比较了 0/1 以外的值 %d，该列不是布尔标志，需要改回 SMALLINT",
+				patternBoolean, schema.name, column, value),
+		})
+	}
+	return expr
+}
+
+// literalInt 返回整数字面量的值，mysqldump 输出的 '0'、'1' 等数字字符串同样按整数处理
+func literalInt(expr ast.ExprNode) (int64, bool) {
+	v, ok := expr.(ast.ValueExpr)
+	if !ok {
+		return 0, false
+	}
+	switch value := v.GetValue().(type) {
+	case int64:
+		return value, true
+	case uint64:
+		return int64(value), value <= 1<<63-1
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// boolLiteral 创建 TRUE/FALSE 字面量
+func boolLiteral(value bool) ast.ExprNode {
+	n := int64(0)
+	if value {
+		n = 1
+	}
+	literal := ast.NewValueExpr(n, "", "")
+	literal.GetType().AddFlag(mysql.IsBooleanFlag)
+	return literal
+}
+
+// tableCollector 收集语句中出现的表名与别名
+type tableCollector struct {
+	tables map[string]string // 别名或表名（小写）到表名（小写）的映射
+}
+
+// Enter 实现 ast.Visitor 接口
+func (c *tableCollector) Enter(n ast.Node) (ast.Node, bool) {
+	switch node := n.(type) {
+	case *ast.TableSource:
+		if name, ok := node.Source.(*ast.TableName); ok && node.AsName.L != "" {
+			c.tables[node.AsName.L] = name.Name.L
+		}
+	case *ast.TableName:
+		c.tables[node.Name.L] = node.Name.L
+	}
+	return n, false
+}
+
+// Leave 实现 ast.Visitor 接口
+func (c *tableCollector) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
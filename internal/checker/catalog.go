@@ -0,0 +1,225 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+)
+
+// tableKey 表的主键或唯一键
+type tableKey struct {
+	name    string   // 键名，主键为 PRIMARY
+	primary bool     // 是否为主键
+	columns []string // 键的列名（小写）
+}
+
+// String 返回键的描述，如 "主键 (id)"、"唯一键 uk_email (email)"
+func (k tableKey) String() string {
+	if k.primary {
+		return fmt.Sprintf("主键 (%s)", strings.Join(k.columns, ","))
+	}
+	return fmt.Sprintf("唯一键 %s (%s)", k.name, strings.Join(k.columns, ","))
+}
+
+// tableSchema 从 DDL 中收集的表结构
+type tableSchema struct {
+	name     string          // 表名
+	columns  []string        // 列名（小写），按定义顺序
+	keys     []tableKey      // 主键与唯一键，主键在前
+	booleans map[string]bool // 转换为 BOOLEAN 的 TINYINT(1) 列（小写），由 DataTypeChecker 标记
+}
+
+// hasColumn 判断表是否包含指定列，列名为小写
+func (t *tableSchema) hasColumn(name string) bool {
+	for _, col := range t.columns {
+		if col == name {
+			return true
+		}
+	}
+	return false
+}
+
+// setBoolean 标记或取消标记转换为 BOOLEAN 的列
+func (t *tableSchema) setBoolean(name string, boolean bool) {
+	if !boolean {
+		delete(t.booleans, name)
+		return
+	}
+	if t.booleans == nil {
+		t.booleans = make(map[string]bool)
+	}
+	t.booleans[name] = true
+}
+
+// addKey 添加主键或唯一键，表达式索引与前缀索引无法作为冲突目标，忽略
+func (t *tableSchema) addKey(name string, primary bool, parts []*ast.IndexPartSpecification) {
+	key := tableKey{name: name, primary: primary}
+	for _, part := range parts {
+		if part.Column == nil || part.Length > 0 {
+			return
+		}
+		key.columns = append(key.columns, part.Column.Name.L)
+	}
+	if len(key.columns) == 0 {
+		return
+	}
+	if primary {
+		key.name = "PRIMARY"
+		t.dropKey(key.name)
+		t.keys = append([]tableKey{key}, t.keys...)
+		return
+	}
+	if key.name == "" {
+		key.name = key.columns[0]
+	}
+	t.keys = append(t.keys, key)
+}
+
+// dropKey 删除指定名称的键，键名不区分大小写
+func (t *tableSchema) dropKey(name string) {
+	keys := t.keys[:0]
+	for _, key := range t.keys {
+		if !strings.EqualFold(key.name, name) {
+			keys = append(keys, key)
+		}
+	}
+	t.keys = keys
+}
+
+// addColumn 添加列及其列级 PRIMARY KEY、UNIQUE 约束
+func (t *tableSchema) addColumn(col *ast.ColumnDef) {
+	t.columns = append(t.columns, col.Name.Name.L)
+	parts := []*ast.IndexPartSpecification{{Column: col.Name}}
+	for _, opt := range col.Options {
+		switch opt.Tp {
+		case ast.ColumnOptionPrimaryKey:
+			t.addKey("", true, parts)
+		case ast.ColumnOptionUniqKey:
+			t.addKey(col.Name.Name.O, false, parts)
+		}
+	}
+}
+
+// addConstraint 添加表级 PRIMARY KEY、UNIQUE 约束
+func (t *tableSchema) addConstraint(c *ast.Constraint) {
+	switch c.Tp {
+	case ast.ConstraintPrimaryKey:
+		t.addKey("", true, c.Keys)
+	case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		t.addKey(c.Name, false, c.Keys)
+	}
+}
+
+// schemaCatalog 检查器从 DDL 中收集的表结构
+// 分析器逐条语句调用 `Check`，而 `Check` 每次都会重置检查器，
+// 表结构需要跨语句保留，因此不随 Reset 清空。表名不区分大小写，不区分库名。
+//
+// 并发安全:
+//   - 该结构体不是并发安全的，与检查器的 Inspect 一样应在单个 goroutine 中使用
+type schemaCatalog struct {
+	tables map[string]*tableSchema
+}
+
+// newSchemaCatalog 创建空的表结构目录
+func newSchemaCatalog() *schemaCatalog {
+	return &schemaCatalog{tables: make(map[string]*tableSchema)}
+}
+
+// lookup 查找表结构
+// 返回:
+//   - *tableSchema: 表结构，未收集到时返回 nil
+func (c *schemaCatalog) lookup(table *ast.TableName) *tableSchema {
+	if table == nil {
+		return nil
+	}
+	return c.tables[table.Name.L]
+}
+
+// learn 根据 DDL 语句更新表结构
+// 支持 CREATE TABLE（含 LIKE）、ALTER TABLE、CREATE UNIQUE INDEX、DROP TABLE 与 RENAME TABLE，其他节点忽略
+func (c *schemaCatalog) learn(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.CreateTableStmt:
+		c.learnCreateTable(n)
+	case *ast.AlterTableStmt:
+		c.learnAlterTable(n)
+	case *ast.CreateIndexStmt:
+		if t := c.lookup(n.Table); t != nil && n.KeyType == ast.IndexKeyTypeUnique {
+			t.addKey(n.IndexName, false, n.IndexPartSpecifications)
+		}
+	case *ast.DropTableStmt:
+		if !n.IsView {
+			for _, table := range n.Tables {
+				delete(c.tables, table.Name.L)
+			}
+		}
+	case *ast.RenameTableStmt:
+		for _, t2t := range n.TableToTables {
+			c.rename(t2t.OldTable, t2t.NewTable)
+		}
+	}
+}
+
+// learnCreateTable 收集 CREATE TABLE 语句的列与键
+func (c *schemaCatalog) learnCreateTable(n *ast.CreateTableStmt) {
+	if n.Table == nil {
+		return
+	}
+	t := &tableSchema{name: n.Table.Name.O}
+	if n.ReferTable != nil {
+		refer := c.lookup(n.ReferTable)
+		if refer == nil {
+			return
+		}
+		t.columns = append(t.columns, refer.columns...)
+		t.keys = append(t.keys, refer.keys...)
+		for col := range refer.booleans {
+			t.setBoolean(col, true)
+		}
+	}
+	for _, col := range n.Cols {
+		t.addColumn(col)
+	}
+	for _, constraint := range n.Constraints {
+		t.addConstraint(constraint)
+	}
+	c.tables[n.Table.Name.L] = t
+}
+
+// learnAlterTable 收集 ALTER TABLE 语句对列与键的修改
+func (c *schemaCatalog) learnAlterTable(n *ast.AlterTableStmt) {
+	t := c.lookup(n.Table)
+	if t == nil {
+		return
+	}
+	for _, spec := range n.Specs {
+		switch spec.Tp {
+		case ast.AlterTableAddColumns:
+			for _, col := range spec.NewColumns {
+				t.addColumn(col)
+			}
+		case ast.AlterTableAddConstraint:
+			if spec.Constraint != nil {
+				t.addConstraint(spec.Constraint)
+			}
+		case ast.AlterTableDropPrimaryKey:
+			t.dropKey("PRIMARY")
+		case ast.AlterTableDropIndex:
+			t.dropKey(spec.Name)
+		case ast.AlterTableRenameTable:
+			c.rename(n.Table, spec.NewTable)
+		}
+	}
+}
+
+// rename 将表结构登记到新表名下
+func (c *schemaCatalog) rename(from, to *ast.TableName) {
+	t := c.lookup(from)
+	if t == nil || to == nil {
+		return
+	}
+	delete(c.tables, from.Name.L)
+	t.name = to.Name.O
+	c.tables[to.Name.L] = t
+}
@@ -4,8 +4,10 @@ package checker
 import (
 	"fmt"
 	"log"
+	"regexp"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
@@ -13,6 +15,7 @@ import (
 
 	"github.com/example/ybMigration/internal/config"
 	"github.com/example/ybMigration/internal/model"
+	sqlemitter "github.com/example/ybMigration/internal/sql-emitter"
 )
 
 // Checker 检查器接口
@@ -32,6 +35,14 @@ type Checker interface {
 	Reset()
 }
 
+// HintProvider 可选接口：提供转换过程中产生的 YSQL 输出提示
+// AST 无法表达的目标语法（如 SERIAL 类型）由检查器记录在 Hints 中，
+// `Check` 会收集实现了该接口的检查器的提示并交给 SQL 生成器使用。
+type HintProvider interface {
+	// Hints 返回检查器记录的输出提示
+	Hints() *sqlemitter.Hints
+}
+
 // 并发语义说明:
 // - `Check` 在遍历 AST 时会在单个 goroutine 中调用每个检查器的 `Inspect` 方法，
 //   因此 `Inspect` 的实现通常不需要为并发调用提供额外保护（在同一次遍历中是串行调用）。
@@ -44,9 +55,10 @@ type Checker interface {
 // 支持不同类别的SQL兼容性检查，是所有检查器的基础实现
 type RuleChecker struct {
 	name     string                 // 检查器名称
-	category string                 // 规则类别：指定检查器处理的规则类型（datatype/function/syntax/charset）
+	category string                 // 规则类别：指定检查器处理的规则类型（datatype/function/syntax/charset/dml/multitable/trigger/index/sharding）
 	rules    map[string]config.Rule // 规则映射：存储从配置文件加载的规则，key为Pattern的大写形式
 	issues   []model.Issue          // 发现的问题列表
+	hints    *sqlemitter.Hints      // YSQL 输出提示：记录 AST 无法表达的转换结果
 	mu       sync.RWMutex           // 读写锁：保护并发访问 `issues` 字段，保证对问题集合的并发读写安全
 }
 
@@ -68,6 +80,7 @@ func newRuleChecker(name, category string, cfg *config.Config) (*RuleChecker, er
 		name:     name,
 		category: category,
 		issues:   make([]model.Issue, 0),
+		hints:    sqlemitter.NewHints(),
 	}
 
 	// 加载规则
@@ -108,12 +121,19 @@ func (r *RuleChecker) Issues() []model.Issue {
 	return r.issues
 }
 
+// Hints 返回检查器记录的 YSQL 输出提示
+// 实现 HintProvider 接口
+func (r *RuleChecker) Hints() *sqlemitter.Hints {
+	return r.hints
+}
+
 // Reset 重置检查器状态
 // 并发安全: 使用写锁保证在并发场景下清空 `issues` 的原子性。
 func (r *RuleChecker) Reset() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.issues = r.issues[:0] // 清空切片但保留底层数组
+	r.hints.Reset()
 }
 
 // LoadRulesFromConfig 从配置中加载规则
@@ -151,6 +171,13 @@ func (r *RuleChecker) GetRules() map[string]config.Rule {
 	return r.rules
 }
 
+// ENUM/SET 列的转换目标（规则的 then.target），通过配置选择转换方式
+const (
+	TypeTargetEnum    = "CREATE TYPE" // ENUM 列转换为 CREATE TYPE ... AS ENUM 定义的枚举类型
+	TypeTargetVarchar = "VARCHAR"     // 转换为 VARCHAR，ENUM/SET 列附加限制取值范围的 CHECK 约束
+	TypeTargetArray   = "TEXT[]"      // SET 列转换为 TEXT[] 数组
+)
+
 // TableOption 类型常量 (对应 TiDB AST 中的 TableOption.Tp)
 // 这些常量用于标识表选项类型，应在包级别定义以便所有检查器使用
 const (
@@ -172,6 +199,10 @@ var _ ast.Visitor = (*visitor)(nil)
 type visitor struct {
 	checkers     []Checker
 	skipChildren bool // 缓存是否跳过子节点
+
+	stmtIndex int                   // 当前语句序号（从 1 开始）
+	locator   *locator              // 当前语句的节点定位器
+	located   []map[int]model.Issue // 按检查器下标记录已补充位置的问题，key 为问题下标
 }
 
 // Reset 重置访问者状态
@@ -209,21 +240,22 @@ func getCheckerName(c Checker) string {
 //   - bool: 是否跳过子节点遍历
 //
 // 实现细节:
-//  1. 检查节点是否为nil或已标记跳过
+//  1. 检查节点是否为nil
 //  2. 遍历所有检查器处理当前节点
 //  3. 收集检查器的跳过子节点请求
 //
 // 并发安全:
 //   - 该方法不是并发安全的，应该单线程调用
 func (v *visitor) Enter(node ast.Node) (ast.Node, bool) {
-	// 如果节点为nil或已标记跳过子节点，则直接返回
-	if node == nil || v.skipChildren {
+	// 如果节点为nil，则直接返回
+	// 跳过子节点由 Accept 根据返回值处理，不能沿用上一个节点的跳过状态，否则其后的兄弟节点都不会被检查
+	if node == nil {
 		return node, true
 	}
 
 	var skip bool
 	// 遍历所有检查器处理当前节点
-	for _, checker := range v.checkers {
+	for i, checker := range v.checkers {
 		// 添加 defer 保护，防止检查器中的 panic
 		func() {
 			defer func() {
@@ -234,6 +266,9 @@ func (v *visitor) Enter(node ast.Node) (ast.Node, bool) {
 				}
 			}()
 
+			before := len(checker.Issues())
+			defer v.locateIssues(i, before, node)
+
 			if n, s := checker.Inspect(node); n != nil || s {
 				if n != nil {
 					node = n // 替换节点
@@ -247,6 +282,22 @@ func (v *visitor) Enter(node ast.Node) (ast.Node, bool) {
 	return node, skip
 }
 
+// locateIssues 为检查器在当前节点上新产生的问题补充位置信息
+// 参数:
+//   - checkerIndex: 检查器下标
+//   - before: 检查节点之前检查器已有的问题数量
+//   - node: 当前节点
+func (v *visitor) locateIssues(checkerIndex, before int, node ast.Node) {
+	issues := v.checkers[checkerIndex].Issues()
+	if len(issues) <= before || v.located == nil {
+		return
+	}
+	start, end := v.locator.locate(node)
+	for j := before; j < len(issues); j++ {
+		v.located[checkerIndex][j] = v.locator.annotate(issues[j], v.stmtIndex, start, end)
+	}
+}
+
 // Leave 实现 ast.Visitor 接口
 // 当离开节点时调用，返回原始节点而不是 nil
 func (v *visitor) Leave(node ast.Node) (ast.Node, bool) {
@@ -255,8 +306,9 @@ func (v *visitor) Leave(node ast.Node) (ast.Node, bool) {
 
 // CheckResult 检查和转换结果
 type CheckResult struct {
-	Issues           []model.Issue  // 发现的问题
-	TransformedStmts []ast.StmtNode // 转换后的语句
+	Issues           []model.Issue     // 发现的问题
+	TransformedStmts []ast.StmtNode    // 转换后的语句
+	Hints            *sqlemitter.Hints // 检查器提供的 YSQL 输出提示
 }
 
 // Check 检查和转换SQL语句（一次遍历完成所有工作）
@@ -278,7 +330,10 @@ func Check(stmts []ast.StmtNode, checkers ...Checker) CheckResult {
 	}
 
 	// 创建访问者
-	v := &visitor{checkers: checkers}
+	v := &visitor{checkers: checkers, located: make([]map[int]model.Issue, len(checkers))}
+	for i := range v.located {
+		v.located[i] = make(map[int]model.Issue)
+	}
 
 	// 一次遍历AST，同时完成分析和转换
 	transformedStmts := make([]ast.StmtNode, len(stmts))
@@ -289,6 +344,8 @@ func Check(stmts []ast.StmtNode, checkers ...Checker) CheckResult {
 
 		// 重置访问者状态
 		v.Reset()
+		v.stmtIndex = i + 1
+		v.locator = newLocator(stmt)
 
 		// 单次遍历AST，应用所有检查器并支持节点转换
 		newNode, _ := stmt.Accept(v)
@@ -303,17 +360,25 @@ func Check(stmts []ast.StmtNode, checkers ...Checker) CheckResult {
 		}
 	}
 
-	// 收集所有检查器发现的问题
+	// 收集所有检查器发现的问题与输出提示
 	var allIssues []model.Issue
-	for _, checker := range checkers {
-		if issues := checker.Issues(); len(issues) > 0 {
-			allIssues = append(allIssues, issues...)
+	hints := sqlemitter.NewHints()
+	for i, checker := range checkers {
+		for j, issue := range checker.Issues() {
+			if located, ok := v.located[i][j]; ok {
+				issue = located
+			}
+			allIssues = append(allIssues, issue)
+		}
+		if provider, ok := checker.(HintProvider); ok {
+			hints.Merge(provider.Hints())
 		}
 	}
 
 	return CheckResult{
 		Issues:           allIssues,
 		TransformedStmts: transformedStmts,
+		Hints:            hints,
 	}
 }
 
@@ -374,7 +439,17 @@ func (r *RuleChecker) replaceFunction(node ast.Node, rule config.Rule) ast.Node
 func (r *RuleChecker) replaceType(node ast.Node, rule config.Rule) ast.Node {
 	switch n := node.(type) {
 	case *ast.ColumnDef:
-		// 根据目标类型创建新的FieldType
+		// ENUM/SET 的转换需要保留元素列表
+		switch rule.Then.Target {
+		case TypeTargetEnum:
+			return r.replaceWithEnumType(n)
+		case TypeTargetVarchar:
+			return r.replaceWithCheckedVarchar(n)
+		case TypeTargetArray:
+			return r.replaceWithTextArray(n)
+		}
+
+		// 根据目标类型确定新的类型
 		var newTp byte
 		switch rule.Then.Target {
 		case "TINYINT":
@@ -398,9 +473,16 @@ func (r *RuleChecker) replaceType(node ast.Node, rule config.Rule) ast.Node {
 		case "TIMESTAMP":
 			newTp = mysql.TypeTimestamp
 		default:
-			return node
+			// 其他目标视为 YSQL 类型模板（如 BYTEA、TIMESTAMP(${fsp})），AST 无法表达，通过输出提示指定列类型
+			r.hints.SetColumnType(n, sqlemitter.FormatType(rule.Then.Target, n.Tp))
+			return n
 		}
+		// 复制原类型，保留 UNSIGNED 等标志以及长度、精度与小数秒精度
 		newType := types.NewFieldType(newTp)
+		if n.Tp != nil {
+			newType = n.Tp.Clone()
+			newType.SetType(newTp)
+		}
 		n.Tp = newType
 		return n
 	default:
@@ -424,11 +506,9 @@ func (r *RuleChecker) replaceConstraint(node ast.Node, rule config.Rule) ast.Nod
 					}
 				}
 			}
-			// SERIAL 是 PostgreSQL 的自增类型，需要修改数据类型
-			if n.Tp != nil {
-				serialType := types.NewFieldType(mysql.TypeLong)
-				n.Tp = serialType
-			}
+			// SERIAL 是 PostgreSQL 的自增类型，AST 无法表达，通过输出提示指定列类型
+			// 按原整数宽度选择 SMALLSERIAL/SERIAL/BIGSERIAL，避免 BIGINT 被截断为 INT
+			r.hints.SetColumnType(n, serialTypeFor(n.Tp))
 		}
 		return n
 	default:
@@ -436,6 +516,124 @@ func (r *RuleChecker) replaceConstraint(node ast.Node, rule config.Rule) ast.Nod
 	}
 }
 
+// replaceWithEnumType 将 ENUM 列转换为 YSQL 枚举类型
+// 枚举类型无法用 AST 表达，通过输出提示由 SQL 生成器在建表语句之前输出 CREATE TYPE
+func (r *RuleChecker) replaceWithEnumType(n *ast.ColumnDef) ast.Node {
+	if n.Tp == nil || n.Tp.GetType() != mysql.TypeEnum {
+		return n
+	}
+	r.hints.UseEnumType(n)
+	return n
+}
+
+// replaceWithCheckedVarchar 将列转换为 VARCHAR
+// ENUM/SET 列的长度取最长的取值，并附加限制取值范围的 CHECK 约束：
+// ENUM 为 col IN (...)，SET 为匹配逗号分隔元素列表的正则表达式（允许空字符串）
+func (r *RuleChecker) replaceWithCheckedVarchar(n *ast.ColumnDef) ast.Node {
+	if n.Tp == nil {
+		return n
+	}
+	tp, elems := n.Tp.GetType(), n.Tp.GetElems()
+	flen := n.Tp.GetFlen()
+
+	var check ast.ExprNode
+	column := &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: n.Name.Name}}
+	switch {
+	case tp == mysql.TypeEnum && len(elems) > 0:
+		flen = 0
+		values := make([]ast.ExprNode, len(elems))
+		for i, elem := range elems {
+			flen = max(flen, utf8.RuneCountInString(elem))
+			values[i] = ast.NewValueExpr(elem, "", "")
+		}
+		check = &ast.PatternInExpr{Expr: column, List: values}
+	case tp == mysql.TypeSet && len(elems) > 0:
+		flen = len(elems) - 1
+		quoted := make([]string, len(elems))
+		for i, elem := range elems {
+			flen += utf8.RuneCountInString(elem)
+			quoted[i] = regexp.QuoteMeta(elem)
+		}
+		alt := "(" + strings.Join(quoted, "|") + ")"
+		check = &ast.PatternRegexpExpr{Expr: column, Pattern: ast.NewValueExpr("^("+alt+"(,"+alt+")*)?$", "", "")}
+	}
+
+	newType := types.NewFieldType(mysql.TypeVarchar)
+	newType.SetFlen(max(flen, 1))
+	n.Tp = newType
+	if check != nil {
+		n.Options = append(n.Options, &ast.ColumnOption{Tp: ast.ColumnOptionCheck, Expr: check, Enforced: true})
+	}
+	return n
+}
+
+// replaceWithTextArray 将 SET 列转换为 TEXT[]
+// 逗号分隔的默认值同时转换为数组字面量，如 'a,b' 转换为 '{"a","b"}'
+func (r *RuleChecker) replaceWithTextArray(n *ast.ColumnDef) ast.Node {
+	if n.Tp == nil || n.Tp.GetType() != mysql.TypeSet {
+		return n
+	}
+	r.hints.SetColumnType(n, TypeTargetArray)
+	for _, opt := range n.Options {
+		if opt.Tp != ast.ColumnOptionDefaultValue {
+			continue
+		}
+		if v, ok := opt.Expr.(ast.ValueExpr); ok && v.GetValue() != nil {
+			opt.Expr = ast.NewValueExpr(setArrayLiteral(v.GetString()), "", "")
+		}
+	}
+	return n
+}
+
+// setArrayLiteral 将 SET 列的逗号分隔值转换为数组字面量
+func setArrayLiteral(value string) string {
+	if value == "" {
+		return "{}"
+	}
+	elems := strings.Split(value, ",")
+	for i, elem := range elems {
+		elems[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(elem) + `"`
+	}
+	return "{" + strings.Join(elems, ",") + "}"
+}
+
+// serialTypeFor 根据原整数类型选择对应的 SERIAL 类型，UNSIGNED 列选择更大一级的类型
+func serialTypeFor(tp *types.FieldType) string {
+	if tp == nil {
+		return "SERIAL"
+	}
+	unsigned := mysql.HasUnsignedFlag(tp.GetFlag())
+	switch tp.GetType() {
+	case mysql.TypeTiny:
+		return "SMALLSERIAL"
+	case mysql.TypeShort:
+		if unsigned {
+			return "SERIAL"
+		}
+		return "SMALLSERIAL"
+	case mysql.TypeLong:
+		if unsigned {
+			return "BIGSERIAL"
+		}
+		return "SERIAL"
+	case mysql.TypeLonglong, mysql.TypeNewDecimal:
+		// BIGINT UNSIGNED 扩宽后为 NUMERIC(20)，自增列只能使用 BIGSERIAL
+		return "BIGSERIAL"
+	default:
+		return "SERIAL"
+	}
+}
+
+// hasAutoIncrement 判断列定义是否带有 AUTO_INCREMENT 选项
+func hasAutoIncrement(col *ast.ColumnDef) bool {
+	for _, opt := range col.Options {
+		if opt.Tp == ast.ColumnOptionAutoIncrement {
+			return true
+		}
+	}
+	return false
+}
+
 // replaceQuotes 替换引号
 func (r *RuleChecker) replaceQuotes(node ast.Node, _ config.Rule) ast.Node {
 	switch n := node.(type) {
@@ -465,8 +663,8 @@ func (r *RuleChecker) replaceClause(node ast.Node, _ config.Rule) ast.Node {
 	switch n := node.(type) {
 	case *ast.Limit:
 		// 处理 LIMIT 子句转换为 OFFSET FETCH
-		// 这里需要创建新的节点结构，暂时返回原节点
-		// 实际实现需要根据具体的SQL语法来构造新的AST节点
+		// AST 中没有 OFFSET ... FETCH 节点，通过输出提示交给 SQL 生成器
+		r.hints.UseOffsetFetch(n)
 		return n
 	default:
 		return node
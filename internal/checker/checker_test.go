@@ -3,6 +3,7 @@ package checker
 import (
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"testing"
 
@@ -13,6 +14,8 @@ import (
 
 	"github.com/example/ybMigration/internal/config"
 	"github.com/example/ybMigration/internal/model"
+	sqlemitter "github.com/example/ybMigration/internal/sql-emitter"
+	sqlparser "github.com/example/ybMigration/internal/sql-parser"
 	"github.com/example/ybMigration/internal/testutils"
 )
 
@@ -273,6 +276,221 @@ func TestDataTypeChecker(t *testing.T) {
 	})
 }
 
+func TestDataTypeChecker_EnumSet(t *testing.T) {
+	// emit 检查并输出建表语句
+	emit := func(t *testing.T, cfg *config.Config, sql string) (string, []model.Issue) {
+		t.Helper()
+		c, err := NewDataTypeChecker(cfg)
+		require.NoError(t, err)
+		stmts, err := sqlparser.NewSQLParser().ParseSQL(sql)
+		require.NoError(t, err)
+		result := Check(stmts, c)
+		out, _, err := sqlemitter.NewEmitter().Emit(result.TransformedStmts, result.Hints)
+		require.NoError(t, err)
+		return out, result.Issues
+	}
+	rules := func(enumTarget, setTarget string) *config.Config {
+		return &config.Config{Rules: []config.Rule{
+			{Name: "enum", Category: "datatype", When: config.RuleCondition{Pattern: "ENUM"}, Then: config.RuleAction{Action: "replace_type", Target: enumTarget}},
+			{Name: "set", Category: "datatype", When: config.RuleCondition{Pattern: "SET"}, Then: config.RuleAction{Action: "replace_type", Target: setTarget}},
+		}}
+	}
+	sql := "CREATE TABLE users (status ENUM('active','banned') DEFAULT 'active', tags SET('a','b.c') DEFAULT 'a,b.c')"
+
+	t.Run("default_config", func(t *testing.T) {
+		out, issues := emit(t, testutils.GetTestConfig(t), sql)
+		assert.Equal(t, "CREATE TYPE users_status AS ENUM ('active','banned');\n"+
+			"CREATE TABLE users (status users_status DEFAULT 'active',tags TEXT[] DEFAULT '{\"a\",\"b.c\"}')", out)
+		// 每列一条转换问题与一条语义差异提示
+		require.Len(t, issues, 4)
+		assert.Contains(t, issues[1].Message, "列 status 转换为枚举类型后仍按元素定义顺序排序")
+		assert.Contains(t, issues[3].Message, "列 tags 转换为 TEXT[]")
+	})
+
+	t.Run("checked_varchar", func(t *testing.T) {
+		out, issues := emit(t, rules(TypeTargetVarchar, TypeTargetVarchar), sql)
+		assert.Equal(t, "CREATE TABLE users (status VARCHAR(6) DEFAULT 'active' CHECK (status IN ('active','banned')),"+
+			"tags VARCHAR(5) DEFAULT 'a,b.c' CHECK (tags ~ '^((a|b\\.c)(,(a|b\\.c))*)?$'))", out)
+		require.Len(t, issues, 4)
+		assert.Contains(t, issues[1].Message, "ORDER BY 按字符串排序而不是元素定义顺序")
+	})
+
+	t.Run("mismatched_target_keeps_column", func(t *testing.T) {
+		// SET 专用的 TEXT[] 目标不改变 ENUM 列，由 SQL 生成器按 TEXT 输出并记录问题
+		out, _ := emit(t, rules(TypeTargetArray, TypeTargetArray), "CREATE TABLE t (status ENUM('a'))")
+		assert.Equal(t, "CREATE TABLE t (status TEXT)", out)
+	})
+}
+
+func TestDataTypeChecker_IntegerAttributes(t *testing.T) {
+	// emit 检查并输出建表语句
+	emit := func(t *testing.T, cfg *config.Config, sql string) (string, []model.Issue) {
+		t.Helper()
+		c, err := NewDataTypeChecker(cfg)
+		require.NoError(t, err)
+		stmts, err := sqlparser.NewSQLParser().ParseSQL(sql)
+		require.NoError(t, err)
+		result := Check(stmts, c)
+		out, _, err := sqlemitter.NewEmitter().Emit(result.TransformedStmts, result.Hints)
+		require.NoError(t, err)
+		return out, result.Issues
+	}
+	sql := "CREATE TABLE t (a TINYINT UNSIGNED, b INT(10) UNSIGNED ZEROFILL, c BIGINT(20) UNSIGNED, d INT)"
+
+	t.Run("default_config", func(t *testing.T) {
+		out, issues := emit(t, testutils.GetTestConfig(t), sql)
+		assert.Equal(t, "CREATE TABLE t (a SMALLINT,b BIGINT,c NUMERIC(20),d INTEGER)", out)
+
+		codes := make([]string, len(issues))
+		for i, issue := range issues {
+			codes[i] = issue.AutoFix.Code
+		}
+		// 扩宽后的 SMALLINT 不再匹配 TINYINT 规则
+		assert.Equal(t, []string{
+			"TINYINT UNSIGNED -> SMALLINT",
+			"INT ZEROFILL -> INT", "INT(10) -> INT", "INT UNSIGNED -> BIGINT",
+			"BIGINT(20) -> BIGINT", "BIGINT UNSIGNED -> NUMERIC(20)",
+		}, codes)
+		assert.Contains(t, issues[3].Message, "列 b 转换为 BIGINT")
+	})
+
+	t.Run("unsigned_check", func(t *testing.T) {
+		cfg := *testutils.GetTestConfig(t)
+		cfg.DataType.UnsignedCheck = true
+		out, _ := emit(t, &cfg, "CREATE TABLE t (id INT UNSIGNED NOT NULL)")
+		assert.Equal(t, "CREATE TABLE t (id BIGINT NOT NULL CHECK (id>=0))", out)
+	})
+
+	t.Run("auto_increment", func(t *testing.T) {
+		// 问题描述的类型与输出一致，与检查器的执行顺序无关
+		cfg := testutils.GetTestConfig(t)
+		sql := "CREATE TABLE t (id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY)"
+		for _, order := range []string{"datatype_first", "syntax_first"} {
+			t.Run(order, func(t *testing.T) {
+				dataType, err := NewDataTypeChecker(cfg)
+				require.NoError(t, err)
+				syntax, err := NewSyntaxChecker(cfg)
+				require.NoError(t, err)
+				checkers := []Checker{dataType, syntax}
+				if order == "syntax_first" {
+					checkers = []Checker{syntax, dataType}
+				}
+				stmts, err := sqlparser.NewSQLParser().ParseSQL(sql)
+				require.NoError(t, err)
+				result := Check(stmts, checkers...)
+				out, _, err := sqlemitter.NewEmitter().Emit(result.TransformedStmts, result.Hints)
+				require.NoError(t, err)
+				assert.Equal(t, "CREATE TABLE t (id BIGSERIAL NOT NULL PRIMARY KEY)", out)
+
+				var codes []string
+				for _, issue := range result.Issues {
+					codes = append(codes, issue.AutoFix.Code)
+				}
+				assert.Contains(t, codes, "BIGINT UNSIGNED -> BIGSERIAL")
+			})
+		}
+
+		// 没有 SERIAL 规则时自增列输出为 IDENTITY，BIGINT UNSIGNED 保留为 BIGINT
+		noSerial := &config.Config{}
+		for _, rule := range cfg.Rules {
+			if rule.Category == "datatype" {
+				noSerial.Rules = append(noSerial.Rules, rule)
+			}
+		}
+		out, issues := emit(t, noSerial, sql)
+		assert.Equal(t, "CREATE TABLE t (id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY)", out)
+		require.NotEmpty(t, issues)
+		assert.Equal(t, "BIGINT UNSIGNED -> BIGINT", issues[0].AutoFix.Code)
+	})
+
+	t.Run("type_rule_keeps_flags", func(t *testing.T) {
+		// 没有 UNSIGNED 规则时，类型规则保留 UNSIGNED 标志，由 SQL 生成器扩宽
+		cfg := &config.Config{Rules: []config.Rule{
+			{Name: "int", Category: "datatype", When: config.RuleCondition{Pattern: "INT"}, Then: config.RuleAction{Action: "replace_type", Target: "BIGINT"}},
+		}}
+		out, _ := emit(t, cfg, "CREATE TABLE t (id INT UNSIGNED)")
+		assert.Equal(t, "CREATE TABLE t (id NUMERIC(20))", out)
+	})
+}
+
+func TestDataTypeChecker_TypeMapping(t *testing.T) {
+	emit := func(t *testing.T, cfg *config.Config, sql string) string {
+		t.Helper()
+		c, err := NewDataTypeChecker(cfg)
+		require.NoError(t, err)
+		stmts, err := sqlparser.NewSQLParser().ParseSQL(sql)
+		require.NoError(t, err)
+		result := Check(stmts, c)
+		out, _, err := sqlemitter.NewEmitter().Emit(result.TransformedStmts, result.Hints)
+		require.NoError(t, err)
+		return out
+	}
+
+	t.Run("config_override", func(t *testing.T) {
+		cfg := *testutils.GetTestConfig(t)
+		cfg.DataType.TypeMapping = map[string]string{"DATETIME": "TIMESTAMPTZ(${fsp})"}
+		assert.Equal(t, "CREATE TABLE t (a TIMESTAMPTZ(6),b NUMERIC(18,4))",
+			emit(t, &cfg, "CREATE TABLE t (a DATETIME(6), b DECIMAL(18,4))"))
+	})
+
+	t.Run("invalid_override", func(t *testing.T) {
+		cfg := *testutils.GetTestConfig(t)
+		cfg.DataType.TypeMapping = map[string]string{"DATETIM": "TIMESTAMP"}
+		_, err := NewDataTypeChecker(&cfg)
+		assert.ErrorContains(t, err, "未知的 MySQL 类型: DATETIM")
+	})
+
+	t.Run("rule_target_template", func(t *testing.T) {
+		// 不在 AST 类型列表中的规则目标作为 YSQL 类型模板输出
+		cfg := &config.Config{Rules: []config.Rule{
+			{Name: "decimal", Category: "datatype", When: config.RuleCondition{Pattern: "DECIMAL"}, Then: config.RuleAction{Action: "replace_type", Target: "DECIMAL(${precision},${scale})"}},
+			{Name: "datetime", Category: "datatype", When: config.RuleCondition{Pattern: "DATETIME"}, Then: config.RuleAction{Action: "replace_type", Target: "TIMESTAMP"}},
+		}}
+		assert.Equal(t, "CREATE TABLE t (a DECIMAL(18,4),b TIMESTAMP(3))",
+			emit(t, cfg, "CREATE TABLE t (a DECIMAL(18,4), b DATETIME(3))"))
+	})
+}
+
+func TestDataTypeChecker_Boolean(t *testing.T) {
+	c, err := NewDataTypeChecker(testutils.GetTestConfig(t))
+	require.NoError(t, err)
+
+	// 与分析器一致，逐条语句检查并输出，表结构跨语句保留
+	var issues []model.Issue
+	emit := func(sql string) string {
+		t.Helper()
+		stmts, err := sqlparser.NewSQLParser().ParseSQL(sql)
+		require.NoError(t, err)
+		result := Check(stmts, c)
+		issues = append(issues, result.Issues...)
+		out, _, err := sqlemitter.NewEmitter().Emit(result.TransformedStmts, result.Hints)
+		require.NoError(t, err)
+		return out
+	}
+
+	assert.Equal(t, "CREATE TABLE users (id INTEGER,is_active BOOLEAN NOT NULL DEFAULT TRUE,level BOOLEAN,age SMALLINT,CHECK (level IN (FALSE,TRUE)))",
+		emit("CREATE TABLE users (id INT, is_active TINYINT(1) NOT NULL DEFAULT '1', level TINYINT(1), age TINYINT, CHECK (level IN (0,1)))"))
+	assert.Equal(t, "CREATE TABLE t (n SMALLINT DEFAULT 2)", emit("CREATE TABLE t (n TINYINT(1) DEFAULT 2)"))
+
+	assert.Equal(t, "INSERT INTO users VALUES (1,TRUE,FALSE,20)", emit("INSERT INTO users VALUES (1, 1, 0, 20)"))
+	assert.Equal(t, "UPDATE users AS u SET is_active=FALSE WHERE u.id=1 AND level!=TRUE",
+		emit("UPDATE users u SET u.is_active = 0 WHERE u.id = 1 AND level <> 1"))
+	assert.Equal(t, "SELECT * FROM users AS u JOIN t ON u.id=t.n WHERE u.is_active=TRUE AND FALSE=level AND age=1",
+		emit("SELECT * FROM users u JOIN t ON u.id = t.n WHERE u.is_active = 1 AND 0 = level AND age = 1"))
+	// 0/1 以外的值保持不变，每列只报告一次
+	assert.Equal(t, "INSERT INTO users (id,level) VALUES (2,5),(3,7)", emit("INSERT INTO users (id, level) VALUES (2, 5), (3, 7)"))
+
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.Message)
+	}
+	// 默认值不是 0/1 的列另有显示宽度与 TINYINT 规则的问题
+	require.Len(t, messages, 7)
+	assert.Contains(t, messages[0], "列 is_active 转换为 BOOLEAN，DEFAULT 1 转换为 TRUE")
+	assert.Contains(t, messages[3], "列 n 的默认值 2 不是 0/1")
+	assert.Contains(t, messages[6], "列 users.level 已转换为 BOOLEAN，但写入或比较了 0/1 以外的值 5")
+}
+
 func TestSyntaxChecker(t *testing.T) {
 	cfg := testutils.GetTestConfig(t)
 	checker, err := NewSyntaxChecker(cfg)
@@ -299,6 +517,21 @@ func TestSyntaxChecker(t *testing.T) {
 		assert.False(t, skip)
 	})
 
+	t.Run("inspect_limit_offset_fetch_hint", func(t *testing.T) {
+		// LIMIT 子句应记录问题，并通过输出提示改为 OFFSET ... FETCH
+		checker.Reset()
+		limit := &ast.Limit{}
+
+		_, skip := checker.Inspect(limit)
+
+		assert.False(t, skip)
+		assert.Len(t, checker.Issues(), 1)
+		assert.True(t, checker.Hints().OffsetFetch(limit))
+
+		checker.Reset()
+		assert.False(t, checker.Hints().OffsetFetch(limit))
+	})
+
 	t.Run("inspect_non_syntax_node", func(t *testing.T) {
 		// 测试非语法相关节点
 		funcCall := &ast.FuncCallExpr{
@@ -398,6 +631,620 @@ func TestCheckerErrorHandling(t *testing.T) {
 	})
 }
 
+// ============================================================================
+// DML 检查器测试
+// ============================================================================
+
+func TestDMLChecker(t *testing.T) {
+	cfg := testutils.GetTestConfig(t)
+
+	// check 依次检查每条语句，与分析器逐条调用 Check 的方式一致
+	check := func(t *testing.T, c *DMLChecker, sqls ...string) CheckResult {
+		t.Helper()
+		var result CheckResult
+		for _, sql := range sqls {
+			stmts, err := sqlparser.NewSQLParser().ParseSQL(sql)
+			require.NoError(t, err)
+			result = Check(stmts, c)
+		}
+		return result
+	}
+	schema := "CREATE TABLE users (id BIGINT AUTO_INCREMENT PRIMARY KEY, email VARCHAR(64) NOT NULL, name VARCHAR(64), hits INT, UNIQUE KEY uk_email (email))"
+
+	t.Run("basic_properties", func(t *testing.T) {
+		c, err := NewDMLChecker(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, "DMLChecker", c.Name())
+		assert.Equal(t, "dml", c.category)
+		assert.Len(t, c.GetRules(), 3)
+	})
+
+	t.Run("on_duplicate_key_from_unique_key", func(t *testing.T) {
+		c, err := NewDMLChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, schema,
+			"INSERT INTO users (email, name) VALUES ('a@x', 'a') ON DUPLICATE KEY UPDATE name = VALUES(name), hits = hits + 1")
+
+		require.Len(t, result.Issues, 1)
+		issue := result.Issues[0]
+		assert.Contains(t, issue.Message, "唯一键 uk_email (email)")
+		assert.True(t, issue.AutoFix.Available)
+		assert.Equal(t, "ON DUPLICATE KEY UPDATE -> ON CONFLICT (email) DO UPDATE", issue.AutoFix.Code)
+
+		insert := result.TransformedStmts[0].(*ast.InsertStmt)
+		clause, ok := result.Hints.OnConflict(insert)
+		require.True(t, ok)
+		assert.Equal(t, []string{"email"}, clause.Columns)
+		assert.False(t, clause.DoNothing)
+
+		// 不带表名的列引用补充表名，VALUES(col) 保持不变
+		hits := insert.OnDuplicate[1].Expr.(*ast.BinaryOperationExpr).L.(*ast.ColumnNameExpr)
+		assert.Equal(t, "users", hits.Name.Table.O)
+		assert.IsType(t, &ast.ValuesExpr{}, insert.OnDuplicate[0].Expr)
+	})
+
+	t.Run("on_duplicate_key_ambiguous", func(t *testing.T) {
+		c, err := NewDMLChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, schema,
+			"INSERT INTO users (id, email, name) VALUES (1, 'a@x', 'a') ON DUPLICATE KEY UPDATE name = VALUES(name)")
+
+		require.Len(t, result.Issues, 1)
+		assert.Contains(t, result.Issues[0].Message, "多个可能冲突的键（主键 (id)、唯一键 uk_email (email)）")
+		assert.False(t, result.Issues[0].AutoFix.Available)
+		_, ok := result.Hints.OnConflict(result.TransformedStmts[0].(*ast.InsertStmt))
+		assert.False(t, ok)
+	})
+
+	t.Run("on_duplicate_key_unknown_table", func(t *testing.T) {
+		c, err := NewDMLChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, "INSERT INTO orders (id) VALUES (1) ON DUPLICATE KEY UPDATE id = id")
+
+		require.Len(t, result.Issues, 1)
+		assert.Contains(t, result.Issues[0].Message, "未找到表 orders 的 CREATE TABLE 语句")
+		assert.False(t, result.Issues[0].AutoFix.Available)
+	})
+
+	t.Run("replace_into", func(t *testing.T) {
+		c, err := NewDMLChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, schema, "ALTER TABLE users DROP INDEX uk_email",
+			"REPLACE INTO users VALUES (1, 'a@x', 'a', 0)")
+
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, "REPLACE INTO -> ON CONFLICT (id) DO UPDATE", result.Issues[0].AutoFix.Code)
+
+		insert := result.TransformedStmts[0].(*ast.InsertStmt)
+		assert.False(t, insert.IsReplace)
+		require.Len(t, insert.OnDuplicate, 3)
+		assert.Equal(t, "email", insert.OnDuplicate[0].Column.Name.O)
+		clause, ok := result.Hints.OnConflict(insert)
+		require.True(t, ok)
+		assert.Equal(t, []string{"id"}, clause.Columns)
+	})
+
+	t.Run("insert_ignore", func(t *testing.T) {
+		c, err := NewDMLChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, "INSERT IGNORE INTO logs (msg) VALUES ('x')")
+
+		require.Len(t, result.Issues, 1)
+		assert.True(t, result.Issues[0].AutoFix.Available)
+
+		insert := result.TransformedStmts[0].(*ast.InsertStmt)
+		assert.False(t, insert.IgnoreErr)
+		clause, ok := result.Hints.OnConflict(insert)
+		require.True(t, ok)
+		assert.True(t, clause.DoNothing)
+		assert.Empty(t, clause.Columns)
+	})
+}
+
+// ============================================================================
+// 多表 DML 检查器测试
+// ============================================================================
+
+func TestMultiTableChecker(t *testing.T) {
+	cfg := testutils.GetTestConfig(t)
+
+	// check 依次检查每条语句，与分析器逐条调用 Check 的方式一致
+	check := func(t *testing.T, c *MultiTableChecker, sqls ...string) CheckResult {
+		t.Helper()
+		var result CheckResult
+		for _, sql := range sqls {
+			stmts, err := sqlparser.NewSQLParser().ParseSQL(sql)
+			require.NoError(t, err)
+			result = Check(stmts, c)
+		}
+		return result
+	}
+
+	t.Run("basic_properties", func(t *testing.T) {
+		c, err := NewMultiTableChecker(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, "MultiTableChecker", c.Name())
+		assert.Equal(t, "multitable", c.category)
+		assert.Len(t, c.GetRules(), 2)
+	})
+
+	t.Run("update_join", func(t *testing.T) {
+		c, err := NewMultiTableChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, "UPDATE orders o JOIN users u ON o.uid = u.id JOIN regions r ON u.rid = r.id SET o.status = r.code WHERE u.banned = 1")
+
+		require.Len(t, result.Issues, 1)
+		assert.True(t, result.Issues[0].AutoFix.Available)
+		assert.Contains(t, result.Issues[0].Message, "目标表为 o")
+
+		joined, ok := result.Hints.JoinedDML(result.TransformedStmts[0])
+		require.True(t, ok)
+		assert.Equal(t, "o", joined.Target.AsName.O)
+		require.Len(t, joined.Tables, 2)
+		assert.Equal(t, "u", joined.Tables[0].AsName.O)
+		assert.Equal(t, "r", joined.Tables[1].AsName.O)
+		assert.Len(t, joined.Conditions, 2)
+	})
+
+	t.Run("update_unqualified_column_from_schema", func(t *testing.T) {
+		c, err := NewMultiTableChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, "CREATE TABLE orders (id INT PRIMARY KEY, uid INT, status INT)",
+			"UPDATE orders, users SET status = users.flag WHERE orders.uid = users.id")
+
+		require.Len(t, result.Issues, 1)
+		joined, ok := result.Hints.JoinedDML(result.TransformedStmts[0])
+		require.True(t, ok)
+		assert.Equal(t, "orders", tableRefName(joined.Target))
+	})
+
+	t.Run("update_manual", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			sql    string
+			reason string
+		}{
+			{"外连接", "UPDATE orders o LEFT JOIN users u ON o.uid = u.id SET o.status = 0 WHERE u.id IS NULL", "包含外连接"},
+			{"修改多个表", "UPDATE orders o JOIN users u ON o.uid = u.id SET o.status = 0, u.flag = 1", "SET 同时修改了 o 与 u 两个表"},
+			{"未知列", "UPDATE orders o JOIN users u ON o.uid = u.id SET status = 0", "SET 列 status 没有指定表名"},
+			{"ORDER BY", "UPDATE orders o JOIN users u ON o.uid = u.id SET o.status = 0 ORDER BY o.id", "ORDER BY/LIMIT"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				c, err := NewMultiTableChecker(cfg)
+				require.NoError(t, err)
+				result := check(t, c, tt.sql)
+
+				require.Len(t, result.Issues, 1)
+				assert.Contains(t, result.Issues[0].Message, tt.reason)
+				assert.False(t, result.Issues[0].AutoFix.Available)
+				_, ok := result.Hints.JoinedDML(result.TransformedStmts[0])
+				assert.False(t, ok)
+			})
+		}
+	})
+
+	t.Run("delete_join", func(t *testing.T) {
+		for _, sql := range []string{
+			"DELETE o FROM orders o JOIN users u ON o.uid = u.id WHERE u.banned = 1",
+			"DELETE FROM o USING orders AS o JOIN users AS u ON o.uid = u.id WHERE u.banned = 1",
+		} {
+			c, err := NewMultiTableChecker(cfg)
+			require.NoError(t, err)
+			result := check(t, c, sql)
+
+			require.Len(t, result.Issues, 1, sql)
+			joined, ok := result.Hints.JoinedDML(result.TransformedStmts[0])
+			require.True(t, ok, sql)
+			assert.Equal(t, "o", joined.Target.AsName.O)
+			require.Len(t, joined.Tables, 1)
+			assert.Equal(t, "u", joined.Tables[0].AsName.O)
+		}
+	})
+
+	t.Run("delete_multiple_targets", func(t *testing.T) {
+		c, err := NewMultiTableChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, "DELETE o, u FROM orders o JOIN users u ON o.uid = u.id")
+
+		require.Len(t, result.Issues, 1)
+		assert.Contains(t, result.Issues[0].Message, "同时删除了 o、u 多个表的行")
+		assert.False(t, result.Issues[0].AutoFix.Available)
+	})
+
+	t.Run("single_table_ignored", func(t *testing.T) {
+		c, err := NewMultiTableChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, "UPDATE orders SET status = 1 WHERE id = 1")
+		assert.Empty(t, result.Issues)
+	})
+}
+
+// ============================================================================
+// 触发器检查器测试
+// ============================================================================
+
+func TestTriggerChecker(t *testing.T) {
+	cfg := testutils.GetTestConfig(t)
+
+	// check 依次检查每条语句，返回每条语句的检查结果
+	check := func(t *testing.T, c *TriggerChecker, sqls ...string) []CheckResult {
+		t.Helper()
+		var results []CheckResult
+		for _, sql := range sqls {
+			stmts, err := sqlparser.NewSQLParser().ParseSQL(sql)
+			require.NoError(t, err)
+			results = append(results, Check(stmts, c))
+		}
+		return results
+	}
+	columns := func(trigger sqlemitter.UpdateTrigger) []string {
+		var names []string
+		for _, assign := range trigger.Columns {
+			names = append(names, assign.Column.Name.L)
+		}
+		return names
+	}
+
+	t.Run("basic_properties", func(t *testing.T) {
+		c, err := NewTriggerChecker(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, "TriggerChecker", c.Name())
+		assert.Equal(t, "trigger", c.category)
+		assert.Len(t, c.GetRules(), 1)
+	})
+
+	t.Run("create_table", func(t *testing.T) {
+		c, err := NewTriggerChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, "CREATE TABLE Orders (id INT, updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP)")[0]
+
+		require.Len(t, result.Issues, 1)
+		assert.True(t, result.Issues[0].AutoFix.Available)
+		create := result.TransformedStmts[0].(*ast.CreateTableStmt)
+		require.Len(t, create.Cols[1].Options, 1)
+		assert.Equal(t, ast.ColumnOptionDefaultValue, create.Cols[1].Options[0].Tp)
+
+		trigger, ok := result.Hints.UpdateTrigger(create)
+		require.True(t, ok)
+		assert.Equal(t, "orders_on_update_fn", trigger.Function)
+		assert.Equal(t, "orders_on_update_trg", trigger.Trigger)
+		assert.Equal(t, []string{"updated_at"}, columns(trigger))
+	})
+
+	t.Run("issue_position", func(t *testing.T) {
+		c, err := NewTriggerChecker(cfg)
+		require.NoError(t, err)
+		results := check(t, c,
+			"CREATE TABLE orders (\n  id INT,\n  created_at DATETIME,\n"+
+				"  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP(3)\n)",
+			"ALTER TABLE orders\n  ADD COLUMN touched_at TIMESTAMP ON UPDATE CURRENT_TIMESTAMP",
+		)
+
+		// 问题定位到 ON UPDATE 子句，而不是语句的起始位置
+		require.Len(t, results[0].Issues, 1)
+		issue := results[0].Issues[0]
+		assert.Equal(t, 4, issue.Line)
+		assert.Equal(t, 49, issue.Column)
+		assert.Equal(t, "ON UPDATE CURRENT_TIMESTAMP(3)", issue.Snippet)
+
+		require.Len(t, results[1].Issues, 1)
+		assert.Equal(t, 2, results[1].Issues[0].Line)
+		assert.Equal(t, "ON UPDATE CURRENT_TIMESTAMP", results[1].Issues[0].Snippet)
+	})
+
+	t.Run("alter_table", func(t *testing.T) {
+		c, err := NewTriggerChecker(cfg)
+		require.NoError(t, err)
+		results := check(t, c,
+			"CREATE TABLE t (id INT, a DATETIME ON UPDATE CURRENT_TIMESTAMP)",
+			"RENAME TABLE t TO t2",
+			"ALTER TABLE t2 ADD COLUMN b TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, ADD COLUMN c INT",
+			"ALTER TABLE t2 MODIFY COLUMN a DATETIME",
+			"ALTER TABLE t2 ADD COLUMN d INT",
+			"ALTER TABLE t2 DROP COLUMN b",
+		)
+
+		trigger, ok := results[2].Hints.UpdateTrigger(results[2].TransformedStmts[0])
+		require.True(t, ok)
+		assert.Equal(t, "t_on_update_fn", trigger.Function, "重命名后沿用原触发器名称")
+		assert.Equal(t, []string{"a", "b"}, columns(trigger))
+
+		trigger, ok = results[3].Hints.UpdateTrigger(results[3].TransformedStmts[0])
+		require.True(t, ok)
+		assert.Equal(t, []string{"b"}, columns(trigger))
+
+		_, ok = results[4].Hints.UpdateTrigger(results[4].TransformedStmts[0])
+		assert.False(t, ok, "未修改 ON UPDATE 列时不重新生成触发器")
+
+		trigger, ok = results[5].Hints.UpdateTrigger(results[5].TransformedStmts[0])
+		require.True(t, ok)
+		assert.Empty(t, trigger.Columns, "没有 ON UPDATE 列时删除触发器")
+	})
+
+	t.Run("create_table_like", func(t *testing.T) {
+		c, err := NewTriggerChecker(cfg)
+		require.NoError(t, err)
+		results := check(t, c,
+			"CREATE TABLE t (id INT, a DATETIME ON UPDATE CURRENT_TIMESTAMP)",
+			"CREATE TABLE t_copy LIKE t",
+		)
+
+		trigger, ok := results[1].Hints.UpdateTrigger(results[1].TransformedStmts[0])
+		require.True(t, ok)
+		assert.Equal(t, "t_copy_on_update_trg", trigger.Trigger)
+		assert.Equal(t, []string{"a"}, columns(trigger))
+	})
+}
+
+// ============================================================================
+// 索引检查器测试
+// ============================================================================
+
+func TestIndexChecker(t *testing.T) {
+	cfg := testutils.GetTestConfig(t)
+
+	// check 依次检查每条语句，返回每条语句的检查结果
+	check := func(t *testing.T, c *IndexChecker, sqls ...string) []CheckResult {
+		t.Helper()
+		var results []CheckResult
+		for _, sql := range sqls {
+			stmts, err := sqlparser.NewSQLParser().ParseSQL(sql)
+			require.NoError(t, err)
+			results = append(results, Check(stmts, c))
+		}
+		return results
+	}
+	names := func(indexes []*ast.CreateIndexStmt) []string {
+		var result []string
+		for _, index := range indexes {
+			result = append(result, index.IndexName)
+		}
+		return result
+	}
+
+	t.Run("basic_properties", func(t *testing.T) {
+		c, err := NewIndexChecker(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, "IndexChecker", c.Name())
+		assert.Equal(t, "index", c.category)
+		assert.Len(t, c.GetRules(), 4)
+	})
+
+	t.Run("extract_inline_indexes", func(t *testing.T) {
+		c, err := NewIndexChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, "CREATE TABLE users (id INT PRIMARY KEY, email VARCHAR(255), name VARCHAR(100), bio TEXT, "+
+			"KEY idx_name (name), UNIQUE KEY uk_email (email(50)), KEY (name(20), id), FULLTEXT KEY ft_bio (bio), UNIQUE KEY (id, name))")[0]
+
+		create := result.TransformedStmts[0].(*ast.CreateTableStmt)
+		require.Len(t, create.Constraints, 1, "只保留不带前缀的唯一约束")
+		assert.Equal(t, "users_id_key", create.Constraints[0].Name)
+
+		indexes := result.Hints.Indexes(create)
+		assert.Equal(t, []string{"idx_name", "uk_email", "users_name_idx"}, names(indexes))
+		assert.Equal(t, ast.IndexKeyTypeUnique, indexes[1].KeyType)
+		require.NotNil(t, indexes[1].IndexPartSpecifications[0].Expr, "唯一前缀索引转换为表达式索引")
+		require.NotNil(t, indexes[2].IndexPartSpecifications[0].Expr, "普通前缀索引同样转换为表达式索引，不扩大为整列索引")
+		assert.Zero(t, indexes[2].IndexPartSpecifications[0].Length)
+		assert.Nil(t, indexes[2].IndexPartSpecifications[1].Expr, "不带前缀的列保持不变")
+
+		var fulltext bool
+		for _, issue := range result.Issues {
+			if strings.Contains(issue.Message, patternFulltext) {
+				fulltext = true
+				assert.Contains(t, issue.Message, "USING GIN (to_tsvector('simple', coalesce(bio, '')))")
+				assert.False(t, issue.AutoFix.Available)
+			}
+		}
+		assert.True(t, fulltext)
+	})
+
+	t.Run("issue_position", func(t *testing.T) {
+		c, err := NewIndexChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, "CREATE TABLE users (\n  id INT PRIMARY KEY,\n  name VARCHAR(100),\n"+
+			"  KEY name (name),\n  INDEX (id), KEY idx_prefix (name(20))\n)")[0]
+
+		// 问题定位到各自的索引子句，而不是 CREATE TABLE 的起始位置
+		var positions []string
+		for _, issue := range result.Issues {
+			positions = append(positions, fmt.Sprintf("%d:%d", issue.Line, issue.Column))
+		}
+		assert.Equal(t, []string{"4:3", "5:3", "5:15", "5:15"}, positions)
+	})
+
+	t.Run("unique_per_schema", func(t *testing.T) {
+		c, err := NewIndexChecker(cfg)
+		require.NoError(t, err)
+		results := check(t, c,
+			"CREATE TABLE users (id INT, name VARCHAR(10), KEY idx_name (name))",
+			"CREATE TABLE orders (id INT, name VARCHAR(10), KEY idx_name (name), KEY users (id))",
+			"CREATE TABLE shop.orders (id INT, name VARCHAR(10), KEY idx_name (name))",
+			"ALTER TABLE orders RENAME INDEX idx_name TO idx_n",
+			"DROP INDEX users ON orders",
+		)
+
+		assert.Equal(t, []string{"orders_idx_name", "orders_users"}, names(results[1].Hints.Indexes(results[1].TransformedStmts[0])))
+		assert.Equal(t, []string{"idx_name"}, names(results[2].Hints.Indexes(results[2].TransformedStmts[0])), "不同 schema 的索引名互不影响")
+
+		alter := results[3].TransformedStmts[0].(*ast.AlterTableStmt)
+		assert.Equal(t, "orders_idx_name", alter.Specs[0].FromKey.O)
+		assert.Equal(t, "idx_n", alter.Specs[0].ToKey.O)
+		assert.Equal(t, "orders_users", results[4].TransformedStmts[0].(*ast.DropIndexStmt).IndexName)
+	})
+
+	t.Run("alter_table_add_index", func(t *testing.T) {
+		c, err := NewIndexChecker(cfg)
+		require.NoError(t, err)
+		result := check(t, c, "ALTER TABLE t ADD INDEX (a), ADD COLUMN b INT")[0]
+
+		alter := result.TransformedStmts[0].(*ast.AlterTableStmt)
+		require.Len(t, alter.Specs, 1)
+		assert.Equal(t, ast.AlterTableAddColumns, alter.Specs[0].Tp)
+		assert.Equal(t, []string{"t_a_idx"}, names(result.Hints.Indexes(alter)))
+	})
+}
+
+// ============================================================================
+// 分片检查器测试
+// ============================================================================
+
+func TestShardingChecker(t *testing.T) {
+	cfg := testutils.GetTestConfig(t)
+
+	// check 依次检查每条语句，返回每条语句的问题
+	check := func(t *testing.T, checkers []Checker, sqls ...string) [][]model.Issue {
+		t.Helper()
+		var issues [][]model.Issue
+		for _, sql := range sqls {
+			stmts, err := sqlparser.NewSQLParser().ParseSQL(sql)
+			require.NoError(t, err)
+			issues = append(issues, Check(stmts, checkers...).Issues)
+		}
+		return issues
+	}
+
+	t.Run("basic_properties", func(t *testing.T) {
+		c, err := NewShardingChecker(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, "ShardingChecker", c.Name())
+		assert.Equal(t, "sharding", c.category)
+		assert.Len(t, c.GetRules(), 2)
+	})
+
+	t.Run("sequential_primary_key", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			sql    string
+			layout string
+		}{
+			{"auto_increment", "CREATE TABLE t (id BIGINT AUTO_INCREMENT PRIMARY KEY, v INT)", "PRIMARY KEY (id HASH)"},
+			{"timestamp_leading", "CREATE TABLE t (created_at TIMESTAMP, device_id INT, PRIMARY KEY (created_at, device_id))", "PRIMARY KEY (device_id HASH, created_at ASC)"},
+			{"sequence_column", "CREATE TABLE t (order_seq INT, line_seq INT, PRIMARY KEY (order_seq, line_seq))", "PRIMARY KEY ((order_seq, line_seq) HASH)"},
+			{"not_sequential", "CREATE TABLE t (tenant_id INT, created_at DATETIME, PRIMARY KEY (tenant_id, created_at))", ""},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				c, err := NewShardingChecker(cfg)
+				require.NoError(t, err)
+				issues := check(t, []Checker{c}, tt.sql)[0]
+				if tt.layout == "" {
+					assert.Empty(t, issues)
+					return
+				}
+				require.Len(t, issues, 1)
+				assert.Contains(t, issues[0].Message, tt.layout)
+			})
+		}
+	})
+
+	t.Run("issue_position", func(t *testing.T) {
+		c, err := NewShardingChecker(cfg)
+		require.NoError(t, err)
+		issues := check(t, []Checker{c},
+			"CREATE TABLE a (\n  v INT,\n  id BIGINT AUTO_INCREMENT PRIMARY KEY\n)",
+			"CREATE TABLE b (\n  created_at TIMESTAMP,\n  device_id INT,\n  PRIMARY KEY (created_at, device_id)\n)",
+		)
+
+		// 问题定位到定义主键的列或 PRIMARY KEY 子句，而不是语句的起始位置
+		require.Len(t, issues[0], 1)
+		assert.Equal(t, 3, issues[0][0].Line)
+		assert.Equal(t, "id BIGINT AUTO_INCREMENT PRIMARY KEY", issues[0][0].Snippet)
+		require.Len(t, issues[1], 1)
+		assert.Equal(t, 4, issues[1][0].Line)
+		assert.Equal(t, "PRIMARY KEY (created_at, device_id)", issues[1][0].Snippet)
+	})
+
+	t.Run("auto_increment_removed_by_other_checker", func(t *testing.T) {
+		syntax, err := NewSyntaxChecker(cfg)
+		require.NoError(t, err)
+		c, err := NewShardingChecker(cfg)
+		require.NoError(t, err)
+		issues := check(t, []Checker{syntax, c}, "CREATE TABLE t (id INT AUTO_INCREMENT, PRIMARY KEY (id))")[0]
+
+		var found bool
+		for _, issue := range issues {
+			found = found || (issue.Checker == "ShardingChecker" && strings.Contains(issue.Message, "PRIMARY KEY (id HASH)"))
+		}
+		assert.True(t, found, "其他检查器移除 AUTO_INCREMENT 选项后仍然识别自增主键")
+	})
+
+	t.Run("range_scan", func(t *testing.T) {
+		c, err := NewShardingChecker(cfg)
+		require.NoError(t, err)
+		issues := check(t, []Checker{c},
+			"CREATE TABLE orders (id INT, user_id INT, total INT, created_at DATETIME)",
+			"CREATE TABLE users (id INT, name VARCHAR(20))",
+			"SELECT o.id FROM orders o JOIN users u ON o.user_id = u.id WHERE o.created_at >= '2024-01-01' AND total > 10 AND u.id < o.id ORDER BY name",
+			"SELECT user_id AS uid FROM orders WHERE created_at BETWEEN '2024-01-01' AND '2024-02-01' ORDER BY uid",
+		)
+
+		require.Len(t, issues[2], 3)
+		assert.Contains(t, issues[2][0].Message, "CREATE INDEX orders_created_at_asc_idx ON orders (created_at ASC)")
+		assert.Contains(t, issues[2][0].Message, "范围条件 >=")
+		assert.Contains(t, issues[2][1].Message, "(total ASC)")
+		assert.Contains(t, issues[2][2].Message, "CREATE INDEX users_name_asc_idx ON users (name ASC)")
+		assert.Empty(t, issues[3], "同一列只建议一次，ORDER BY 中的列别名不建议")
+	})
+}
+
+// ============================================================================
+// 问题位置测试
+// ============================================================================
+
+func TestCheck_IssuePositions(t *testing.T) {
+	cfg := testutils.GetTestConfig(t)
+	functionChecker, err := NewFunctionChecker(cfg)
+	require.NoError(t, err)
+	dataTypeChecker, err := NewDataTypeChecker(cfg)
+	require.NoError(t, err)
+
+	sqls := []string{
+		"SELECT id,\n  IFNULL(name, 'x') FROM users",
+		"CREATE TABLE t (\n  id INT,\n  flag TINYINT\n)",
+	}
+	var stmts []ast.StmtNode
+	for _, sql := range sqls {
+		nodes, err := sqlparser.NewSQLParser().ParseSQL(sql)
+		require.NoError(t, err)
+		stmts = append(stmts, nodes...)
+	}
+
+	result := Check(stmts, functionChecker, dataTypeChecker)
+
+	byChecker := make(map[string]model.Issue)
+	for _, issue := range result.Issues {
+		byChecker[issue.Checker] = issue
+	}
+
+	// 表达式节点使用解析器记录的原文偏移量
+	ifnull := byChecker["FunctionChecker"]
+	assert.Equal(t, 1, ifnull.StatementIndex)
+	assert.Equal(t, 2, ifnull.Line)
+	assert.Equal(t, 3, ifnull.Column)
+	assert.Equal(t, "IFNULL(name, 'x')", ifnull.Snippet, "片段是节点本身的文本，不包含其后的子句")
+
+	// 列定义没有偏移量，按列名在原文中定位
+	tinyint := byChecker["DataTypeChecker"]
+	assert.Equal(t, 2, tinyint.StatementIndex)
+	assert.Equal(t, 3, tinyint.Line)
+	assert.Equal(t, 3, tinyint.Column)
+	assert.Equal(t, "flag TINYINT", tinyint.Snippet)
+
+	t.Run("function_call_followed_by_clauses", func(t *testing.T) {
+		stmts, err := sqlparser.NewSQLParser().ParseSQL("SELECT IFNULL(a, 0) AS x, COUNT(*) FROM orders\n" +
+			"WHERE IFNULL(total, (1 + 2)) > 10 AND note IS NOT NULL GROUP BY IFNULL(a, 0) ORDER BY id LIMIT 5")
+		require.NoError(t, err)
+
+		var snippets []string
+		for _, issue := range Check(stmts, functionChecker).Issues {
+			snippets = append(snippets, issue.Snippet)
+		}
+		assert.Equal(t, []string{"IFNULL(a, 0)", "IFNULL(total, (1 + 2))", "IFNULL(a, 0)"}, snippets)
+	})
+}
+
 // ============================================================================
 // 性能测试
 // ============================================================================
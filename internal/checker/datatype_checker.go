@@ -2,13 +2,23 @@ package checker
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
 	"github.com/pingcap/tidb/pkg/parser/types"
 
 	"github.com/example/ybMigration/internal/config"
 	"github.com/example/ybMigration/internal/model"
+	sqlemitter "github.com/example/ybMigration/internal/sql-emitter"
+)
+
+// 整数列属性规则的 pattern
+const (
+	patternUnsigned     = "UNSIGNED"
+	patternZerofill     = "ZEROFILL"
+	patternDisplayWidth = "DISPLAY WIDTH"
 )
 
 // DataTypeChecker 数据类型检查器实现
@@ -16,6 +26,15 @@ import (
 // 支持从default.yaml配置文件加载规则，实现MySQL到目标数据库的语法转换
 type DataTypeChecker struct {
 	*RuleChecker
+	unsignedCheck bool // UNSIGNED 列扩宽后是否添加 CHECK (col >= 0)
+
+	catalog *schemaCatalog    // 从 DDL 收集的表结构，记录转换为 BOOLEAN 的列，跨语句保留
+	flagged map[string]bool   // 已报告写入 0/1 以外取值的 BOOLEAN 列，key 为 表名.列名，跨语句保留
+	table   *ast.TableName    // 当前 CREATE/ALTER TABLE 语句的目标表
+	scope   map[string]string // 当前语句中的别名或表名到表名的映射，随 Reset 清空
+
+	serial        bool                    // 配置了 AUTO_INCREMENT 转 SERIAL 的规则，自增列输出为 SMALLSERIAL/SERIAL/BIGSERIAL
+	autoIncrement map[*ast.ColumnDef]bool // 当前建表语句中的自增列，进入语句时记录，其他检查器可能先于本检查器移除 AUTO_INCREMENT 选项
 }
 
 // NewDataTypeChecker 创建数据类型检查器
@@ -27,8 +46,26 @@ func NewDataTypeChecker(cfg *config.Config) (*DataTypeChecker, error) {
 	if err != nil {
 		return nil, fmt.Errorf("创建数据类型检查器失败: %w", err)
 	}
+	// 配置的类型转换表通过输出提示交给 SQL 生成器
+	mapping, err := sqlemitter.NewTypeMapping(cfg.DataType.TypeMapping)
+	if err != nil {
+		return nil, fmt.Errorf("创建数据类型检查器失败: 类型转换表无效: %w", err)
+	}
+	ruleChecker.hints.SetTypeMapping(mapping)
+
+	serial := false
+	for _, rule := range cfg.Rules {
+		if strings.EqualFold(rule.When.Pattern, "AUTO_INCREMENT") && rule.Then.Action == "replace_constraint" && rule.Then.Target == "SERIAL" {
+			serial = true
+		}
+	}
+
 	return &DataTypeChecker{
-		RuleChecker: ruleChecker,
+		RuleChecker:   ruleChecker,
+		unsignedCheck: cfg.DataType.UnsignedCheck,
+		catalog:       newSchemaCatalog(),
+		flagged:       make(map[string]bool),
+		serial:        serial,
 	}, nil
 }
 
@@ -37,17 +74,60 @@ func (d *DataTypeChecker) Name() string {
 	return "DataTypeChecker"
 }
 
+// Reset 重置检查器状态，表结构与已报告的列跨语句保留
+func (d *DataTypeChecker) Reset() {
+	d.RuleChecker.Reset()
+	d.table = nil
+	d.scope = nil
+	d.autoIncrement = nil
+}
+
 // Inspect 实现 Checker 接口，处理 AST 节点
-// 检查数据类型兼容性并执行转换
+// 检查数据类型兼容性并执行转换；DDL 语句同时用于收集表结构，
+// DML 语句中 BOOLEAN 列的 0/1 字面量转换为 FALSE/TRUE
 func (d *DataTypeChecker) Inspect(n ast.Node) (w ast.Node, skipChildren bool) {
 	switch node := n.(type) {
 	case *ast.ColumnDef:
 		// 检查并转换列定义中的数据类型
 		return d.checkColumnType(node)
 
+	case *ast.CreateTableStmt:
+		d.catalog.learn(node)
+		d.table = node.Table
+		d.enterStatement(node)
+		d.autoIncrement = make(map[*ast.ColumnDef]bool)
+		for _, col := range node.Cols {
+			if hasAutoIncrement(col) {
+				d.autoIncrement[col] = true
+			}
+		}
+
 	case *ast.AlterTableStmt:
 		// 检查并转换修改表结构中的数据类型变更
+		d.catalog.learn(node)
+		d.table = node.Table
+		d.enterStatement(node)
 		return d.checkAlterTable(node)
+
+	case *ast.CreateIndexStmt, *ast.DropTableStmt, *ast.RenameTableStmt:
+		d.catalog.learn(node)
+
+	case *ast.InsertStmt:
+		d.enterStatement(node)
+		d.checkBooleanInsert(node)
+
+	case *ast.UpdateStmt:
+		d.enterStatement(node)
+		d.checkBooleanAssignments(node.List)
+
+	case *ast.DeleteStmt, *ast.SelectStmt, *ast.SetOprStmt:
+		d.enterStatement(node)
+
+	case *ast.BinaryOperationExpr:
+		d.checkBooleanComparison(node)
+
+	case *ast.PatternInExpr:
+		d.checkBooleanIn(node)
 	}
 	return n, false
 }
@@ -71,6 +151,16 @@ func (d *DataTypeChecker) checkColumnType(node *ast.ColumnDef) (ast.Node, bool)
 		return node, false
 	}
 
+	// TINYINT(1) 按 BOOLEAN 处理，不再检查整数属性与 TINYINT 规则
+	if d.checkBooleanColumn(node) {
+		return node, false
+	}
+
+	// 整数列的 UNSIGNED、ZEROFILL 与显示宽度，扩宽后按新类型匹配类型规则
+	if d.checkIntegerAttributes(node, typeName) {
+		typeName = d.extractTypeNameFromFieldType(node.Tp)
+	}
+
 	// 检查是否有匹配的规则
 	rules := d.GetRules()
 	rule, hasRule := rules[typeName]
@@ -89,11 +179,151 @@ func (d *DataTypeChecker) checkColumnType(node *ast.ColumnDef) (ast.Node, bool)
 		},
 	})
 
+	// ENUM/SET 转换后的语义差异
+	if note := enumSemanticsNote(typeName, rule.Then.Target); note != "" {
+		d.AddIssue(model.Issue{
+			Checker: "DataTypeChecker",
+			Message: fmt.Sprintf("数据类型 %s: 列 %s %s", typeName, node.Name.Name.O, note),
+		})
+	}
+
 	// 执行AST转换
 	transformedNode := d.ApplyTransformation(node, rule)
 	return transformedNode, true
 }
 
+// checkIntegerAttributes 检查整数列的 UNSIGNED、ZEROFILL 与显示宽度属性
+// UNSIGNED 列扩宽为能容纳其上限的类型并清除 UNSIGNED 标志，BIGINT UNSIGNED 转换为 NUMERIC(20)（自增列转换为 BIGINT）；
+// ZEROFILL 与显示宽度只影响 MySQL 客户端的显示，直接移除。每项修改分别记录问题。
+// 参数:
+//   - node: 列定义节点
+//   - typeName: 列的原类型名称
+//
+// 返回:
+//   - bool: 列类型是否被扩宽
+func (d *DataTypeChecker) checkIntegerAttributes(node *ast.ColumnDef, typeName string) bool {
+	tp := node.Tp
+	if !mysql.IsIntegerType(tp.GetType()) {
+		return false
+	}
+	rules := d.GetRules()
+	column := node.Name.Name.O
+
+	if rule, ok := rules[patternZerofill]; ok && mysql.HasZerofillFlag(tp.GetFlag()) {
+		tp.DelFlag(mysql.ZerofillFlag)
+		d.addAttributeIssue(patternZerofill, rule, column, typeName+" ZEROFILL", typeName)
+	}
+	if rule, ok := rules[patternDisplayWidth]; ok && tp.GetFlen() != types.UnspecifiedLength {
+		width := fmt.Sprintf("%s(%d)", typeName, tp.GetFlen())
+		tp.SetFlen(types.UnspecifiedLength)
+		d.addAttributeIssue(patternDisplayWidth, rule, column, width, typeName)
+	}
+
+	rule, ok := rules[patternUnsigned]
+	if !ok || !mysql.HasUnsignedFlag(tp.GetFlag()) {
+		return false
+	}
+	autoIncrement := d.autoIncrement[node] || hasAutoIncrement(node)
+	// 报告实际输出的类型：自增列由 AUTO_INCREMENT 规则按原类型输出为 SERIAL 类型
+	emitted := ""
+	if autoIncrement && d.serial {
+		emitted = serialTypeFor(tp)
+	}
+	widened := widenUnsigned(tp, autoIncrement)
+	if emitted == "" {
+		emitted = widened
+	}
+	d.addAttributeIssue(patternUnsigned, rule, column, typeName+" UNSIGNED", emitted)
+	if d.unsignedCheck {
+		node.Options = append(node.Options, &ast.ColumnOption{
+			Tp: ast.ColumnOptionCheck,
+			Expr: &ast.BinaryOperationExpr{
+				Op: opcode.GE,
+				L:  &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: node.Name.Name}},
+				R:  ast.NewValueExpr(0, "", ""),
+			},
+			Enforced: true,
+		})
+	}
+	return true
+}
+
+// addAttributeIssue 记录整数列属性的转换问题
+// 参数:
+//   - pattern: 规则的 pattern
+//   - rule: 匹配的规则
+//   - column: 列名
+//   - from: 原类型写法，如 INT UNSIGNED
+//   - to: 转换后的类型
+func (d *DataTypeChecker) addAttributeIssue(pattern string, rule config.Rule, column, from, to string) {
+	d.AddIssue(model.Issue{
+		Checker: "DataTypeChecker",
+		Message: fmt.Sprintf("数据类型 %s: %s (建议: %s)，列 %s 转换为 %s", pattern, rule.Description, rule.Then.Target, column, to),
+		AutoFix: model.AutoFix{
+			Available: true,
+			Action:    rule.Then.Action,
+			Code:      fmt.Sprintf("%s -> %s", from, to),
+		},
+	})
+}
+
+// widenUnsigned 将 UNSIGNED 整数类型扩宽为能容纳其上限的有符号类型，并清除 UNSIGNED 标志
+// TINYINT UNSIGNED 的上限 255 在 SMALLINT 范围内，与有符号 TINYINT 一样转换为 SMALLINT。
+// 参数:
+//   - tp: 列类型
+//   - autoIncrement: 是否为自增列，自增列的 BIGINT UNSIGNED 保留为 BIGINT
+//
+// 返回:
+//   - string: 扩宽后的 YSQL 类型名称
+func widenUnsigned(tp *types.FieldType, autoIncrement bool) string {
+	tp.DelFlag(mysql.UnsignedFlag)
+	switch tp.GetType() {
+	case mysql.TypeTiny:
+		tp.SetType(mysql.TypeShort)
+		return "SMALLINT"
+	case mysql.TypeShort, mysql.TypeInt24:
+		tp.SetType(mysql.TypeLong)
+		return "INTEGER"
+	case mysql.TypeLong:
+		tp.SetType(mysql.TypeLonglong)
+		return "BIGINT"
+	case mysql.TypeLonglong:
+		if autoIncrement {
+			// 序列与 IDENTITY 只能使用整数类型，自增取值也不会超过 BIGINT 的上限
+			return "BIGINT"
+		}
+		fallthrough
+	default:
+		// BIGINT UNSIGNED 的上限 18446744073709551615 共 20 位
+		tp.SetType(mysql.TypeNewDecimal)
+		tp.SetFlen(20)
+		tp.SetDecimal(0)
+		return "NUMERIC(20)"
+	}
+}
+
+// enumSemanticsNote 返回 ENUM/SET 列按目标类型转换后与 MySQL 的语义差异
+// 参数:
+//   - typeName: 原类型名称（ENUM 或 SET）
+//   - target: 规则的转换目标
+//
+// 返回:
+//   - string: 语义差异说明，不是 ENUM/SET 的转换时返回空字符串
+func enumSemanticsNote(typeName, target string) string {
+	const emptyString = "MySQL 非严格模式下写入不在列表中的值会存为空字符串 ''，YSQL 会直接报错"
+	switch {
+	case typeName == "ENUM" && target == TypeTargetEnum:
+		return "转换为枚举类型后仍按元素定义顺序排序，但不再支持按序号访问（如 col+0、WHERE col=1）；" + emptyString
+	case typeName == "ENUM" && target == TypeTargetVarchar:
+		return "转换为 VARCHAR 后 ORDER BY 按字符串排序而不是元素定义顺序，也不再支持按序号访问；" + emptyString
+	case typeName == "SET" && target == TypeTargetArray:
+		return "转换为 TEXT[] 后需要把 'a,b' 形式的数据迁移为数组，FIND_IN_SET 等查询需要改写为数组运算符；MySQL 按元素定义顺序排序并去重，数组保留写入时的顺序与重复元素"
+	case typeName == "SET" && target == TypeTargetVarchar:
+		return "转换为 VARCHAR 后 ORDER BY 按字符串排序而不是元素位图；MySQL 按元素定义顺序规整并去重，VARCHAR 保留写入时的原样"
+	}
+	return ""
+}
+
 // extractTypeNameFromFieldType 从 FieldType 提取类型名称。
 // 使用 TiDB 的 GetType() 方法直接获取类型常量，并转换为字符串表示。
 // 参数:
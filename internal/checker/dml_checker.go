@@ -0,0 +1,279 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+
+	"github.com/example/ybMigration/internal/config"
+	"github.com/example/ybMigration/internal/model"
+	sqlemitter "github.com/example/ybMigration/internal/sql-emitter"
+)
+
+// upsert 规则的 pattern
+const (
+	patternOnDuplicate = "ON DUPLICATE KEY UPDATE"
+	patternReplace     = "REPLACE INTO"
+	patternIgnore      = "INSERT IGNORE"
+)
+
+// DMLChecker DML 检查器
+// 检查 MySQL 特有的 upsert 写法（ON DUPLICATE KEY UPDATE、REPLACE INTO、INSERT IGNORE），
+// 改写为 YSQL 的 INSERT ... ON CONFLICT。ON CONFLICT DO UPDATE 必须指定冲突目标，
+// 冲突目标取自此前 DDL 中收集的主键或唯一键；无法唯一确定时只报告原因，不做猜测，
+// 语句由 SQL 生成器输出为注释，不会以普通 INSERT 的形式执行。
+type DMLChecker struct {
+	*RuleChecker
+	catalog *schemaCatalog // 从 DDL 收集的表结构，跨语句保留
+}
+
+// NewDMLChecker 创建 DML 检查器实例
+// 返回:
+//   - *DMLChecker: 初始化后的 DML 检查器实例
+//   - error: 错误信息
+func NewDMLChecker(cfg *config.Config) (*DMLChecker, error) {
+	ruleChecker, err := newRuleChecker("DMLChecker", "dml", cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建DML检查器失败: %w", err)
+	}
+	return &DMLChecker{
+		RuleChecker: ruleChecker,
+		catalog:     newSchemaCatalog(),
+	}, nil
+}
+
+// Name 返回检查器名称
+func (d *DMLChecker) Name() string { return "DMLChecker" }
+
+// Inspect 实现 Checker 接口
+// DDL 语句用于收集表结构，INSERT 语句按收集到的主键与唯一键改写为 ON CONFLICT
+func (d *DMLChecker) Inspect(n ast.Node) (w ast.Node, skipChildren bool) {
+	switch node := n.(type) {
+	case *ast.InsertStmt:
+		d.checkInsert(node)
+	case *ast.CreateTableStmt, *ast.AlterTableStmt, *ast.CreateIndexStmt, *ast.DropTableStmt, *ast.RenameTableStmt:
+		d.catalog.learn(node)
+	}
+	return n, false
+}
+
+// checkInsert 检查 INSERT 语句的 upsert 写法
+func (d *DMLChecker) checkInsert(n *ast.InsertStmt) {
+	rules := d.GetRules()
+	switch {
+	case n.IsReplace:
+		if rule, ok := rules[patternReplace]; ok {
+			d.rewriteReplace(n, rule)
+		}
+	case len(n.OnDuplicate) > 0:
+		if rule, ok := rules[patternOnDuplicate]; ok {
+			d.rewriteOnDuplicate(n, rule)
+		}
+	case n.IgnoreErr:
+		if rule, ok := rules[patternIgnore]; ok {
+			d.rewriteIgnore(n, rule)
+		}
+	}
+}
+
+// rewriteOnDuplicate 将 ON DUPLICATE KEY UPDATE 改写为 ON CONFLICT (...) DO UPDATE SET
+// 赋值表达式中不带表名的列引用补充表名，避免与 EXCLUDED 中的同名列产生歧义
+func (d *DMLChecker) rewriteOnDuplicate(n *ast.InsertStmt, rule config.Rule) {
+	table := insertTable(n)
+	target, key, reason := d.conflictTarget(n, table)
+	if target == nil {
+		d.addUpsertIssue(patternOnDuplicate, rule, "", "无法确定冲突目标: "+reason+"，需要手工指定 ON CONFLICT (...) 的冲突目标")
+		return
+	}
+
+	qualifier := &columnQualifier{table: table.Name}
+	for _, assign := range n.OnDuplicate {
+		if expr, ok := assign.Expr.Accept(qualifier); ok {
+			assign.Expr = expr.(ast.ExprNode)
+		}
+	}
+	d.hints.SetOnConflict(n, sqlemitter.OnConflict{Columns: target})
+	d.addUpsertIssue(patternOnDuplicate, rule, conflictClause(target, "DO UPDATE"), "冲突目标取自表 "+table.Name.O+" 的"+key.String())
+}
+
+// rewriteReplace 将 REPLACE INTO 改写为 INSERT ... ON CONFLICT (...) DO UPDATE SET
+// 插入列中冲突目标以外的列更新为 EXCLUDED 中的值，插入列都属于冲突目标时改写为 DO NOTHING
+func (d *DMLChecker) rewriteReplace(n *ast.InsertStmt, rule config.Rule) {
+	table := insertTable(n)
+	target, key, reason := d.conflictTarget(n, table)
+	if target == nil {
+		d.addUpsertIssue(patternReplace, rule, "", "无法确定冲突目标: "+reason+"，需要手工改写为 INSERT ... ON CONFLICT (...) DO UPDATE")
+		return
+	}
+
+	inTarget := make(map[string]bool, len(target))
+	for _, col := range target {
+		inTarget[col] = true
+	}
+	var assigns []*ast.Assignment
+	for _, col := range d.insertColumns(n, table) {
+		if inTarget[col.L] {
+			continue
+		}
+		assigns = append(assigns, &ast.Assignment{
+			Column: &ast.ColumnName{Name: col},
+			Expr:   &ast.ValuesExpr{Column: &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: col}}},
+		})
+	}
+
+	n.IsReplace = false
+	n.OnDuplicate = assigns
+	action := "DO UPDATE"
+	if len(assigns) == 0 {
+		action = "DO NOTHING"
+	}
+	d.hints.SetOnConflict(n, sqlemitter.OnConflict{Columns: target, DoNothing: len(assigns) == 0})
+	d.addUpsertIssue(patternReplace, rule, conflictClause(target, action),
+		"冲突目标取自表 "+table.Name.O+" 的"+key.String()+"；REPLACE 会先删除冲突行再插入，改写后未插入的列保留原值而不是恢复默认值")
+}
+
+// rewriteIgnore 将 INSERT IGNORE 改写为 INSERT ... ON CONFLICT DO NOTHING
+// 不指定冲突目标的 DO NOTHING 在任一唯一约束冲突时生效，与 MySQL 行为一致，无需确定冲突目标
+func (d *DMLChecker) rewriteIgnore(n *ast.InsertStmt, rule config.Rule) {
+	n.IgnoreErr = false
+	d.hints.SetOnConflict(n, sqlemitter.OnConflict{DoNothing: true})
+	d.addUpsertIssue(patternIgnore, rule, "ON CONFLICT DO NOTHING",
+		"IGNORE 还会忽略数据截断、NOT NULL 等其他错误，ON CONFLICT DO NOTHING 只忽略唯一约束冲突")
+}
+
+// conflictTarget 根据表的主键与唯一键确定 ON CONFLICT 的冲突目标
+// 只有全部列都由 INSERT 提供的键才可能冲突（未提供的列取默认值或自增值）；
+// 可能冲突的键恰好一个时作为冲突目标。
+//
+// 返回:
+//   - []string: 冲突目标列，无法确定时为 nil
+//   - tableKey: 作为冲突目标的键
+//   - string: 无法确定时的原因
+func (d *DMLChecker) conflictTarget(n *ast.InsertStmt, table *ast.TableName) ([]string, tableKey, string) {
+	if table == nil {
+		return nil, tableKey{}, "INSERT 的目标不是单个表"
+	}
+	schema := d.catalog.lookup(table)
+	if schema == nil {
+		return nil, tableKey{}, fmt.Sprintf("未找到表 %s 的 CREATE TABLE 语句，主键与唯一键未知", table.Name.O)
+	}
+	if len(schema.keys) == 0 {
+		return nil, tableKey{}, fmt.Sprintf("表 %s 没有主键或唯一键", table.Name.O)
+	}
+
+	supplied := make(map[string]bool)
+	for _, col := range d.insertColumns(n, table) {
+		supplied[col.L] = true
+	}
+	var candidates []tableKey
+	for _, key := range schema.keys {
+		covered := true
+		for _, col := range key.columns {
+			covered = covered && supplied[col]
+		}
+		if covered {
+			candidates = append(candidates, key)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, tableKey{}, fmt.Sprintf("插入的列不包含表 %s 任何主键或唯一键的全部列（%s）", table.Name.O, describeKeys(schema.keys))
+	case 1:
+		return candidates[0].columns, candidates[0], ""
+	default:
+		return nil, tableKey{}, fmt.Sprintf("表 %s 有多个可能冲突的键（%s），MySQL 在任一键冲突时都会处理，ON CONFLICT 只能指定一个冲突目标",
+			table.Name.O, describeKeys(candidates))
+	}
+}
+
+// insertColumns 返回 INSERT 提供的列，未写列名列表时为表的全部列
+func (d *DMLChecker) insertColumns(n *ast.InsertStmt, table *ast.TableName) []ast.CIStr {
+	var cols []ast.CIStr
+	if len(n.Columns) > 0 {
+		for _, col := range n.Columns {
+			cols = append(cols, col.Name)
+		}
+		return cols
+	}
+	if schema := d.catalog.lookup(table); schema != nil {
+		for _, col := range schema.columns {
+			cols = append(cols, ast.NewCIStr(col))
+		}
+	}
+	return cols
+}
+
+// addUpsertIssue 记录 upsert 写法的问题
+// 参数:
+//   - pattern: 规则的 pattern
+//   - rule: 匹配的规则
+//   - clause: 改写后的 ON CONFLICT 子句，为空表示无法自动改写
+//   - detail: 冲突目标的来源、无法改写的原因或语义差异
+func (d *DMLChecker) addUpsertIssue(pattern string, rule config.Rule, clause, detail string) {
+	issue := model.Issue{
+		Checker: "DMLChecker",
+		Message: fmt.Sprintf("DML %s: %s (建议: %s)，%s", pattern, rule.Description, rule.Then.Target, detail),
+	}
+	if clause != "" {
+		issue.AutoFix = model.AutoFix{
+			Available: true,
+			Action:    rule.Then.Action,
+			Code:      fmt.Sprintf("%s -> %s", pattern, clause),
+		}
+	}
+	d.AddIssue(issue)
+}
+
+// insertTable 返回 INSERT 的目标表，目标不是单个表时返回 nil
+func insertTable(n *ast.InsertStmt) *ast.TableName {
+	if n.Table == nil || n.Table.TableRefs == nil || n.Table.TableRefs.Right != nil {
+		return nil
+	}
+	source, ok := n.Table.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return nil
+	}
+	table, _ := source.Source.(*ast.TableName)
+	return table
+}
+
+// conflictClause 返回 ON CONFLICT 子句的描述，如 "ON CONFLICT (id) DO UPDATE"
+func conflictClause(target []string, action string) string {
+	return fmt.Sprintf("ON CONFLICT (%s) %s", strings.Join(target, ","), action)
+}
+
+// describeKeys 返回键列表的描述
+func describeKeys(keys []tableKey) string {
+	descs := make([]string, len(keys))
+	for i, key := range keys {
+		descs[i] = key.String()
+	}
+	return strings.Join(descs, "、")
+}
+
+// columnQualifier 为表达式中不带表名的列引用补充表名
+// ON CONFLICT DO UPDATE 中现有行与 EXCLUDED 的列同时可见，不带表名的列引用存在歧义。
+// VALUES(col) 与子查询内部的列引用保持不变。
+type columnQualifier struct {
+	table ast.CIStr
+}
+
+// Enter 实现 ast.Visitor 接口
+func (q *columnQualifier) Enter(n ast.Node) (ast.Node, bool) {
+	switch node := n.(type) {
+	case *ast.ValuesExpr, *ast.SubqueryExpr:
+		return n, true
+	case *ast.ColumnNameExpr:
+		if node.Name != nil && node.Name.Table.L == "" {
+			node.Name.Table = q.table
+		}
+	}
+	return n, false
+}
+
+// Leave 实现 ast.Visitor 接口
+func (q *columnQualifier) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
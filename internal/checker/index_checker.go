@@ -0,0 +1,424 @@
+package checker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+
+	"github.com/example/ybMigration/internal/config"
+	"github.com/example/ybMigration/internal/model"
+	sqlemitter "github.com/example/ybMigration/internal/sql-emitter"
+)
+
+// 索引规则的 pattern
+const (
+	patternInlineIndex = "INLINE INDEX"
+	patternFulltext    = "FULLTEXT"
+	patternPrefixIndex = "PREFIX INDEX"
+	patternIndexName   = "INDEX NAME"
+)
+
+// maxIdentLength YSQL 标识符的最大字节数，超出的部分会被截断
+const maxIdentLength = 63
+
+// IndexChecker 索引检查器
+// MySQL 的 CREATE TABLE 可以内联 KEY/INDEX、FULLTEXT 与前缀索引，YSQL 只接受内联的 PRIMARY KEY 与 UNIQUE 约束。
+// 检查器把普通索引从 CREATE TABLE（以及 ALTER TABLE ADD INDEX）中拆分为单独的 CREATE INDEX 语句；
+// 前缀索引改为 substr 表达式索引，索引宽度与唯一语义都与 MySQL 一致；
+// 全文索引省略并给出 tsvector + GIN 索引的改写建议。
+// MySQL 的索引名只需在表内唯一，YSQL 的索引与表共用 schema 内的命名空间，重名的索引按表名重命名，
+// 后续 DROP INDEX、RENAME INDEX 使用重命名后的名称。
+type IndexChecker struct {
+	*RuleChecker
+	relations map[string]bool              // schema 内已使用的表名与索引名，key 为 "schema.name"（小写），跨语句保留
+	indexes   map[string]map[string]string // 表（"schema.table"，小写）的 MySQL 索引名（小写）到 YSQL 索引名
+	locator   *locator                     // 当前语句的定位器
+	start     int                          // 当前索引子句在语句原文中的起始字节偏移量，问题定位到该子句
+	end       int                          // 当前索引子句在语句原文中的结束字节偏移量
+}
+
+// NewIndexChecker 创建索引检查器实例
+// 返回:
+//   - *IndexChecker: 初始化后的索引检查器实例
+//   - error: 错误信息
+func NewIndexChecker(cfg *config.Config) (*IndexChecker, error) {
+	ruleChecker, err := newRuleChecker("IndexChecker", "index", cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建索引检查器失败: %w", err)
+	}
+	return &IndexChecker{
+		RuleChecker: ruleChecker,
+		relations:   make(map[string]bool),
+		indexes:     make(map[string]map[string]string),
+	}, nil
+}
+
+// Name 返回检查器名称
+func (c *IndexChecker) Name() string { return "IndexChecker" }
+
+// Inspect 实现 Checker 接口
+// CREATE/ALTER TABLE 中的索引拆分为 CREATE INDEX，各类 DDL 用于维护 schema 内已使用的名称
+func (c *IndexChecker) Inspect(n ast.Node) (w ast.Node, skipChildren bool) {
+	switch node := n.(type) {
+	case *ast.CreateTableStmt:
+		c.checkCreateTable(node)
+	case *ast.AlterTableStmt:
+		c.checkAlterTable(node)
+	case *ast.CreateIndexStmt:
+		c.checkCreateIndex(node)
+	case *ast.DropIndexStmt:
+		node.IndexName = c.dropIndex(node.Table, node.IndexName)
+	case *ast.DropTableStmt:
+		if !node.IsView {
+			for _, table := range node.Tables {
+				c.dropTable(table)
+			}
+		}
+	case *ast.RenameTableStmt:
+		for _, t2t := range node.TableToTables {
+			c.renameTable(t2t.OldTable, t2t.NewTable)
+		}
+	}
+	return n, false
+}
+
+// checkCreateTable 拆分 CREATE TABLE 中的索引
+func (c *IndexChecker) checkCreateTable(n *ast.CreateTableStmt) {
+	if n.Table == nil {
+		return
+	}
+	c.dropTable(n.Table)
+	c.relations[relationKey(n.Table, n.Table.Name.L)] = true
+
+	// 约束节点没有原文偏移量，按原文顺序依次定位表名、列定义与各个索引子句
+	c.locator = newLocator(n)
+	c.locator.locate(n.Table)
+	for _, col := range n.Cols {
+		c.locator.locate(col)
+	}
+
+	var indexes []*ast.CreateIndexStmt
+	constraints := n.Constraints[:0]
+	for _, constraint := range n.Constraints {
+		c.start, c.end = c.locator.locateConstraint(constraint)
+		index, keep := c.checkConstraint(n.Table, constraint)
+		if index != nil {
+			indexes = append(indexes, index)
+		}
+		if keep {
+			constraints = append(constraints, constraint)
+		}
+	}
+	n.Constraints = constraints
+	if len(indexes) > 0 {
+		c.hints.SetIndexes(n, indexes)
+	}
+}
+
+// checkAlterTable 拆分 ALTER TABLE ADD INDEX 中的索引，DROP/RENAME INDEX 使用 YSQL 中的索引名
+func (c *IndexChecker) checkAlterTable(n *ast.AlterTableStmt) {
+	if n.Table == nil {
+		return
+	}
+	c.locator = newLocator(n)
+	c.start, c.end = c.locator.locate(n.Table)
+
+	var indexes []*ast.CreateIndexStmt
+	var renamed *ast.TableName
+	specs := n.Specs[:0]
+	for _, spec := range n.Specs {
+		switch spec.Tp {
+		case ast.AlterTableAddConstraint:
+			if spec.Constraint == nil {
+				break
+			}
+			c.start, c.end = c.locator.locateConstraint(spec.Constraint)
+			index, keep := c.checkConstraint(n.Table, spec.Constraint)
+			if index != nil {
+				indexes = append(indexes, index)
+			}
+			if !keep {
+				continue
+			}
+		case ast.AlterTableDropIndex:
+			spec.Name = c.dropIndex(n.Table, spec.Name)
+		case ast.AlterTableRenameIndex:
+			from := c.dropIndex(n.Table, spec.FromKey.O)
+			to := c.indexName(n.Table, spec.ToKey.O, nil, false)
+			spec.FromKey, spec.ToKey = ast.NewCIStr(from), ast.NewCIStr(to)
+		case ast.AlterTableRenameTable:
+			renamed = spec.NewTable
+		}
+		specs = append(specs, spec)
+	}
+	n.Specs = specs
+	if renamed != nil {
+		c.renameTable(n.Table, renamed)
+	}
+	if len(indexes) > 0 {
+		c.hints.SetIndexes(n, indexes)
+	}
+}
+
+// checkConstraint 检查表级约束中的索引
+// 返回:
+//   - *ast.CreateIndexStmt: 拆分出的 CREATE INDEX 语句，不需要拆分时为 nil
+//   - bool: 约束是否保留在原语句中
+func (c *IndexChecker) checkConstraint(table *ast.TableName, constraint *ast.Constraint) (*ast.CreateIndexStmt, bool) {
+	rules := c.GetRules()
+	switch constraint.Tp {
+	case ast.ConstraintKey, ast.ConstraintIndex:
+		rule, ok := rules[patternInlineIndex]
+		if !ok {
+			return nil, true
+		}
+		index := c.newIndex(table, constraint, false)
+		c.addIndexIssue(patternInlineIndex, rule, fmt.Sprintf("表 %s 的索引 %s 拆分为单独的 CREATE INDEX", table.Name.O, index.IndexName),
+			strings.TrimSpace("KEY "+constraint.Name), "CREATE INDEX "+index.IndexName)
+		c.checkPrefix(table, index.IndexName, index.IndexPartSpecifications, false)
+		return index, false
+	case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		// 带前缀长度或表达式的唯一键不能作为约束，转换为唯一索引
+		if _, ok := rules[patternInlineIndex]; ok && !plainColumns(constraint.Keys) {
+			index := c.newIndex(table, constraint, true)
+			c.checkPrefix(table, index.IndexName, index.IndexPartSpecifications, true)
+			return index, false
+		}
+		constraint.Name = c.indexName(table, constraint.Name, constraint.Keys, true)
+		return nil, true
+	case ast.ConstraintFulltext:
+		rule, ok := rules[patternFulltext]
+		if !ok {
+			return nil, true
+		}
+		c.AddIssue(c.locator.annotate(model.Issue{
+			Checker: "IndexChecker",
+			Message: fmt.Sprintf("索引 %s: %s (建议: %s)，表 %s 的全文索引 %s 已省略；MATCH ... AGAINST 查询需要改写为 to_tsvector(...) @@ to_tsquery(...)，可以建立 GIN 索引: %s",
+				patternFulltext, rule.Description, rule.Then.Target, table.Name.O, constraint.Name, fulltextSuggestion(table, constraint)),
+		}, 0, c.start, c.end))
+		return nil, false
+	}
+	return nil, true
+}
+
+// checkCreateIndex 检查单独的 CREATE INDEX 语句的索引名与前缀长度
+func (c *IndexChecker) checkCreateIndex(n *ast.CreateIndexStmt) {
+	if n.Table == nil || (n.KeyType != ast.IndexKeyTypeNone && n.KeyType != ast.IndexKeyTypeUnique) {
+		return
+	}
+	c.locator = newLocator(n)
+	c.start, c.end = c.locator.locate(n)
+	unique := n.KeyType == ast.IndexKeyTypeUnique
+	n.IndexName = c.indexName(n.Table, n.IndexName, n.IndexPartSpecifications, unique)
+	c.checkPrefix(n.Table, n.IndexName, n.IndexPartSpecifications, unique)
+}
+
+// newIndex 由表级约束创建 CREATE INDEX 语句
+func (c *IndexChecker) newIndex(table *ast.TableName, constraint *ast.Constraint, unique bool) *ast.CreateIndexStmt {
+	index := &ast.CreateIndexStmt{
+		IndexName:               c.indexName(table, constraint.Name, constraint.Keys, unique),
+		Table:                   table,
+		IndexPartSpecifications: constraint.Keys,
+		IndexOption:             constraint.Option,
+	}
+	if unique {
+		index.KeyType = ast.IndexKeyTypeUnique
+	}
+	return index
+}
+
+// checkPrefix 转换索引中的前缀长度
+// 前缀列改为 substr(col, 1, N) 表达式，索引只保存前缀，不会因长列值超出索引项大小；唯一索引保持只比较前缀的唯一语义。
+// MySQL 的前缀长度对字符串按字符计、对二进制串按字节计，与 YSQL 的 substr 对 TEXT 与 BYTEA 的行为一致
+// （left 不接受 BYTEA，因此不使用 left）。
+func (c *IndexChecker) checkPrefix(table *ast.TableName, name string, parts []*ast.IndexPartSpecification, unique bool) {
+	rule, ok := c.GetRules()[patternPrefixIndex]
+	if !ok {
+		return
+	}
+	for _, part := range parts {
+		if part.Column == nil || part.Length <= 0 {
+			continue
+		}
+		from := fmt.Sprintf("%s(%d)", part.Column.Name.O, part.Length)
+		prefix := fmt.Sprintf("substr(%s, 1, %d)", part.Column.Name.O, part.Length)
+		to := "(" + prefix + ")"
+		var detail string
+		if unique {
+			detail = fmt.Sprintf("表 %s 的唯一索引 %s 的前缀列 %s 转换为表达式 %s，保持按前缀判断唯一的语义；查询需要使用相同表达式才能使用该索引",
+				table.Name.O, name, from, to)
+		} else {
+			detail = fmt.Sprintf("表 %s 的索引 %s 的前缀列 %s 转换为表达式索引 %s；按该列过滤的查询需要改写为 %s = ... 才能使用该索引",
+				table.Name.O, name, from, to, prefix)
+		}
+		part.Expr = &ast.FuncCallExpr{
+			FnName: ast.NewCIStr("substr"),
+			Args:   []ast.ExprNode{&ast.ColumnNameExpr{Name: part.Column}, ast.NewValueExpr(1, "", ""), ast.NewValueExpr(part.Length, "", "")},
+		}
+		part.Column = nil
+		part.Length = 0
+		c.addIndexIssue(patternPrefixIndex, rule, detail, from, to)
+	}
+}
+
+// indexName 确定索引在 YSQL 中的名称并登记
+// 未命名的索引按 MySQL 的规则以第一列命名登记，YSQL 中的名称为 <表名>_<列名>_idx（唯一索引为 _key）；
+// 与 schema 中已有的表名或索引名重复时改为 <表名>_<索引名>，仍然重复时追加 _2、_3 等序号。
+// 参数:
+//   - table: 索引所属的表
+//   - name: MySQL 索引名，可以为空
+//   - parts: 索引列，用于为未命名的索引生成名称
+//   - unique: 是否为唯一索引
+//
+// 返回:
+//   - string: YSQL 中的索引名
+func (c *IndexChecker) indexName(table *ast.TableName, name string, parts []*ast.IndexPartSpecification, unique bool) string {
+	key := relationKey(table, table.Name.L)
+	names := c.indexes[key]
+	if names == nil {
+		names = make(map[string]string)
+		c.indexes[key] = names
+	}
+
+	mysqlName, ysqlName := name, fitIdent(name, "")
+	if name == "" {
+		// MySQL 以第一列命名未命名的索引，重名时追加 _2、_3 等序号；表达式索引命名为 functional_index
+		column := "functional_index"
+		if len(parts) > 0 && parts[0].Column != nil {
+			column = parts[0].Column.Name.O
+		}
+		mysqlName = column
+		for i := 2; names[strings.ToLower(mysqlName)] != ""; i++ {
+			mysqlName = column + "_" + strconv.Itoa(i)
+		}
+		suffix := "_idx"
+		if unique {
+			suffix = "_key"
+		}
+		ysqlName = fitIdent(table.Name.O+"_"+mysqlName, suffix)
+	}
+
+	if c.relations[relationKey(table, ysqlName)] {
+		base := table.Name.O + "_" + mysqlName
+		ysqlName = fitIdent(base, "")
+		for i := 2; c.relations[relationKey(table, ysqlName)]; i++ {
+			ysqlName = fitIdent(base, "_"+strconv.Itoa(i))
+		}
+		if rule, ok := c.GetRules()[patternIndexName]; ok && name != "" {
+			c.addIndexIssue(patternIndexName, rule,
+				fmt.Sprintf("表 %s 的索引 %s 与 schema 中已有的表或索引重名，重命名为 %s", table.Name.O, name, ysqlName), name, ysqlName)
+		}
+	}
+
+	names[strings.ToLower(mysqlName)] = ysqlName
+	c.relations[relationKey(table, ysqlName)] = true
+	return ysqlName
+}
+
+// dropIndex 删除索引的登记
+// 返回:
+//   - string: YSQL 中的索引名，未登记的索引返回原名称
+func (c *IndexChecker) dropIndex(table *ast.TableName, name string) string {
+	if table == nil {
+		return name
+	}
+	names := c.indexes[relationKey(table, table.Name.L)]
+	ysqlName, ok := names[strings.ToLower(name)]
+	if !ok {
+		return name
+	}
+	delete(names, strings.ToLower(name))
+	delete(c.relations, relationKey(table, ysqlName))
+	return ysqlName
+}
+
+// dropTable 删除表及其索引的登记
+func (c *IndexChecker) dropTable(table *ast.TableName) {
+	key := relationKey(table, table.Name.L)
+	for _, ysqlName := range c.indexes[key] {
+		delete(c.relations, relationKey(table, ysqlName))
+	}
+	delete(c.indexes, key)
+	delete(c.relations, key)
+}
+
+// renameTable 将表及其索引登记到新表名下，YSQL 重命名表时索引名保持不变
+func (c *IndexChecker) renameTable(from, to *ast.TableName) {
+	if from == nil || to == nil {
+		return
+	}
+	fromKey, toKey := relationKey(from, from.Name.L), relationKey(to, to.Name.L)
+	if names, ok := c.indexes[fromKey]; ok {
+		delete(c.indexes, fromKey)
+		c.indexes[toKey] = names
+	}
+	if c.relations[fromKey] {
+		delete(c.relations, fromKey)
+		c.relations[toKey] = true
+	}
+}
+
+// addIndexIssue 记录可以自动转换的索引问题，问题定位到当前索引子句
+// 参数:
+//   - pattern: 规则的 pattern
+//   - rule: 匹配的规则
+//   - detail: 转换方式
+//   - from: 转换前的写法
+//   - to: 转换后的写法
+func (c *IndexChecker) addIndexIssue(pattern string, rule config.Rule, detail, from, to string) {
+	c.AddIssue(c.locator.annotate(model.Issue{
+		Checker: "IndexChecker",
+		Message: fmt.Sprintf("索引 %s: %s (建议: %s)，%s", pattern, rule.Description, rule.Then.Target, detail),
+		AutoFix: model.AutoFix{
+			Available: true,
+			Action:    rule.Then.Action,
+			Code:      fmt.Sprintf("%s -> %s", from, to),
+		},
+	}, 0, c.start, c.end))
+}
+
+// fulltextSuggestion 返回代替全文索引的 GIN 索引语句
+func fulltextSuggestion(table *ast.TableName, constraint *ast.Constraint) string {
+	var columns []string
+	for _, part := range constraint.Keys {
+		if part.Column != nil {
+			columns = append(columns, fmt.Sprintf("coalesce(%s, '')", sqlemitter.QuoteIdent(part.Column.Name.O)))
+		}
+	}
+	name := constraint.Name
+	if name == "" && len(constraint.Keys) > 0 && constraint.Keys[0].Column != nil {
+		name = constraint.Keys[0].Column.Name.O
+	}
+	return fmt.Sprintf("CREATE INDEX %s ON %s USING GIN (to_tsvector('simple', %s))",
+		sqlemitter.QuoteIdent(fitIdent(table.Name.O+"_"+name, "_gin")), sqlemitter.QuoteIdent(table.Name.O), strings.Join(columns, " || ' ' || "))
+}
+
+// plainColumns 判断索引列是否都是不带前缀长度的普通列
+func plainColumns(parts []*ast.IndexPartSpecification) bool {
+	for _, part := range parts {
+		if part.Column == nil || part.Length > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// relationKey 返回 schema 内的名称登记使用的 key，如 "shop.users"
+func relationKey(table *ast.TableName, name string) string {
+	return table.Schema.L + "." + strings.ToLower(name)
+}
+
+// fitIdent 返回小写的 base + suffix，超出 YSQL 标识符的最大长度时截断 base，不截断多字节字符
+func fitIdent(base, suffix string) string {
+	base = strings.ToLower(base)
+	if limit := maxIdentLength - len(suffix); len(base) > limit {
+		base = base[:limit]
+		for !utf8.ValidString(base) {
+			base = base[:len(base)-1]
+		}
+	}
+	return base + suffix
+}
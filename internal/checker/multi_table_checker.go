@@ -0,0 +1,283 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+
+	"github.com/example/ybMigration/internal/config"
+	"github.com/example/ybMigration/internal/model"
+	sqlemitter "github.com/example/ybMigration/internal/sql-emitter"
+)
+
+// 多表 DML 规则的 pattern
+const (
+	patternUpdateJoin = "UPDATE JOIN"
+	patternDeleteJoin = "DELETE JOIN"
+)
+
+// MultiTableChecker 多表 DML 检查器
+// MySQL 的多表 UPDATE（UPDATE a JOIN b ... SET ...）与多表 DELETE（DELETE a FROM a JOIN b ...）
+// 在 YSQL 中没有对应写法，改写为 UPDATE ... FROM ... WHERE 与 DELETE ... USING ... WHERE：
+// 被修改的表作为目标表，其余表移到 FROM/USING 中，内连接的 ON 条件并入 WHERE。
+// 外连接、USING/NATURAL 连接、ORDER BY/LIMIT 以及同时修改多个表的语句无法等价改写，报告为需要人工处理。
+type MultiTableChecker struct {
+	*RuleChecker
+	catalog *schemaCatalog // 从 DDL 收集的表结构，用于确定不带表名的 SET 列所属的表
+}
+
+// NewMultiTableChecker 创建多表 DML 检查器实例
+// 返回:
+//   - *MultiTableChecker: 初始化后的多表 DML 检查器实例
+//   - error: 错误信息
+func NewMultiTableChecker(cfg *config.Config) (*MultiTableChecker, error) {
+	ruleChecker, err := newRuleChecker("MultiTableChecker", "multitable", cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建多表DML检查器失败: %w", err)
+	}
+	return &MultiTableChecker{
+		RuleChecker: ruleChecker,
+		catalog:     newSchemaCatalog(),
+	}, nil
+}
+
+// Name 返回检查器名称
+func (m *MultiTableChecker) Name() string { return "MultiTableChecker" }
+
+// Inspect 实现 Checker 接口
+// DDL 语句用于收集表结构，多表 UPDATE/DELETE 记录改写后的结构供 SQL 生成器输出
+func (m *MultiTableChecker) Inspect(n ast.Node) (w ast.Node, skipChildren bool) {
+	switch node := n.(type) {
+	case *ast.UpdateStmt:
+		if node.MultipleTable || isJoinedRefs(node.TableRefs) {
+			if rule, ok := m.GetRules()[patternUpdateJoin]; ok {
+				m.checkUpdate(node, rule)
+			}
+		}
+	case *ast.DeleteStmt:
+		if node.IsMultiTable || isJoinedRefs(node.TableRefs) {
+			if rule, ok := m.GetRules()[patternDeleteJoin]; ok {
+				m.checkDelete(node, rule)
+			}
+		}
+	case *ast.CreateTableStmt, *ast.AlterTableStmt, *ast.CreateIndexStmt, *ast.DropTableStmt, *ast.RenameTableStmt:
+		m.catalog.learn(node)
+	}
+	return n, false
+}
+
+// checkUpdate 将多表 UPDATE 改写为 UPDATE ... FROM
+// SET 修改的列必须属于同一个表，该表作为目标表
+func (m *MultiTableChecker) checkUpdate(n *ast.UpdateStmt, rule config.Rule) {
+	if n.Order != nil || n.Limit != nil {
+		m.addJoinIssue(patternUpdateJoin, rule, false, "多表 UPDATE 的 ORDER BY/LIMIT 没有对应写法，需要手工改写为基于主键子查询的 UPDATE")
+		return
+	}
+	tables, conditions, reason := flattenJoin(n.TableRefs.TableRefs)
+	if reason != "" {
+		m.addJoinIssue(patternUpdateJoin, rule, false, reason+"，需要手工改写为 UPDATE ... FROM")
+		return
+	}
+
+	var target *ast.TableSource
+	for _, assign := range n.List {
+		owner, reason := m.columnOwner(assign.Column, tables)
+		if reason == "" && target != nil && owner != target {
+			reason = fmt.Sprintf("SET 同时修改了 %s 与 %s 两个表，YSQL 的 UPDATE 只能修改一个表", tableRefName(target), tableRefName(owner))
+		}
+		if reason != "" {
+			m.addJoinIssue(patternUpdateJoin, rule, false, reason+"，需要手工改写为 UPDATE ... FROM")
+			return
+		}
+		target = owner
+	}
+	if target == nil {
+		return
+	}
+
+	m.hints.SetJoinedDML(n, sqlemitter.JoinedDML{Target: target, Tables: withoutTable(tables, target), Conditions: conditions})
+	m.addJoinIssue(patternUpdateJoin, rule, true, "目标表为 "+tableRefName(target)+"，其余表移到 FROM 中，连接条件并入 WHERE")
+}
+
+// checkDelete 将多表 DELETE 改写为 DELETE ... USING
+// DELETE 与 FROM 之间（或 FROM 与 USING 之间）只能列出一个要删除的表
+func (m *MultiTableChecker) checkDelete(n *ast.DeleteStmt, rule config.Rule) {
+	if n.Order != nil || n.Limit != nil {
+		m.addJoinIssue(patternDeleteJoin, rule, false, "多表 DELETE 的 ORDER BY/LIMIT 没有对应写法，需要手工改写为基于主键子查询的 DELETE")
+		return
+	}
+	tables, conditions, reason := flattenJoin(n.TableRefs.TableRefs)
+	if reason != "" {
+		m.addJoinIssue(patternDeleteJoin, rule, false, reason+"，需要手工改写为 DELETE ... USING")
+		return
+	}
+
+	var target *ast.TableSource
+	switch {
+	case n.Tables == nil || len(n.Tables.Tables) == 0:
+		// 没有列出要删除的表时（DELETE FROM a JOIN b ...）只能删除单个表
+		if len(tables) == 1 {
+			target = tables[0]
+		} else {
+			reason = "没有指定要删除的表"
+		}
+	case len(n.Tables.Tables) > 1:
+		names := make([]string, len(n.Tables.Tables))
+		for i, table := range n.Tables.Tables {
+			names[i] = table.Name.O
+		}
+		reason = fmt.Sprintf("同时删除了 %s 多个表的行，YSQL 的 DELETE 只能删除一个表", strings.Join(names, "、"))
+	default:
+		if target = findTableRef(tables, n.Tables.Tables[0].Name); target == nil {
+			reason = fmt.Sprintf("要删除的表 %s 不在 FROM 子句中", n.Tables.Tables[0].Name.O)
+		}
+	}
+	if reason != "" {
+		m.addJoinIssue(patternDeleteJoin, rule, false, reason+"，需要手工改写为 DELETE ... USING")
+		return
+	}
+
+	m.hints.SetJoinedDML(n, sqlemitter.JoinedDML{Target: target, Tables: withoutTable(tables, target), Conditions: conditions})
+	m.addJoinIssue(patternDeleteJoin, rule, true, "目标表为 "+tableRefName(target)+"，其余表移到 USING 中，连接条件并入 WHERE")
+}
+
+// columnOwner 确定 SET 列所属的表
+// 带表名（或别名）的列按名称匹配；不带表名的列根据收集到的表结构查找唯一包含该列的表。
+//
+// 返回:
+//   - *ast.TableSource: 列所属的表
+//   - string: 无法确定时的原因
+func (m *MultiTableChecker) columnOwner(col *ast.ColumnName, tables []*ast.TableSource) (*ast.TableSource, string) {
+	if col.Table.L != "" {
+		if owner := findTableRef(tables, col.Table); owner != nil {
+			return owner, ""
+		}
+		return nil, fmt.Sprintf("SET 列 %s.%s 的表不在 FROM 子句中", col.Table.O, col.Name.O)
+	}
+
+	var owners []*ast.TableSource
+	for _, table := range tables {
+		name, ok := table.Source.(*ast.TableName)
+		if !ok {
+			continue
+		}
+		if schema := m.catalog.lookup(name); schema != nil {
+			for _, c := range schema.columns {
+				if c == col.Name.L {
+					owners = append(owners, table)
+					break
+				}
+			}
+		}
+	}
+	if len(owners) != 1 {
+		return nil, fmt.Sprintf("SET 列 %s 没有指定表名，无法确定所属的表", col.Name.O)
+	}
+	return owners[0], ""
+}
+
+// addJoinIssue 记录多表 DML 的问题
+// 参数:
+//   - pattern: 规则的 pattern
+//   - rule: 匹配的规则
+//   - rewritten: 是否已自动改写
+//   - detail: 改写方式或需要人工处理的原因
+func (m *MultiTableChecker) addJoinIssue(pattern string, rule config.Rule, rewritten bool, detail string) {
+	issue := model.Issue{
+		Checker: "MultiTableChecker",
+		Message: fmt.Sprintf("多表 DML %s: %s (建议: %s)，%s", pattern, rule.Description, rule.Then.Target, detail),
+	}
+	if rewritten {
+		issue.AutoFix = model.AutoFix{
+			Available: true,
+			Action:    rule.Then.Action,
+			Code:      fmt.Sprintf("%s -> %s", pattern, rule.Then.Target),
+		}
+	}
+	m.AddIssue(issue)
+}
+
+// flattenJoin 将内连接展开为表列表与连接条件
+// 返回:
+//   - []*ast.TableSource: 参与连接的表，按出现顺序
+//   - []*ast.OnCondition: 内连接的 ON 条件
+//   - string: 无法展开时的原因（外连接、USING/NATURAL 连接）
+func flattenJoin(node ast.ResultSetNode) ([]*ast.TableSource, []*ast.OnCondition, string) {
+	var tables []*ast.TableSource
+	var conditions []*ast.OnCondition
+	var walk func(node ast.ResultSetNode) string
+	walk = func(node ast.ResultSetNode) string {
+		switch n := node.(type) {
+		case *ast.Join:
+			if n.Right != nil {
+				switch {
+				case n.Tp == ast.LeftJoin || n.Tp == ast.RightJoin:
+					return "包含外连接，外连接的 ON 条件无法并入 WHERE"
+				case n.NaturalJoin || len(n.Using) > 0:
+					return "包含 NATURAL 或 USING 连接，需要先改写为 ON 条件"
+				}
+			}
+			if reason := walk(n.Left); reason != "" {
+				return reason
+			}
+			if n.Right != nil {
+				if reason := walk(n.Right); reason != "" {
+					return reason
+				}
+			}
+			if n.On != nil {
+				conditions = append(conditions, n.On)
+			}
+		case *ast.TableSource:
+			if join, ok := n.Source.(*ast.Join); ok {
+				return walk(join)
+			}
+			tables = append(tables, n)
+		default:
+			return fmt.Sprintf("不支持的表引用 %T", node)
+		}
+		return ""
+	}
+	if reason := walk(node); reason != "" {
+		return nil, nil, reason
+	}
+	return tables, conditions, ""
+}
+
+// findTableRef 按别名或表名查找表引用，有别名的表只按别名匹配
+func findTableRef(tables []*ast.TableSource, name ast.CIStr) *ast.TableSource {
+	for _, table := range tables {
+		if tableRefName(table) == name.L {
+			return table
+		}
+	}
+	return nil
+}
+
+// tableRefName 返回表引用在语句中的名称（别名或表名，小写）
+func tableRefName(table *ast.TableSource) string {
+	if table.AsName.L != "" {
+		return table.AsName.L
+	}
+	if name, ok := table.Source.(*ast.TableName); ok {
+		return name.Name.L
+	}
+	return ""
+}
+
+// withoutTable 返回去掉目标表后的表列表
+func withoutTable(tables []*ast.TableSource, target *ast.TableSource) []*ast.TableSource {
+	var others []*ast.TableSource
+	for _, table := range tables {
+		if table != target {
+			others = append(others, table)
+		}
+	}
+	return others
+}
+
+// isJoinedRefs 判断表引用是否包含多表连接
+func isJoinedRefs(refs *ast.TableRefsClause) bool {
+	return refs != nil && refs.TableRefs != nil && refs.TableRefs.Right != nil
+}
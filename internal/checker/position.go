@@ -0,0 +1,365 @@
+package checker
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+
+	"github.com/example/ybMigration/internal/model"
+	sqlparser "github.com/example/ybMigration/internal/sql-parser"
+)
+
+// locator 在语句原文中定位 AST 节点
+//
+// TiDB 解析器只为表达式节点记录原文偏移量（OriginTextPosition），
+// 列定义、表名、LIMIT 等节点没有偏移量，这些节点根据其名称或关键字
+// 从最近一次定位的位置向后搜索。遍历顺序与原文顺序基本一致，因此搜索结果通常准确。
+// 解析器不记录节点的结束位置，结束位置由节点子树中最后一个记号推算，见 extent。
+type locator struct {
+	text   string // 语句原文
+	cursor int    // 最近一次定位到的字节偏移量
+}
+
+// newLocator 为语句创建定位器
+func newLocator(stmt ast.StmtNode) *locator {
+	return &locator{text: stmt.Text()}
+}
+
+// locate 返回节点在语句原文中的字节范围 [start, end)，无法定位时 start 为最近一次定位的位置、范围为空
+func (l *locator) locate(node ast.Node) (start, end int) {
+	if l == nil || l.text == "" {
+		return 0, 0
+	}
+
+	if _, ok := node.(ast.StmtNode); ok {
+		// 子查询等嵌套语句从当前位置（子查询表达式的左括号）开始
+		if text := node.Text(); text != "" && strings.HasPrefix(l.text[l.cursor:], text) {
+			return l.cursor, l.cursor + len(text)
+		}
+		return l.cursor, l.extent(node, l.cursor, l.cursor)
+	}
+
+	if off := node.OriginTextPosition(); off > 0 && off < len(l.text) {
+		l.cursor = off
+		return off, l.extent(node, off, off)
+	}
+	// 多个候选关键字时取最先出现的一个，如未命名索引的 KEY 与 INDEX
+	found, width := -1, 0
+	for _, anchor := range nodeAnchors(node) {
+		if i := indexWord(l.text[l.cursor:], anchor); i >= 0 && (found < 0 || i < found) {
+			found, width = i, len(anchor)
+		}
+	}
+	if found < 0 {
+		return l.cursor, l.cursor
+	}
+	l.cursor += found
+	return l.cursor, l.extent(node, l.cursor, l.cursor+width)
+}
+
+// locateConstraint 返回表级约束在语句原文中的字节范围
+// 与遍历 AST 时一样依次定位约束及其索引列，使下一个子句从本子句之后开始搜索；
+// 用于在遍历到约束之前就修改或检查约束的检查器
+func (l *locator) locateConstraint(constraint *ast.Constraint) (start, end int) {
+	start, end = l.locate(constraint)
+	for _, key := range constraint.Keys {
+		if key.Column != nil {
+			l.locate(key.Column)
+		}
+	}
+	return start, end
+}
+
+// extent 返回节点在原文中的结束位置
+// 列定义与表级约束是以逗号分隔的子句，结束于括号外的逗号；表选项结束于选项值；
+// 其他节点结束于子树中最后一个记号之后，并补齐节点内未闭合的括号（如函数调用的右括号）。
+// 参数:
+//   - node: 节点
+//   - start: 节点起始的字节偏移量
+//   - end: 节点起始记号（关键字或名称）的结束位置，节点有原文偏移量时与 start 相同
+func (l *locator) extent(node ast.Node, start, end int) int {
+	switch n := node.(type) {
+	case *ast.SelectField:
+		if text := n.Text(); text != "" && strings.HasPrefix(l.text[start:], text) {
+			return start + len(text)
+		}
+	case *ast.ColumnDef, *ast.Constraint:
+		return sqlparser.ClauseEnd(l.text, start)
+	case *ast.TableOption:
+		return sqlparser.TokenEnd(l.text, l.skipAssign(end))
+	case *ast.ColumnOption:
+		if n.Tp == ast.ColumnOptionCollate {
+			return sqlparser.TokenEnd(l.text, l.skipAssign(end))
+		}
+	case *ast.Limit:
+		// LIMIT 的参数没有原文偏移量：LIMIT n、LIMIT m, n 或 LIMIT n OFFSET m
+		end = sqlparser.TokenEnd(l.text, l.skipSpace(end))
+		if i := l.skipSpace(end); i < len(l.text) && l.text[i] == ',' {
+			return sqlparser.TokenEnd(l.text, l.skipSpace(i+1))
+		} else if indexWord(l.text[i:], "OFFSET") == 0 {
+			return sqlparser.TokenEnd(l.text, l.skipSpace(i+len("OFFSET")))
+		}
+		return end
+	}
+
+	if end == start {
+		end = sqlparser.TokenEnd(l.text, start)
+	}
+	last, lastNode := lastOffset(node, len(l.text))
+	if last <= start {
+		last, lastNode = start, node
+	}
+	end = max(end, sqlparser.TokenEnd(l.text, last))
+	if isCall(lastNode) {
+		// 参数没有原文偏移量的函数调用，如 NOW()、CURRENT_TIMESTAMP(3)
+		if i := l.skipSpace(end); i < len(l.text) && l.text[i] == '(' {
+			end = sqlparser.TokenEnd(l.text, i)
+		}
+	}
+	end = sqlparser.CloseParens(l.text, start, end)
+
+	// 子树之后的关键字仍属于节点本身
+	switch n := node.(type) {
+	case *ast.IsNullExpr:
+		end = l.wordEnd(end, "NULL", 1)
+	case *ast.IsTruthExpr:
+		if n.True != 0 {
+			end = l.wordEnd(end, "TRUE", 1)
+		} else {
+			end = l.wordEnd(end, "FALSE", 1)
+		}
+	case *ast.CaseExpr:
+		// 嵌套的 CASE 也各自以 END 结束
+		within := asciiLower(l.text[start:end])
+		end = l.wordEnd(end, "END", countWord(within, "case")-countWord(within, "end"))
+	}
+	return end
+}
+
+// skipSpace 返回从偏移量开始的第一个非空白字符的位置
+func (l *locator) skipSpace(offset int) int {
+	for offset < len(l.text) && strings.IndexByte(" \t\r\n", l.text[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// skipAssign 跳过选项名之后的空白与可选的等号，返回选项值的起始位置
+func (l *locator) skipAssign(offset int) int {
+	offset = l.skipSpace(offset)
+	if offset < len(l.text) && l.text[offset] == '=' {
+		offset = l.skipSpace(offset + 1)
+	}
+	return offset
+}
+
+// wordEnd 返回偏移量之后第 n 个完整单词 word 的结束位置，未找到时返回原偏移量
+func (l *locator) wordEnd(offset int, word string, n int) int {
+	end := offset
+	for ; n > 0; n-- {
+		i := indexWord(l.text[end:], word)
+		if i < 0 {
+			return offset
+		}
+		end += i + len(word)
+	}
+	return end
+}
+
+// annotate 为节点上产生的问题补充语句内的位置信息
+// 参数:
+//   - issue: 问题
+//   - stmtIndex: 语句在本次检查中的序号（从 1 开始）
+//   - start: 节点在语句原文中的起始字节偏移量
+//   - end: 节点在语句原文中的结束字节偏移量，片段取 [start, end) 的原文
+func (l *locator) annotate(issue model.Issue, stmtIndex, start, end int) model.Issue {
+	if issue.StatementIndex == 0 {
+		issue.StatementIndex = stmtIndex
+	}
+	if issue.Line == 0 && l != nil && l.text != "" {
+		issue.Line, issue.Column = sqlparser.OffsetPosition(l.text, start)
+		issue.Snippet = sqlparser.Snippet(l.text, start, end)
+	}
+	return issue
+}
+
+// lastOffset 返回节点子树中原文偏移量最大的节点及其偏移量，limit 为语句原文的长度
+func lastOffset(node ast.Node, limit int) (int, ast.Node) {
+	v := &lastOffsetVisitor{limit: limit}
+	node.Accept(v)
+	return v.last, v.node
+}
+
+// lastOffsetVisitor 记录子树中原文偏移量最大的节点
+type lastOffsetVisitor struct {
+	limit int
+	last  int
+	node  ast.Node
+}
+
+// Enter 实现 ast.Visitor 接口
+func (v *lastOffsetVisitor) Enter(n ast.Node) (ast.Node, bool) {
+	if off := n.OriginTextPosition(); off < v.limit && off > v.last {
+		v.last, v.node = off, n
+	}
+	return n, false
+}
+
+// isCall 判断节点是否为函数调用，其参数列表紧跟在函数名之后
+func isCall(node ast.Node) bool {
+	switch node.(type) {
+	case *ast.FuncCallExpr, *ast.AggregateFuncExpr, *ast.WindowFuncExpr:
+		return true
+	}
+	return false
+}
+
+// Leave 实现 ast.Visitor 接口
+func (v *lastOffsetVisitor) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+// nodeAnchors 返回没有原文偏移量的节点在原文中可能出现的名称或关键字
+func nodeAnchors(node ast.Node) []string {
+	switch n := node.(type) {
+	case *ast.SelectField:
+		if text := n.Text(); text != "" {
+			return []string{text}
+		}
+	case *ast.ColumnDef:
+		if n.Name != nil {
+			return []string{n.Name.Name.O}
+		}
+	case *ast.ColumnName:
+		return []string{n.Name.O}
+	case *ast.TableName:
+		return []string{n.Name.O}
+	case *ast.Limit:
+		return []string{"LIMIT"}
+	case *ast.ColumnOption:
+		return columnOptionAnchors(n)
+	case *ast.TableOption:
+		return tableOptionAnchors(n)
+	case *ast.Constraint:
+		// 优先使用关键字：索引名常与列名相同（如 KEY name (name)），按名称搜索会定位到列定义
+		if anchors := constraintAnchors(n); anchors != nil {
+			return anchors
+		}
+		if n.Name != "" {
+			return []string{n.Name}
+		}
+	}
+	return nil
+}
+
+// columnOptionAnchors 返回列选项对应的关键字
+func columnOptionAnchors(n *ast.ColumnOption) []string {
+	switch n.Tp {
+	case ast.ColumnOptionAutoIncrement:
+		return []string{"AUTO_INCREMENT"}
+	case ast.ColumnOptionDefaultValue:
+		return []string{"DEFAULT"}
+	case ast.ColumnOptionOnUpdate:
+		return []string{"ON UPDATE"}
+	case ast.ColumnOptionComment:
+		return []string{"COMMENT"}
+	case ast.ColumnOptionCollate:
+		return []string{"COLLATE"}
+	case ast.ColumnOptionPrimaryKey:
+		return []string{"PRIMARY"}
+	case ast.ColumnOptionUniqKey:
+		return []string{"UNIQUE"}
+	case ast.ColumnOptionNotNull, ast.ColumnOptionNull:
+		return []string{"NULL"}
+	case ast.ColumnOptionGenerated:
+		return []string{"AS"}
+	case ast.ColumnOptionCheck:
+		return []string{"CHECK"}
+	}
+	return nil
+}
+
+// tableOptionAnchors 返回表选项对应的关键字
+func tableOptionAnchors(n *ast.TableOption) []string {
+	switch n.Tp {
+	case ast.TableOptionEngine:
+		return []string{"ENGINE"}
+	case ast.TableOptionCharset:
+		return []string{"CHARSET", "CHARACTER SET"}
+	case ast.TableOptionCollate:
+		return []string{"COLLATE"}
+	case ast.TableOptionAutoIncrement:
+		return []string{"AUTO_INCREMENT"}
+	case ast.TableOptionComment:
+		return []string{"COMMENT"}
+	case ast.TableOptionRowFormat:
+		return []string{"ROW_FORMAT"}
+	}
+	return nil
+}
+
+// constraintAnchors 返回约束对应的关键字
+func constraintAnchors(n *ast.Constraint) []string {
+	switch n.Tp {
+	case ast.ConstraintPrimaryKey:
+		return []string{"PRIMARY"}
+	case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		return []string{"UNIQUE"}
+	case ast.ConstraintForeignKey:
+		return []string{"FOREIGN"}
+	case ast.ConstraintFulltext:
+		return []string{"FULLTEXT"}
+	case ast.ConstraintCheck:
+		return []string{"CHECK"}
+	case ast.ConstraintKey, ast.ConstraintIndex:
+		return []string{"KEY", "INDEX"}
+	}
+	return nil
+}
+
+// indexWord 不区分大小写地查找完整单词，返回字节偏移量，未找到返回 -1
+func indexWord(text, word string) int {
+	if word == "" {
+		return -1
+	}
+	// 只转换 ASCII 字母，保证字节偏移量与原文一致
+	lower, target := asciiLower(text), asciiLower(word)
+	for from := 0; from <= len(lower)-len(target); {
+		i := strings.Index(lower[from:], target)
+		if i < 0 {
+			return -1
+		}
+		start, end := from+i, from+i+len(target)
+		if (start == 0 || !isWordByte(lower[start-1])) && (end == len(lower) || !isWordByte(lower[end])) {
+			return start
+		}
+		from = start + 1
+	}
+	return -1
+}
+
+// countWord 统计完整单词在文本中出现的次数，text 与 word 需为小写
+func countWord(text, word string) int {
+	n := 0
+	for i := indexWord(text, word); i >= 0; i = indexWord(text, word) {
+		n++
+		text = text[i+len(word):]
+	}
+	return n
+}
+
+// asciiLower 将 ASCII 大写字母转为小写，其他字节保持不变
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// isWordByte 判断字节是否属于标识符
+func isWordByte(b byte) bool {
+	return b == '_' || b == '$' || b >= 'a' && b <= 'z' || b >= '0' && b <= '9'
+}
@@ -0,0 +1,381 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
+
+	"github.com/example/ybMigration/internal/config"
+	"github.com/example/ybMigration/internal/model"
+	sqlemitter "github.com/example/ybMigration/internal/sql-emitter"
+)
+
+// 分片规则的 pattern
+const (
+	patternSequentialKey = "SEQUENTIAL PRIMARY KEY"
+	patternRangeScan     = "RANGE SCAN"
+)
+
+// ShardingChecker YugabyteDB 分片检查器
+// YugabyteDB 按主键把表拆分到多个 tablet：HASH 分片把相邻的键分散到不同 tablet，
+// ASC/DESC 范围分片保持键的顺序。单调递增的主键（AUTO_INCREMENT、时间开头、序号列）在范围分片时
+// 所有写入集中在最后一个 tablet，检查器建议以 HASH 分片或 HASH/范围组合的复合主键定义；
+// YSQL 的主键与索引默认按第一列 HASH 分片，无法按顺序扫描，DML 中用于范围条件或 ORDER BY 的列
+// 建议建立 ASC 二级索引。检查器只给出建议，不修改输出。
+type ShardingChecker struct {
+	*RuleChecker
+	catalog    *schemaCatalog                       // 从 DDL 收集的表结构，用于确定不带表名的列所属的表
+	sequential map[string]map[string]sequentialKind // 表名（小写）的单调递增列（小写），跨语句保留
+	advised    map[string]bool                      // 已建议 ASC 索引的 "表.列"，每列只建议一次
+	scope      map[string]string                    // 当前语句中的别名或表名到表名的映射
+}
+
+// sequentialKind 列单调递增的原因
+type sequentialKind string
+
+// 单调递增列的类型
+const (
+	sequentialAutoIncrement sequentialKind = "自增列（AUTO_INCREMENT）"
+	sequentialTemporal      sequentialKind = "时间类型列"
+	sequentialNamed         sequentialKind = "序号列"
+)
+
+// NewShardingChecker 创建分片检查器实例
+// 返回:
+//   - *ShardingChecker: 初始化后的分片检查器实例
+//   - error: 错误信息
+func NewShardingChecker(cfg *config.Config) (*ShardingChecker, error) {
+	ruleChecker, err := newRuleChecker("ShardingChecker", "sharding", cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建分片检查器失败: %w", err)
+	}
+	return &ShardingChecker{
+		RuleChecker: ruleChecker,
+		catalog:     newSchemaCatalog(),
+		sequential:  make(map[string]map[string]sequentialKind),
+		advised:     make(map[string]bool),
+	}, nil
+}
+
+// Name 返回检查器名称
+func (s *ShardingChecker) Name() string { return "ShardingChecker" }
+
+// Reset 重置检查器状态，表结构与已建议的列跨语句保留
+func (s *ShardingChecker) Reset() {
+	s.RuleChecker.Reset()
+	s.scope = nil
+}
+
+// Inspect 实现 Checker 接口
+// DDL 语句用于收集表结构并检查主键，DML 语句中的范围条件与 ORDER BY 用于建议 ASC 索引
+func (s *ShardingChecker) Inspect(n ast.Node) (w ast.Node, skipChildren bool) {
+	switch node := n.(type) {
+	case *ast.CreateTableStmt:
+		s.catalog.learn(node)
+		s.checkCreateTable(node)
+	case *ast.AlterTableStmt:
+		s.catalog.learn(node)
+		s.checkAlterTable(node)
+	case *ast.CreateIndexStmt:
+		s.catalog.learn(node)
+	case *ast.DropTableStmt:
+		s.catalog.learn(node)
+		if !node.IsView {
+			for _, table := range node.Tables {
+				delete(s.sequential, table.Name.L)
+			}
+		}
+	case *ast.RenameTableStmt:
+		s.catalog.learn(node)
+		for _, t2t := range node.TableToTables {
+			s.renameTable(t2t.OldTable, t2t.NewTable)
+		}
+	case *ast.SelectStmt, *ast.SetOprStmt, *ast.UpdateStmt, *ast.DeleteStmt, *ast.InsertStmt:
+		s.enterStatement(node)
+	case *ast.BinaryOperationExpr:
+		s.checkRangeComparison(node)
+	case *ast.BetweenExpr:
+		if col, ok := node.Expr.(*ast.ColumnNameExpr); ok {
+			s.adviseAscIndex(col.Name, "范围条件 BETWEEN")
+		}
+	case *ast.OrderByClause:
+		for _, item := range node.Items {
+			if col, ok := item.Expr.(*ast.ColumnNameExpr); ok {
+				s.adviseAscIndex(col.Name, "ORDER BY")
+			}
+		}
+	}
+	return n, false
+}
+
+// checkCreateTable 记录单调递增列并检查主键
+func (s *ShardingChecker) checkCreateTable(n *ast.CreateTableStmt) {
+	if n.Table == nil {
+		return
+	}
+	columns := make(map[string]sequentialKind)
+	if n.ReferTable != nil {
+		for col, kind := range s.sequential[n.ReferTable.Name.L] {
+			columns[col] = kind
+		}
+	}
+	// 按原文顺序依次定位表名、列定义与约束，问题定位到定义主键的列或 PRIMARY KEY 子句
+	l := newLocator(n)
+	l.locate(n.Table)
+	var primary []string
+	var start, end int
+	for _, col := range n.Cols {
+		colStart, colEnd := l.locate(col)
+		if kind, ok := sequentialColumn(col); ok {
+			columns[col.Name.Name.L] = kind
+		}
+		for _, opt := range col.Options {
+			if opt.Tp == ast.ColumnOptionPrimaryKey {
+				primary = []string{col.Name.Name.O}
+				start, end = colStart, colEnd
+			}
+		}
+	}
+	for _, constraint := range n.Constraints {
+		constraintStart, constraintEnd := l.locateConstraint(constraint)
+		if constraint.Tp == ast.ConstraintPrimaryKey {
+			primary = keyColumnNames(constraint.Keys)
+			start, end = constraintStart, constraintEnd
+		}
+	}
+	s.sequential[n.Table.Name.L] = columns
+	s.checkPrimaryKey(n.Table, primary, l, start, end)
+}
+
+// checkAlterTable 记录新增或修改的单调递增列，检查 ADD PRIMARY KEY 定义的主键
+func (s *ShardingChecker) checkAlterTable(n *ast.AlterTableStmt) {
+	if n.Table == nil {
+		return
+	}
+	columns := s.sequential[n.Table.Name.L]
+	if columns == nil {
+		columns = make(map[string]sequentialKind)
+		s.sequential[n.Table.Name.L] = columns
+	}
+	l := newLocator(n)
+	l.locate(n.Table)
+	var primary []string
+	var start, end int
+	var renamed *ast.TableName
+	for _, spec := range n.Specs {
+		switch spec.Tp {
+		case ast.AlterTableAddColumns, ast.AlterTableModifyColumn, ast.AlterTableChangeColumn:
+			if spec.OldColumnName != nil {
+				delete(columns, spec.OldColumnName.Name.L)
+			}
+			for _, col := range spec.NewColumns {
+				l.locate(col)
+				delete(columns, col.Name.Name.L)
+				if kind, ok := sequentialColumn(col); ok {
+					columns[col.Name.Name.L] = kind
+				}
+			}
+		case ast.AlterTableAddConstraint:
+			if spec.Constraint != nil && spec.Constraint.Tp == ast.ConstraintPrimaryKey {
+				primary = keyColumnNames(spec.Constraint.Keys)
+				start, end = l.locateConstraint(spec.Constraint)
+			}
+		case ast.AlterTableRenameTable:
+			renamed = spec.NewTable
+		}
+	}
+	s.checkPrimaryKey(n.Table, primary, l, start, end)
+	if renamed != nil {
+		s.renameTable(n.Table, renamed)
+	}
+}
+
+// checkPrimaryKey 检查主键的第一列是否单调递增
+// 单列主键建议 PRIMARY KEY (col HASH)；复合主键建议非递增列作为 HASH 分片列、递增列按 ASC 排序，
+// 如 PRIMARY KEY (device_id HASH, created_at ASC)，全部列都递增时以整个主键作为 HASH 分片列。
+// 参数:
+//   - table: 表名
+//   - primary: 主键列名
+//   - l: 语句的定位器
+//   - start, end: 定义主键的列或 PRIMARY KEY 子句在语句原文中的字节范围
+func (s *ShardingChecker) checkPrimaryKey(table *ast.TableName, primary []string, l *locator, start, end int) {
+	rule, ok := s.GetRules()[patternSequentialKey]
+	if !ok || len(primary) == 0 {
+		return
+	}
+	columns := s.sequential[table.Name.L]
+	kind, ok := columns[strings.ToLower(primary[0])]
+	if !ok {
+		return
+	}
+
+	var hash, rng []string
+	for _, col := range primary {
+		if _, ok := columns[strings.ToLower(col)]; ok {
+			rng = append(rng, sqlemitter.QuoteIdent(col)+" ASC")
+		} else {
+			hash = append(hash, sqlemitter.QuoteIdent(col))
+		}
+	}
+	var layout string
+	switch {
+	case len(primary) == 1:
+		layout = sqlemitter.QuoteIdent(primary[0]) + " HASH"
+	case len(hash) == 0:
+		quoted := make([]string, len(primary))
+		for i, col := range primary {
+			quoted[i] = sqlemitter.QuoteIdent(col)
+		}
+		layout = "(" + strings.Join(quoted, ", ") + ") HASH"
+	case len(hash) == 1:
+		layout = strings.Join(append([]string{hash[0] + " HASH"}, rng...), ", ")
+	default:
+		layout = strings.Join(append([]string{"(" + strings.Join(hash, ", ") + ") HASH"}, rng...), ", ")
+	}
+
+	s.AddIssue(l.annotate(model.Issue{
+		Checker: "ShardingChecker",
+		Message: fmt.Sprintf("分片 %s: %s (建议: %s)，表 %s 的主键第一列 %s 是%s，按 ASC/DESC 范围分片时写入集中在最后一个 tablet；建议显式定义为 PRIMARY KEY (%s)",
+			patternSequentialKey, rule.Description, rule.Then.Target, table.Name.O, primary[0], kind, layout),
+	}, 0, start, end))
+}
+
+// enterStatement 记录语句中出现的表及其别名，用于确定范围条件中的列所属的表
+// 每次 `Check` 只处理一条语句，子查询等嵌套语句沿用最外层语句的记录
+func (s *ShardingChecker) enterStatement(stmt ast.Node) {
+	if s.scope != nil {
+		return
+	}
+	collector := &tableCollector{tables: make(map[string]string)}
+	stmt.Accept(collector)
+	s.scope = collector.tables
+}
+
+// checkRangeComparison 检查列与非列表达式的 <、<=、>、>= 比较
+func (s *ShardingChecker) checkRangeComparison(n *ast.BinaryOperationExpr) {
+	switch n.Op {
+	case opcode.LT, opcode.LE, opcode.GT, opcode.GE:
+	default:
+		return
+	}
+	var op strings.Builder
+	n.Op.Format(&op)
+	left, leftCol := n.L.(*ast.ColumnNameExpr)
+	right, rightCol := n.R.(*ast.ColumnNameExpr)
+	switch {
+	case leftCol && !rightCol:
+		s.adviseAscIndex(left.Name, "范围条件 "+op.String())
+	case rightCol && !leftCol:
+		s.adviseAscIndex(right.Name, "范围条件 "+op.String())
+	}
+}
+
+// adviseAscIndex 建议为 DML 中按顺序访问的列建立 ASC 索引，每列只建议一次
+// 参数:
+//   - col: 列名
+//   - usage: 列的用途，如 "ORDER BY"
+func (s *ShardingChecker) adviseAscIndex(col *ast.ColumnName, usage string) {
+	rule, ok := s.GetRules()[patternRangeScan]
+	if !ok || s.scope == nil {
+		return
+	}
+	table := s.columnTable(col)
+	if table == "" {
+		return
+	}
+	key := table + "." + col.Name.L
+	if s.advised[key] {
+		return
+	}
+	s.advised[key] = true
+
+	name := table
+	if schema := s.catalog.tables[table]; schema != nil {
+		name = schema.name
+	}
+	s.AddIssue(model.Issue{
+		Checker: "ShardingChecker",
+		Message: fmt.Sprintf("分片 %s: %s (建议: %s)，表 %s 的列 %s 用于%s，YugabyteDB 的主键与索引默认按第一列 HASH 分片，无法按顺序扫描；建议建立索引: CREATE INDEX %s ON %s (%s ASC)",
+			patternRangeScan, rule.Description, rule.Then.Target, name, col.Name.O, usage,
+			sqlemitter.QuoteIdent(fitIdent(name+"_"+col.Name.O, "_asc_idx")), sqlemitter.QuoteIdent(name), sqlemitter.QuoteIdent(col.Name.O)),
+	})
+}
+
+// columnTable 确定列所属的表
+// 带表名（或别名）的列按名称匹配；不带表名的列在语句只有一个表时属于该表，
+// 否则根据收集到的表结构查找唯一包含该列的表。表结构已知时列必须存在，排除 ORDER BY 中的列别名。
+// 返回:
+//   - string: 表名（小写），无法确定时返回空字符串
+func (s *ShardingChecker) columnTable(col *ast.ColumnName) string {
+	var table string
+	if col.Table.L != "" {
+		table = s.scope[col.Table.L]
+	} else {
+		tables := make(map[string]bool)
+		for _, name := range s.scope {
+			tables[name] = true
+		}
+		for name := range tables {
+			if len(tables) > 1 {
+				if schema := s.catalog.tables[name]; schema == nil || !schema.hasColumn(col.Name.L) {
+					continue
+				}
+				if table != "" {
+					return ""
+				}
+			}
+			table = name
+		}
+	}
+	if schema := s.catalog.tables[table]; schema != nil && !schema.hasColumn(col.Name.L) {
+		return ""
+	}
+	return table
+}
+
+// renameTable 将单调递增列登记到新表名下
+func (s *ShardingChecker) renameTable(from, to *ast.TableName) {
+	if from == nil || to == nil {
+		return
+	}
+	if columns, ok := s.sequential[from.Name.L]; ok {
+		delete(s.sequential, from.Name.L)
+		s.sequential[to.Name.L] = columns
+	}
+}
+
+// sequentialColumn 判断列的取值是否单调递增
+// AUTO_INCREMENT 列、DATETIME/TIMESTAMP/DATE 列，以及名称为 seq、sequence 或以 _seq、_sequence 结尾的整数列视为单调递增。
+// 在进入建表/改表语句时调用，此时列选项尚未被其他检查器在列定义上的转换修改。
+func sequentialColumn(col *ast.ColumnDef) (sequentialKind, bool) {
+	if col.Tp == nil {
+		return "", false
+	}
+	if hasAutoIncrement(col) {
+		return sequentialAutoIncrement, true
+	}
+	switch col.Tp.GetType() {
+	case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDate:
+		return sequentialTemporal, true
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		name := col.Name.Name.L
+		if name == "seq" || name == "sequence" || strings.HasSuffix(name, "_seq") || strings.HasSuffix(name, "_sequence") {
+			return sequentialNamed, true
+		}
+	}
+	return "", false
+}
+
+// keyColumnNames 返回键的列名，表达式键返回空字符串占位
+func keyColumnNames(parts []*ast.IndexPartSpecification) []string {
+	names := make([]string, len(parts))
+	for i, part := range parts {
+		if part.Column != nil {
+			names[i] = part.Column.Name.O
+		}
+	}
+	return names
+}
@@ -2,6 +2,7 @@ package checker
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/pingcap/tidb/pkg/parser/ast"
@@ -14,6 +15,7 @@ import (
 // 检查 SQL 语法兼容性问题，支持从配置文件加载规则
 type SyntaxChecker struct {
 	*RuleChecker
+	table *ast.CreateTableStmt // 当前 CREATE TABLE 语句，其列的 AUTO_INCREMENT 在访问到列定义时转换
 }
 
 // NewSyntaxChecker 创建新的 SyntaxChecker 实例
@@ -33,14 +35,28 @@ func NewSyntaxChecker(cfg *config.Config) (*SyntaxChecker, error) {
 // Name 返回检查器名称
 func (s *SyntaxChecker) Name() string { return "SyntaxChecker" }
 
+// Reset 重置检查器状态
+func (s *SyntaxChecker) Reset() {
+	s.RuleChecker.Reset()
+	s.table = nil
+}
+
 // Inspect 实现 Checker 接口，处理 AST 节点
 // 检查语法兼容性问题，如 AUTO_INCREMENT 等
 func (s *SyntaxChecker) Inspect(n ast.Node) (w ast.Node, skipChildren bool) {
 	switch node := n.(type) {
 	case *ast.CreateTableStmt:
 		// 检查并转换表级别的语法问题
+		s.table = node
 		return s.checkCreateTableSyntax(node)
 
+	case *ast.ColumnDef:
+		// AUTO_INCREMENT 在列定义上转换而不是在建表语句上转换，
+		// 其他检查器进入建表语句时仍能从列选项识别自增列，与检查器的执行顺序无关
+		if s.table != nil && slices.Contains(s.table.Cols, node) {
+			return node, s.checkColumnAutoIncrement(node, s.GetRules())
+		}
+
 	case *ast.TableName:
 		// 检查并转换表名中的反引号
 		return s.checkTableNameQuotes(node)
@@ -56,6 +72,10 @@ func (s *SyntaxChecker) Inspect(n ast.Node) (w ast.Node, skipChildren bool) {
 	case *ast.UnlockTablesStmt:
 		// 检查 UNLOCK TABLES 语法
 		return s.checkUnlockTablesSyntax(node)
+
+	case *ast.Limit:
+		// 检查并转换 LIMIT 子句
+		return s.checkLimitSyntax(node)
 	}
 	return n, false
 }
@@ -68,29 +88,11 @@ func (s *SyntaxChecker) Inspect(n ast.Node) (w ast.Node, skipChildren bool) {
 //   - ast.Node: 转换后的节点
 //   - bool: 是否有转换发生
 func (s *SyntaxChecker) checkCreateTableSyntax(node *ast.CreateTableStmt) (ast.Node, bool) {
-	hasTransform := s.checkColumnsSyntax(node.Cols) || s.checkTableOptionsSyntax(node.Options)
+	hasTransform := s.checkTableOptionsSyntax(node.Options)
 
 	return node, hasTransform
 }
 
-// checkColumnsSyntax 检查列定义中的语法问题
-// 参数:
-//   - columns: 列定义数组
-//
-// 返回值:
-//   - bool: 是否有转换发生
-func (s *SyntaxChecker) checkColumnsSyntax(columns []*ast.ColumnDef) bool {
-	hasTransform := false
-	rules := s.GetRules()
-
-	for _, col := range columns {
-		if s.checkColumnAutoIncrement(col, rules) {
-			hasTransform = true
-		}
-	}
-	return hasTransform
-}
-
 // checkColumnAutoIncrement 检查单个列的 AUTO_INCREMENT 选项
 // 参数:
 //   - col: 列定义
@@ -279,3 +281,30 @@ func (s *SyntaxChecker) checkUnlockTablesSyntax(node *ast.UnlockTablesStmt) (ast
 	s.AddIssue(issue)
 	return node, false
 }
+
+// checkLimitSyntax 检查 LIMIT 子句并按规则转换为 OFFSET ... FETCH
+// 参数:
+//   - node: LIMIT子句节点
+//
+// 返回值:
+//   - ast.Node: 转换后的节点
+//   - bool: 是否跳过子节点
+func (s *SyntaxChecker) checkLimitSyntax(node *ast.Limit) (ast.Node, bool) {
+	rule, hasRule := s.GetRules()["LIMIT"]
+	if !hasRule {
+		return node, false
+	}
+
+	s.AddIssue(model.Issue{
+		Checker: "SyntaxChecker",
+		Message: fmt.Sprintf("语法 %s: %s (建议: %s)", "LIMIT", rule.Description, rule.Then.Target),
+		AutoFix: model.AutoFix{
+			Available: true,
+			Action:    rule.Then.Action,
+			Code:      fmt.Sprintf("%s -> %s", "LIMIT", rule.Then.Target),
+		},
+	})
+
+	// 执行AST转换
+	return s.ApplyTransformation(node, rule), false
+}
@@ -0,0 +1,266 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+
+	"github.com/example/ybMigration/internal/config"
+	"github.com/example/ybMigration/internal/model"
+	sqlemitter "github.com/example/ybMigration/internal/sql-emitter"
+)
+
+// patternOnUpdate ON UPDATE CURRENT_TIMESTAMP 规则的 pattern
+const patternOnUpdate = "ON UPDATE CURRENT_TIMESTAMP"
+
+// updateTrigger 表的 ON UPDATE 触发器
+// 函数名与触发器名在首次生成时按表名确定，表重命名后沿用原名称，与 YSQL 中触发器随表重命名的行为一致
+type updateTrigger struct {
+	function string
+	trigger  string
+	columns  []*ast.Assignment // ON UPDATE 列及其表达式，按定义顺序
+}
+
+// TriggerChecker 触发器检查器
+// MySQL 的 DATETIME/TIMESTAMP 列可以声明 ON UPDATE CURRENT_TIMESTAMP，YSQL 没有列级写法。
+// 检查器从列定义中去掉该选项，改为在表定义之后生成 BEFORE UPDATE 触发器：
+// 行发生变化且语句没有修改该列时，由触发器函数把列更新为当前时间。
+// 一个表的所有 ON UPDATE 列共用一个触发器，ALTER TABLE 增删列时重新生成触发器函数。
+type TriggerChecker struct {
+	*RuleChecker
+	triggers map[string]*updateTrigger // 表名（小写）到触发器，跨语句保留
+	locator  *locator                  // 当前语句的定位器
+}
+
+// NewTriggerChecker 创建触发器检查器实例
+// 返回:
+//   - *TriggerChecker: 初始化后的触发器检查器实例
+//   - error: 错误信息
+func NewTriggerChecker(cfg *config.Config) (*TriggerChecker, error) {
+	ruleChecker, err := newRuleChecker("TriggerChecker", "trigger", cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建触发器检查器失败: %w", err)
+	}
+	return &TriggerChecker{
+		RuleChecker: ruleChecker,
+		triggers:    make(map[string]*updateTrigger),
+	}, nil
+}
+
+// Name 返回检查器名称
+func (t *TriggerChecker) Name() string { return "TriggerChecker" }
+
+// Inspect 实现 Checker 接口
+// CREATE/ALTER TABLE 中的 ON UPDATE 列转换为触发器，DROP/RENAME TABLE 更新记录的触发器
+func (t *TriggerChecker) Inspect(n ast.Node) (w ast.Node, skipChildren bool) {
+	rule, ok := t.GetRules()[patternOnUpdate]
+	if !ok {
+		return n, false
+	}
+	switch node := n.(type) {
+	case *ast.CreateTableStmt:
+		t.checkCreateTable(node, rule)
+	case *ast.AlterTableStmt:
+		t.checkAlterTable(node, rule)
+	case *ast.DropTableStmt:
+		if !node.IsView {
+			for _, table := range node.Tables {
+				delete(t.triggers, table.Name.L)
+			}
+		}
+	case *ast.RenameTableStmt:
+		for _, t2t := range node.TableToTables {
+			t.rename(t2t.OldTable, t2t.NewTable)
+		}
+	}
+	return n, false
+}
+
+// checkCreateTable 为 CREATE TABLE 中的 ON UPDATE 列生成触发器
+// CREATE TABLE ... LIKE 在 MySQL 中复制 ON UPDATE 选项，而 YSQL 的 LIKE 不复制触发器，为新表生成同样的触发器
+func (t *TriggerChecker) checkCreateTable(n *ast.CreateTableStmt, rule config.Rule) {
+	if n.Table == nil {
+		return
+	}
+	delete(t.triggers, n.Table.Name.L)
+	// ON UPDATE 选项在遍历到列定义之前就被去掉，按原文顺序依次定位表名与列定义
+	t.locator = newLocator(n)
+	t.locator.locate(n.Table)
+
+	var columns []*ast.Assignment
+	if n.ReferTable != nil {
+		if refer := t.triggers[n.ReferTable.Name.L]; refer != nil {
+			columns = append(columns, refer.columns...)
+		}
+	}
+	for _, col := range n.Cols {
+		if assign := t.takeOnUpdate(col, rule); assign != nil {
+			columns = append(columns, assign)
+		}
+	}
+	if len(columns) == 0 {
+		return
+	}
+
+	trigger := newUpdateTrigger(n.Table)
+	trigger.columns = columns
+	t.triggers[n.Table.Name.L] = trigger
+	t.hints.SetUpdateTrigger(n, trigger.hint())
+}
+
+// checkAlterTable 根据 ALTER TABLE 对 ON UPDATE 列的修改重新生成触发器
+// 添加、修改、删除或重命名 ON UPDATE 列都会改变触发器函数的内容，函数以 CREATE OR REPLACE 重新输出
+func (t *TriggerChecker) checkAlterTable(n *ast.AlterTableStmt, rule config.Rule) {
+	if n.Table == nil {
+		return
+	}
+	t.locator = newLocator(n)
+	t.locator.locate(n.Table)
+
+	trigger := t.triggers[n.Table.Name.L]
+	changed := false
+	for _, spec := range n.Specs {
+		switch spec.Tp {
+		case ast.AlterTableAddColumns:
+			for _, col := range spec.NewColumns {
+				if assign := t.takeOnUpdate(col, rule); assign != nil {
+					if trigger == nil {
+						trigger = newUpdateTrigger(n.Table)
+						t.triggers[n.Table.Name.L] = trigger
+					}
+					trigger.columns = append(trigger.columns, assign)
+					changed = true
+				}
+			}
+		case ast.AlterTableModifyColumn, ast.AlterTableChangeColumn:
+			if len(spec.NewColumns) == 0 {
+				continue
+			}
+			col := spec.NewColumns[0]
+			name := col.Name.Name
+			if spec.Tp == ast.AlterTableChangeColumn && spec.OldColumnName != nil {
+				name = spec.OldColumnName.Name
+			}
+			// MODIFY/CHANGE 重新定义整个列，没有 ON UPDATE 选项的列不再自动更新
+			if trigger != nil && trigger.remove(name.L) {
+				changed = true
+			}
+			if assign := t.takeOnUpdate(col, rule); assign != nil {
+				if trigger == nil {
+					trigger = newUpdateTrigger(n.Table)
+					t.triggers[n.Table.Name.L] = trigger
+				}
+				trigger.columns = append(trigger.columns, assign)
+				changed = true
+			}
+		case ast.AlterTableDropColumn:
+			if trigger != nil && spec.OldColumnName != nil && trigger.remove(spec.OldColumnName.Name.L) {
+				changed = true
+			}
+		case ast.AlterTableRenameColumn:
+			if trigger == nil || spec.OldColumnName == nil || spec.NewColumnName == nil {
+				continue
+			}
+			for i, assign := range trigger.columns {
+				if assign.Column.Name.L == spec.OldColumnName.Name.L {
+					trigger.columns[i] = &ast.Assignment{Column: &ast.ColumnName{Name: spec.NewColumnName.Name}, Expr: assign.Expr}
+					changed = true
+				}
+			}
+		case ast.AlterTableRenameTable:
+			t.rename(n.Table, spec.NewTable)
+		}
+	}
+	if !changed {
+		return
+	}
+
+	t.hints.SetUpdateTrigger(n, trigger.hint())
+	if len(trigger.columns) == 0 {
+		for name, tr := range t.triggers {
+			if tr == trigger {
+				delete(t.triggers, name)
+			}
+		}
+	}
+}
+
+// takeOnUpdate 从列定义中去掉 ON UPDATE 选项并记录问题，问题定位到 ON UPDATE 子句
+// 返回:
+//   - *ast.Assignment: 触发器中更新该列的赋值，列没有 ON UPDATE 选项时返回 nil
+func (t *TriggerChecker) takeOnUpdate(col *ast.ColumnDef, rule config.Rule) *ast.Assignment {
+	start, end := t.locator.locate(col)
+	var assign *ast.Assignment
+	options := col.Options[:0]
+	for _, opt := range col.Options {
+		if opt.Tp == ast.ColumnOptionOnUpdate {
+			assign = &ast.Assignment{Column: &ast.ColumnName{Name: col.Name.Name}, Expr: opt.Expr}
+			if s, e := t.locator.locate(opt); e > s {
+				start, end = s, e
+			}
+			continue
+		}
+		options = append(options, opt)
+	}
+	col.Options = options
+	if assign == nil {
+		return nil
+	}
+
+	t.AddIssue(t.locator.annotate(model.Issue{
+		Checker: "TriggerChecker",
+		Message: fmt.Sprintf("%s: %s (建议: %s)，列 %s 的 ON UPDATE 选项已去掉，改为由 BEFORE UPDATE 触发器更新；语句显式把该列更新为原值时触发器同样会更新该列",
+			patternOnUpdate, rule.Description, rule.Then.Target, col.Name.Name.O),
+		AutoFix: model.AutoFix{
+			Available: true,
+			Action:    rule.Then.Action,
+			Code:      fmt.Sprintf("%s -> %s", patternOnUpdate, rule.Then.Target),
+		},
+	}, 0, start, end))
+	return assign
+}
+
+// rename 将触发器登记到新表名下
+func (t *TriggerChecker) rename(from, to *ast.TableName) {
+	if from == nil || to == nil {
+		return
+	}
+	if trigger, ok := t.triggers[from.Name.L]; ok {
+		delete(t.triggers, from.Name.L)
+		t.triggers[to.Name.L] = trigger
+	}
+}
+
+// newUpdateTrigger 按表名创建触发器，函数名为 <表名>_on_update_fn，触发器名为 <表名>_on_update_trg
+func newUpdateTrigger(table *ast.TableName) *updateTrigger {
+	name := strings.ToLower(table.Name.O)
+	return &updateTrigger{
+		function: name + "_on_update_fn",
+		trigger:  name + "_on_update_trg",
+	}
+}
+
+// remove 删除指定列，列名为小写
+// 返回:
+//   - bool: 是否删除了列
+func (u *updateTrigger) remove(name string) bool {
+	columns := u.columns[:0]
+	for _, assign := range u.columns {
+		if assign.Column.Name.L != name {
+			columns = append(columns, assign)
+		}
+	}
+	removed := len(columns) < len(u.columns)
+	u.columns = columns
+	return removed
+}
+
+// hint 返回供 SQL 生成器输出的触发器，列为当前列的副本
+func (u *updateTrigger) hint() sqlemitter.UpdateTrigger {
+	return sqlemitter.UpdateTrigger{
+		Function: u.function,
+		Trigger:  u.trigger,
+		Columns:  append([]*ast.Assignment(nil), u.columns...),
+	}
+}
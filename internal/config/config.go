@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -27,16 +28,83 @@ type RuleAction struct {
 type Rule struct {
 	Name        string        `yaml:"name"`        // 规则的唯一标识符
 	Description string        `yaml:"description"` // 描述规则的功能和用途
-	Category    string        `yaml:"category"`    // 指定规则所属的类别（function、datatype、syntax、charset）
+	Category    string        `yaml:"category"`    // 指定规则所属的类别（function、datatype、syntax、charset、dml、multitable、trigger、index、sharding）
 	When        RuleCondition `yaml:"when"`        // 定义规则匹配的条件
 	Then        RuleAction    `yaml:"then"`        // 定义规则匹配后执行的动作
 }
 
+// mysqldump 预处理模式
+const (
+	DumpModeAuto = "auto" // 根据文件头（-- MySQL dump）识别，默认值
+	DumpModeOn   = "on"   // 所有 .sql 文件都按 mysqldump 输出处理
+	DumpModeOff  = "off"  // 不做 mysqldump 预处理
+)
+
+// DefaultServerVersion 未配置 server_version 时假定的源 MySQL 服务器版本
+const DefaultServerVersion = "8.0.0"
+
+// InputConfig 输入解析相关的配置
+type InputConfig struct {
+	// ServerVersion 源 MySQL 服务器版本（如 "8.0.35"），决定 /*!NNNNN ... */ 版本注释是否生效
+	ServerVersion string `yaml:"server_version"`
+	// Mysqldump mysqldump 预处理模式：auto、on、off，为空时按 auto 处理
+	Mysqldump string `yaml:"mysqldump"`
+	// Encoding 输入文件编码：auto、utf8、gbk、gb18030、big5、latin1，为空时按 auto 处理
+	// auto 模式依次根据 BOM、开头的 SET NAMES 语句和文件内容识别；BOM 始终优先于配置
+	Encoding string `yaml:"encoding"`
+}
+
+// DataTypeConfig 数据类型转换相关的配置
+type DataTypeConfig struct {
+	// UnsignedCheck UNSIGNED 整数列扩宽为更大的类型后，是否添加 CHECK (col >= 0) 约束保留非负语义
+	UnsignedCheck bool `yaml:"unsigned_check"`
+	// TypeMapping 覆盖默认类型转换表的条目，key 为 MySQL 类型名（如 DATETIME、INT UNSIGNED、BIT(1)），
+	// value 为 YSQL 类型模板，可以使用 ${length}、${precision}、${scale}、${fsp} 占位符
+	TypeMapping map[string]string `yaml:"type_mapping"`
+}
+
+// MysqlVersion 返回版本注释使用的数字格式版本号
+// 例如 "8.0.35" 返回 80035，"5.7.44-log" 返回 50744；未配置时使用 DefaultServerVersion。
+// 返回:
+//   - int: 版本号
+//   - error: 版本格式无效时返回错误
+func (c InputConfig) MysqlVersion() (int, error) {
+	version := c.ServerVersion
+	if version == "" {
+		version = DefaultServerVersion
+	}
+
+	// 去除 "-log"、"-MariaDB" 等后缀
+	numeric := strings.TrimSpace(version)
+	if i := strings.IndexFunc(numeric, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); i >= 0 {
+		numeric = numeric[:i]
+	}
+
+	parts := strings.Split(numeric, ".")
+	if numeric == "" || len(parts) > 3 {
+		return 0, fmt.Errorf("无效的服务器版本: %q", version)
+	}
+	result := 0
+	for i, weight := range []int{10000, 100, 1} {
+		if i >= len(parts) {
+			break
+		}
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || i > 0 && n > 99 {
+			return 0, fmt.Errorf("无效的服务器版本: %q", version)
+		}
+		result += n * weight
+	}
+	return result, nil
+}
+
 // Config 表示加载后的配置文件内容，包含所有规则及元信息。
 type Config struct {
 	Rules []Rule `yaml:"rules"` // 存储加载的转换规则
 	// 新增字段
-	LastUpdated string `yaml:"last_updated"` // 最后更新时间
+	LastUpdated string         `yaml:"last_updated"` // 最后更新时间
+	Input       InputConfig    `yaml:"input"`        // 输入解析配置
+	DataType    DataTypeConfig `yaml:"datatype"`     // 数据类型转换配置
 }
 
 // GetRules 返回缓存的规则
@@ -140,6 +140,34 @@ func TestConfig_GetRulesByCategory(t *testing.T) {
 // 工具函数测试
 // ============================================================================
 
+func TestInputConfig_MysqlVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected int
+		wantErr  bool
+	}{
+		{version: "", expected: 80000},
+		{version: "8.0.35", expected: 80035},
+		{version: "5.7.44-log", expected: 50744},
+		{version: "5.6", expected: 50600},
+		{version: "10.6.12-MariaDB", expected: 100612},
+		{version: "abc", wantErr: true},
+		{version: "8.100.1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := InputConfig{ServerVersion: tt.version}.MysqlVersion()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
 func TestResolveFilePath(t *testing.T) {
 	t.Run("empty_path", func(t *testing.T) {
 		_, err := ResolveFilePath("", "测试文件")
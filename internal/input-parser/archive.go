@@ -0,0 +1,334 @@
+package inputparser
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// VirtualPathSeparator 归档文件路径与成员路径之间的分隔符
+// 归档中的成员以虚拟路径表示，如 archive.tar.gz!/db/users.sql。
+const VirtualPathSeparator = "!/"
+
+// 压缩文件扩展名
+const (
+	extGzip = ".gz"
+	extZstd = ".zst"
+)
+
+// archiveSuffixes 归档文件的扩展名，按长度从长到短匹配
+var archiveSuffixes = []string{".tar.gz", ".tar.zst", ".tgz", ".tzst", ".tar", ".zip"}
+
+// rotationSuffixRe 匹配日志轮转产生的数字后缀，如 general.log.1
+var rotationSuffixRe = regexp.MustCompile(`\.\d+$`)
+
+// SplitVirtualPath 拆分归档成员的虚拟路径
+// 参数:
+//   - path: 文件路径或虚拟路径
+//
+// 返回值:
+//   - archive: 归档文件路径；不是虚拟路径时为 path 本身
+//   - member: 归档内的成员路径；不是虚拟路径时为空
+func SplitVirtualPath(path string) (archive, member string) {
+	if i := strings.Index(path, VirtualPathSeparator); i >= 0 {
+		return path[:i], path[i+len(VirtualPathSeparator):]
+	}
+	return path, ""
+}
+
+// TrimCompressionExt 去除文件名末尾的 .gz、.zst 压缩扩展名
+func TrimCompressionExt(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range []string{extGzip, extZstd} {
+		if strings.HasSuffix(lower, ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
+
+// InputExt 返回输入文件内容对应的扩展名（小写）
+// 虚拟路径取成员名；先去除压缩扩展名，再去除日志轮转的数字后缀，
+// 如 dump.sql.gz 返回 .sql，general.log.1.gz 返回 .log。
+func InputExt(path string) string {
+	_, member := SplitVirtualPath(path)
+	if member == "" {
+		member = path
+	}
+	name := rotationSuffixRe.ReplaceAllString(TrimCompressionExt(filepath.Base(member)), "")
+	return strings.ToLower(filepath.Ext(name))
+}
+
+// IsArchive 判断文件是否为受支持的归档文件（.tar、.tar.gz、.tgz、.tar.zst、.zip）
+func IsArchive(path string) bool {
+	lower := strings.ToLower(path)
+	for _, suffix := range archiveSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenFile 检查并打开输入文件，调用方负责关闭
+// 压缩文件（.gz、.zst）边读边解压；虚拟路径打开归档中的对应成员。
+// 不会产生临时文件。
+// 参数:
+//   - path: 文件路径或归档成员的虚拟路径
+//
+// 返回值:
+//   - io.ReadCloser: 解压后的内容
+//   - error: 文件不存在、是目录、成员不存在或压缩格式错误时返回错误
+func OpenFile(path string) (io.ReadCloser, error) {
+	if err := checkFile(path); err != nil {
+		return nil, err
+	}
+
+	if archive, member := SplitVirtualPath(path); member != "" {
+		return openMember(archive, member)
+	}
+
+	file, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("打开文件 %s 失败: %w", path, err)
+	}
+	return decompress(path, file)
+}
+
+// checkFile 检查输入路径：不能为空，文件（或虚拟路径所在的归档）必须存在且不是目录
+func checkFile(path string) error {
+	if path == "" {
+		return fmt.Errorf("文件路径不能为空")
+	}
+
+	archive, _ := SplitVirtualPath(path)
+	fileInfo, err := os.Stat(archive)
+	if err != nil {
+		return fmt.Errorf("文件 %s 不存在: %w", archive, err)
+	}
+	if fileInfo.IsDir() {
+		return fmt.Errorf("不支持目录，请使用 analyzer 的 AnalyzeDirectory 方法")
+	}
+	return nil
+}
+
+// WalkArchive 按归档中的顺序逐个读取成员文件
+// tar 归档一次顺序读取完成，不会为每个成员重新解压整个归档。
+// 参数:
+//   - archivePath: 归档文件路径
+//   - fn: 成员回调，member 为成员的虚拟路径，r 为解压后的成员内容，仅在回调期间有效；
+//     返回错误时停止遍历
+//
+// 返回值:
+//   - error: 归档格式错误、读取错误或 fn 返回的错误
+func WalkArchive(archivePath string, fn func(member string, r io.Reader) error) error {
+	walk := func(name string, r io.Reader) error {
+		member := archivePath + VirtualPathSeparator + name
+		rc, err := decompress(name, io.NopCloser(r))
+		if err != nil {
+			return fmt.Errorf("解压 %s 失败: %w", member, err)
+		}
+		defer func() {
+			_ = rc.Close()
+		}()
+		return fn(member, rc)
+	}
+
+	if strings.HasSuffix(strings.ToLower(archivePath), ".zip") {
+		return walkZip(archivePath, walk)
+	}
+	return walkTar(archivePath, walk)
+}
+
+// walkTar 顺序读取 tar 归档中的普通文件
+func walkTar(archivePath string, fn func(name string, r io.Reader) error) error {
+	file, err := os.Open(archivePath) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("打开文件 %s 失败: %w", archivePath, err)
+	}
+	stream, err := decompress(tarCompression(archivePath), file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := stream.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "关闭文件 %s 失败: %v\n", archivePath, err)
+		}
+	}()
+
+	reader := tar.NewReader(stream)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取归档 %s 失败: %w", archivePath, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(memberName(header.Name), reader); err != nil {
+			return err
+		}
+	}
+}
+
+// walkZip 按目录顺序读取 zip 归档中的普通文件
+func walkZip(archivePath string, fn func(name string, r io.Reader) error) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("读取归档 %s 失败: %w", archivePath, err)
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "关闭文件 %s 失败: %v\n", archivePath, err)
+		}
+	}()
+
+	for _, f := range reader.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("读取归档 %s 失败: %w", archivePath, err)
+		}
+		err = fn(memberName(f.Name), rc)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openMember 打开归档中的单个成员
+// tar 归档需要从头顺序查找成员，批量分析时请使用 WalkArchive。
+func openMember(archivePath, member string) (io.ReadCloser, error) {
+	member = memberName(member)
+	if IsArchive(member) {
+		return nil, fmt.Errorf("不支持嵌套归档: %s", member)
+	}
+
+	if strings.HasSuffix(strings.ToLower(archivePath), ".zip") {
+		reader, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("读取归档 %s 失败: %w", archivePath, err)
+		}
+		for _, f := range reader.File {
+			if memberName(f.Name) != member || !f.Mode().IsRegular() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				_ = reader.Close()
+				return nil, fmt.Errorf("读取归档 %s 失败: %w", archivePath, err)
+			}
+			return decompress(member, &multiCloser{Reader: rc, closers: []io.Closer{rc, reader}})
+		}
+		_ = reader.Close()
+		return nil, fmt.Errorf("归档 %s 中不存在文件 %s", archivePath, member)
+	}
+
+	file, err := os.Open(archivePath) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("打开文件 %s 失败: %w", archivePath, err)
+	}
+	stream, err := decompress(tarCompression(archivePath), file)
+	if err != nil {
+		return nil, err
+	}
+	reader := tar.NewReader(stream)
+	for {
+		header, err := reader.Next()
+		if err != nil {
+			_ = stream.Close()
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("归档 %s 中不存在文件 %s", archivePath, member)
+			}
+			return nil, fmt.Errorf("读取归档 %s 失败: %w", archivePath, err)
+		}
+		if header.Typeflag == tar.TypeReg && memberName(header.Name) == member {
+			return decompress(member, &multiCloser{Reader: reader, closers: []io.Closer{stream}})
+		}
+	}
+}
+
+// memberName 规范化归档成员路径，去除开头的 ./ 和 /
+func memberName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// tarCompression 返回 tar 归档外层压缩对应的扩展名
+func tarCompression(archivePath string) string {
+	lower := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(lower, ".tgz"):
+		return extGzip
+	case strings.HasSuffix(lower, ".tzst"):
+		return extZstd
+	}
+	return lower
+}
+
+// decompress 根据文件名的压缩扩展名包装解压流，未压缩时原样返回
+// 返回的 ReadCloser 关闭时同时关闭 rc。
+func decompress(name string, rc io.ReadCloser) (io.ReadCloser, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case extGzip:
+		gz, err := gzip.NewReader(rc)
+		if err != nil {
+			_ = rc.Close()
+			return nil, fmt.Errorf("读取 gzip 文件 %s 失败: %w", name, err)
+		}
+		return &multiCloser{Reader: gz, closers: []io.Closer{gz, rc}}, nil
+
+	case extZstd:
+		zr, err := zstd.NewReader(rc)
+		if err != nil {
+			_ = rc.Close()
+			return nil, fmt.Errorf("读取 zstd 文件 %s 失败: %w", name, err)
+		}
+		return &multiCloser{Reader: zr, closers: []io.Closer{zstdCloser{zr}, rc}}, nil
+	}
+	return rc, nil
+}
+
+// multiCloser 关闭时依次关闭解压流和底层文件
+type multiCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close 依次关闭所有 closer，返回第一个错误
+func (m *multiCloser) Close() error {
+	var first error
+	for _, c := range m.closers {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// zstdCloser 适配 zstd.Decoder 的 Close（无返回值）
+type zstdCloser struct {
+	decoder *zstd.Decoder
+}
+
+// Close 释放解码器资源
+func (z zstdCloser) Close() error {
+	z.decoder.Close()
+	return nil
+}
@@ -0,0 +1,162 @@
+package inputparser
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gzipBytes 压缩测试内容
+func gzipBytes(t *testing.T, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := io.WriteString(w, content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// writeTarGz 生成 .tar.gz 归档，成员按 names 的顺序写入
+func writeTarGz(t *testing.T, path string, names []string, members map[string][]byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "./db/", Typeflag: tar.TypeDir, Mode: 0750}))
+	for _, name := range names {
+		content := members[name]
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "./" + name, Mode: 0600, Size: int64(len(content))}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+}
+
+// writeZip 生成 .zip 归档
+func writeZip(t *testing.T, path string, names []string, members map[string][]byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(members[name])
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+}
+
+// TestInputExt 测试压缩文件、轮转日志与归档成员的扩展名识别
+func TestInputExt(t *testing.T) {
+	tests := map[string]string{
+		"dump.sql":                     ".sql",
+		"dump.SQL.GZ":                  ".sql",
+		"dump.sql.zst":                 ".sql",
+		"general.log.1":                ".log",
+		"general.log.1.gz":             ".log",
+		"archive.tar.gz!/db/users.sql": ".sql",
+		"archive.zip!/logs/slow.log.2": ".log",
+		"notes.txt.gz":                 ".txt",
+	}
+	for path, expected := range tests {
+		assert.Equal(t, expected, InputExt(path), path)
+	}
+
+	assert.True(t, IsArchive("backup.TAR.GZ"))
+	assert.True(t, IsArchive("backup.tgz"))
+	assert.True(t, IsArchive("backup.zip"))
+	assert.False(t, IsArchive("dump.sql.gz"))
+}
+
+// TestOpenFile 测试压缩文件与归档成员的透明解压
+func TestOpenFile(t *testing.T) {
+	dir := t.TempDir()
+	const content = "SELECT 1;\nSELECT 2;\n"
+
+	gzPath := filepath.Join(dir, "dump.sql.gz")
+	require.NoError(t, os.WriteFile(gzPath, gzipBytes(t, content), 0600))
+
+	var zst bytes.Buffer
+	zw, err := zstd.NewWriter(&zst)
+	require.NoError(t, err)
+	_, err = io.WriteString(zw, content)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	zstPath := filepath.Join(dir, "dump.sql.zst")
+	require.NoError(t, os.WriteFile(zstPath, zst.Bytes(), 0600))
+
+	members := map[string][]byte{
+		"db/users.sql":       []byte(content),
+		"db/orders.sql.gz":   gzipBytes(t, content),
+		"logs/general.log.1": []byte("log"),
+	}
+	names := []string{"db/users.sql", "db/orders.sql.gz", "logs/general.log.1"}
+	tarPath := filepath.Join(dir, "backup.tar.gz")
+	writeTarGz(t, tarPath, names, members)
+	zipPath := filepath.Join(dir, "backup.zip")
+	writeZip(t, zipPath, names, members)
+
+	for _, path := range []string{
+		gzPath,
+		zstPath,
+		tarPath + "!/db/users.sql",
+		tarPath + "!/db/orders.sql.gz",
+		zipPath + "!/db/users.sql",
+		zipPath + "!/db/orders.sql.gz",
+	} {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			rc, err := OpenFile(path)
+			require.NoError(t, err)
+			data, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			require.NoError(t, rc.Close())
+			assert.Equal(t, content, string(data))
+		})
+	}
+
+	t.Run("missing_member", func(t *testing.T) {
+		_, err := OpenFile(tarPath + "!/db/missing.sql")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "不存在文件 db/missing.sql")
+	})
+
+	t.Run("sql_file_parser", func(t *testing.T) {
+		sql, err := NewSQLFileParser().Parse(gzPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, sql)
+	})
+
+	t.Run("walk_archive", func(t *testing.T) {
+		for _, archive := range []string{tarPath, zipPath} {
+			var walked []string
+			err := WalkArchive(archive, func(member string, r io.Reader) error {
+				data, err := io.ReadAll(r)
+				require.NoError(t, err)
+				walked = append(walked, member)
+				if InputExt(member) == ".sql" {
+					assert.Equal(t, content, string(data), member)
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []string{
+				archive + "!/db/users.sql",
+				archive + "!/db/orders.sql.gz",
+				archive + "!/logs/general.log.1",
+			}, walked)
+		}
+	})
+}
@@ -0,0 +1,424 @@
+package inputparser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// binlog 片段元数据的键名
+const (
+	BinlogMetaPosition    = "position"    // 事件在 binlog 中的起始位置（# at）
+	BinlogMetaEndPosition = "end_log_pos" // 事件的结束位置
+	BinlogMetaTime        = "time"        // 事件头部中的时间
+	BinlogMetaServerID    = "server_id"   // 产生事件的服务器 ID
+	BinlogMetaEvent       = "event"       // 事件类型，如 Query、Write_rows
+	BinlogMetaGTID        = "gtid"        // 事件所属事务的 GTID
+	BinlogMetaThreadID    = "thread_id"   // Query 事件的连接 ID
+	BinlogMetaSchema      = "schema"      // use 语句指定的数据库
+	BinlogMetaTimestamp   = "timestamp"   // SET TIMESTAMP= 中的 Unix 时间戳
+	BinlogMetaTable       = "table"       // 行事件对应的表
+)
+
+// binlogDelimiter mysqlbinlog 输出中语句的分隔符（DELIMITER /*!*/;）
+const binlogDelimiter = "/*!*/;"
+
+// binlogSniffLines 判断 binlog 格式时读取的最大行数
+const binlogSniffLines = 64
+
+var (
+	// binlogAtRe 匹配事件位置行，格式示例: # at 276
+	binlogAtRe = regexp.MustCompile(`^# at (\d+)\s*$`)
+	// binlogHeaderRe 匹配事件头部行
+	// 格式示例: #240115 10:31:00 server id 1  end_log_pos 399 CRC32 0x5e4a7b1c 	Query	thread_id=8	exec_time=0	error_code=0
+	binlogHeaderRe = regexp.MustCompile(`^#(\d{6}\s+\d{1,2}:\d{2}:\d{2})\s+server id\s+(\d+)\s+end_log_pos\s+(\d+)(?:\s+CRC32\s+0x[0-9a-fA-F]+)?\s+([A-Za-z_-]+):?(.*)$`)
+	// binlogThreadRe 匹配 Query 事件头部中的 thread_id
+	binlogThreadRe = regexp.MustCompile(`thread_id=(\d+)`)
+	// binlogGTIDRe 匹配 SET @@SESSION.GTID_NEXT= '...'
+	binlogGTIDRe = regexp.MustCompile(`(?i)GTID_NEXT\s*=\s*'([^']*)'`)
+	// binlogRowRe 匹配行事件伪 SQL 的起始行
+	// 格式示例: ### INSERT INTO `shop`.`users`、### UPDATE `shop`.`users`、### DELETE FROM `shop`.`users`
+	binlogRowRe = regexp.MustCompile(`^### (INSERT INTO|UPDATE|DELETE FROM) (\S+)\s*$`)
+	// binlogValueRe 匹配行事件中的列值，格式示例: ###   @2='alice' /* VARSTRING(80) meta=80 nullable=1 is_null=0 */
+	binlogValueRe = regexp.MustCompile(`^###\s+@(\d+)=(.*)$`)
+	// binlogColumnsRe 匹配 --print-table-metadata 输出的列名
+	binlogColumnsRe = regexp.MustCompile("`([^`]+)`")
+	// binlogTimestampRe 匹配 SET TIMESTAMP=...
+	binlogTimestampRe = regexp.MustCompile(`(?i)^SET\s+TIMESTAMP\s*=\s*(\d+)`)
+)
+
+// BinlogFileParser 解析 mysqlbinlog -v 输出的文本格式
+// 从 binlog 中提取写入流量：
+//   - 基于语句的事件（Query）中的 SQL，跳过 BEGIN/COMMIT、SET 等会话语句
+//   - 基于行的事件（Write_rows/Update_rows/Delete_rows）以 ### 开头的伪 SQL，
+//     转换为 INSERT/UPDATE/DELETE 语句，每行数据对应一个片段
+//
+// 每个片段的元数据记录事件位置（# at）、结束位置、时间、GTID 等来源信息。
+// 行事件中的列名在 mysqlbinlog 使用 --print-table-metadata 时取自表结构，否则以 col_N 表示第 N 列。
+type BinlogFileParser struct{}
+
+// NewBinlogFileParser 创建并返回一个新的 binlog 文本解析器
+func NewBinlogFileParser() *BinlogFileParser {
+	return &BinlogFileParser{}
+}
+
+// Parse 解析 mysqlbinlog 输出文件
+// 参数 path 是 mysqlbinlog 输出文件的路径
+// 返回值: 提取的SQL语句字符串和可能的错误
+func (p *BinlogFileParser) Parse(path string) (string, error) {
+	segments, err := p.ParseSegments(path)
+	if err != nil {
+		return "", err
+	}
+	sql, _ := JoinSegments(segments)
+	return sql, nil
+}
+
+// ParseSegments 解析 mysqlbinlog 输出文件，每条语句或每行数据对应一个片段
+// 参数 path 是 mysqlbinlog 输出文件的路径
+// 返回值: SQL 片段列表（含事件位置、GTID 等元数据）和可能的错误
+func (p *BinlogFileParser) ParseSegments(path string) ([]Segment, error) {
+	return collectSegments(func(fn func(Segment) error) error {
+		return p.StreamSegments(path, fn)
+	})
+}
+
+// StreamSegments 逐个解析 mysqlbinlog 输出文件中的事件，每得到一个片段调用一次 fn
+// 参数:
+//   - path: mysqlbinlog 输出文件的路径
+//   - fn: 片段回调，返回错误时停止解析
+//
+// 返回:
+//   - error: 文件错误、读取错误或 fn 返回的错误
+func (p *BinlogFileParser) StreamSegments(path string, fn func(Segment) error) error {
+	if err := checkFile(path); err != nil {
+		return err
+	}
+
+	if ext := InputExt(path); ext != ".sql" && ext != ".log" {
+		return fmt.Errorf("不支持的文件类型: %s，mysqlbinlog 输出文件应为 .sql 或 .log 扩展名", ext)
+	}
+
+	file, err := openText(path, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "关闭文件 %s 失败: %v\n", path, err)
+		}
+	}()
+
+	return p.ReadSegments(file, fn)
+}
+
+// ReadSegments 从已打开的输入流中解析 mysqlbinlog 输出，每得到一个片段调用一次 fn
+// 用于归档成员等没有独立文件路径的输入，调用方负责打开与关闭 reader。
+func (p *BinlogFileParser) ReadSegments(reader io.Reader, fn func(Segment) error) error {
+	state := &binlogState{emit: fn, columns: make(map[string][]string)}
+
+	scanner := newLineScanner(reader)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if err := state.line(scanner.Text(), lineNo); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取 binlog 内容时出错: %w", err)
+	}
+	return state.flushRow()
+}
+
+// binlogState 解析 mysqlbinlog 输出的过程状态
+type binlogState struct {
+	emit func(Segment) error
+
+	event   map[string]string   // 当前事件的元数据
+	gtid    string              // 当前事务的 GTID
+	schema  string              // 当前数据库
+	stamp   string              // 最近一次 SET TIMESTAMP 的值
+	columns map[string][]string // Table_map 事件中的列名，键为表名
+
+	body     strings.Builder // 当前语句的文本
+	bodyLine int             // 当前语句第一行的行号
+
+	row       *binlogRow // 当前行事件的伪 SQL
+	mapTable  string     // 最近的 Table_map 事件对应的表
+	inColumns bool       // 是否正在读取 # Columns(...) 列表
+}
+
+// line 处理一行输出
+func (s *binlogState) line(text string, lineNo int) error {
+	trimmed := strings.TrimSpace(text)
+
+	if strings.HasPrefix(trimmed, "###") {
+		return s.rowLine(trimmed, lineNo)
+	}
+	if err := s.flushRow(); err != nil {
+		return err
+	}
+
+	switch {
+	case binlogAtRe.MatchString(trimmed):
+		s.body.Reset()
+		s.event = map[string]string{BinlogMetaPosition: binlogAtRe.FindStringSubmatch(trimmed)[1]}
+		s.mapTable, s.inColumns = "", false
+		return nil
+
+	case strings.HasPrefix(trimmed, "#"):
+		s.header(trimmed)
+		return nil
+
+	case s.event == nil || strings.HasPrefix(trimmed, "DELIMITER"):
+		// 第一个事件之前的会话设置和 DELIMITER 命令
+		return nil
+	}
+
+	if s.body.Len() == 0 {
+		if trimmed == "" {
+			return nil
+		}
+		s.bodyLine = lineNo
+	}
+	s.body.WriteString(text)
+	s.body.WriteString("\n")
+
+	if !strings.HasSuffix(trimmed, binlogDelimiter) {
+		return nil
+	}
+	stmt := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s.body.String()), binlogDelimiter))
+	s.body.Reset()
+	return s.statement(stmt)
+}
+
+// header 处理以 # 开头的事件头部和注释
+func (s *binlogState) header(line string) {
+	if s.inColumns || strings.HasPrefix(line, "# Columns(") {
+		// --print-table-metadata 输出的列，可能跨越多行
+		s.inColumns = !strings.HasSuffix(line, ")")
+		if s.mapTable != "" {
+			for _, m := range binlogColumnsRe.FindAllStringSubmatch(line, -1) {
+				s.columns[s.mapTable] = append(s.columns[s.mapTable], m[1])
+			}
+		}
+		return
+	}
+
+	m := binlogHeaderRe.FindStringSubmatch(line)
+	if m == nil || s.event == nil {
+		return
+	}
+	s.event[BinlogMetaTime] = strings.Join(strings.Fields(m[1]), " ")
+	s.event[BinlogMetaServerID] = m[2]
+	s.event[BinlogMetaEndPosition] = m[3]
+	s.event[BinlogMetaEvent] = m[4]
+
+	switch m[4] {
+	case "Query":
+		if t := binlogThreadRe.FindStringSubmatch(m[5]); t != nil {
+			s.event[BinlogMetaThreadID] = t[1]
+		}
+	case "Table_map":
+		if t := binlogColumnsRe.FindAllStringSubmatch(m[5], 2); len(t) == 2 {
+			s.mapTable = "`" + t[0][1] + "`.`" + t[1][1] + "`"
+			delete(s.columns, s.mapTable)
+		}
+	}
+}
+
+// statement 处理以分隔符结尾的一条语句
+func (s *binlogState) statement(stmt string) error {
+	upper := strings.ToUpper(stmt)
+	switch {
+	case stmt == "", strings.HasPrefix(stmt, "/*!"), strings.HasPrefix(upper, "BINLOG "):
+		// 版本注释中的会话设置、base64 编码的行事件
+		return nil
+
+	case binlogGTIDRe.MatchString(stmt):
+		s.gtid = binlogGTIDRe.FindStringSubmatch(stmt)[1]
+		if strings.EqualFold(s.gtid, "AUTOMATIC") {
+			s.gtid = ""
+		}
+		return nil
+
+	case useStmtRe.MatchString(stmt):
+		s.schema = useStmtRe.FindStringSubmatch(stmt)[1]
+		return nil
+
+	case binlogTimestampRe.MatchString(stmt):
+		s.stamp = binlogTimestampRe.FindStringSubmatch(stmt)[1]
+		return nil
+
+	case isIgnoredSQL(stmt):
+		return nil
+	}
+
+	return s.emit(Segment{SQL: stmt, Line: s.bodyLine, Meta: s.meta()})
+}
+
+// meta 返回当前事件的元数据副本
+func (s *binlogState) meta() map[string]string {
+	meta := make(map[string]string, len(s.event)+3)
+	for k, v := range s.event {
+		meta[k] = v
+	}
+	if s.gtid != "" {
+		meta[BinlogMetaGTID] = s.gtid
+	}
+	if s.schema != "" {
+		meta[BinlogMetaSchema] = s.schema
+	}
+	if s.stamp != "" {
+		meta[BinlogMetaTimestamp] = s.stamp
+	}
+	return meta
+}
+
+// binlogRow 行事件中一行数据的伪 SQL
+type binlogRow struct {
+	kind    string // INSERT INTO、UPDATE 或 DELETE FROM
+	table   string
+	line    int
+	section string        // 当前读取的部分：SET 或 WHERE
+	set     []binlogValue // SET 部分的列值（插入或更新后的值）
+	where   []binlogValue // WHERE 部分的列值（更新或删除前的值）
+}
+
+// binlogValue 伪 SQL 中的一个列值
+type binlogValue struct {
+	column string // 列序号（从 1 开始）
+	value  string
+}
+
+// rowLine 处理以 ### 开头的行事件伪 SQL
+func (s *binlogState) rowLine(line string, lineNo int) error {
+	if m := binlogRowRe.FindStringSubmatch(line); m != nil {
+		if err := s.flushRow(); err != nil {
+			return err
+		}
+		s.row = &binlogRow{kind: m[1], table: m[2], line: lineNo}
+		return nil
+	}
+	if s.row == nil {
+		return nil
+	}
+
+	switch strings.TrimSpace(strings.TrimPrefix(line, "###")) {
+	case "SET", "WHERE":
+		s.row.section = strings.TrimSpace(strings.TrimPrefix(line, "###"))
+		return nil
+	}
+
+	if m := binlogValueRe.FindStringSubmatch(line); m != nil {
+		v := binlogValue{column: m[1], value: binlogLiteral(m[2])}
+		if s.row.section == "WHERE" {
+			s.row.where = append(s.row.where, v)
+		} else {
+			s.row.set = append(s.row.set, v)
+		}
+	}
+	return nil
+}
+
+// flushRow 将当前行的伪 SQL 转换为 SQL 片段
+func (s *binlogState) flushRow() error {
+	row := s.row
+	if row == nil {
+		return nil
+	}
+	s.row = nil
+
+	name := func(v binlogValue) string {
+		columns := s.columns[row.table]
+		var i int
+		if _, err := fmt.Sscanf(v.column, "%d", &i); err == nil && i >= 1 && i <= len(columns) {
+			return columns[i-1]
+		}
+		return "col_" + v.column
+	}
+
+	var sql string
+	switch row.kind {
+	case "INSERT INTO":
+		names, values := make([]string, len(row.set)), make([]string, len(row.set))
+		for i, v := range row.set {
+			names[i], values[i] = name(v), v.value
+		}
+		sql = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", row.table, strings.Join(names, ", "), strings.Join(values, ", "))
+
+	case "UPDATE":
+		sets := make([]string, len(row.set))
+		for i, v := range row.set {
+			sets[i] = name(v) + " = " + v.value
+		}
+		sql = fmt.Sprintf("UPDATE %s SET %s WHERE %s", row.table, strings.Join(sets, ", "), binlogWhere(row.where, name))
+
+	case "DELETE FROM":
+		sql = fmt.Sprintf("DELETE FROM %s WHERE %s", row.table, binlogWhere(row.where, name))
+	}
+
+	meta := s.meta()
+	meta[BinlogMetaTable] = row.table
+	return s.emit(Segment{SQL: sql, Line: row.line, Meta: meta})
+}
+
+// binlogWhere 将更新或删除前的列值转换为 WHERE 条件
+func binlogWhere(values []binlogValue, name func(binlogValue) string) string {
+	if len(values) == 0 {
+		return "1 = 1"
+	}
+	conds := make([]string, len(values))
+	for i, v := range values {
+		if v.value == "NULL" {
+			conds[i] = name(v) + " IS NULL"
+		} else {
+			conds[i] = name(v) + " = " + v.value
+		}
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// binlogLiteral 提取列值中的字面量，去除 -vv 输出的类型注释和无符号整数的括号说明
+// 格式示例: 'alice' /* VARSTRING(80) meta=80 nullable=1 is_null=0 */、-1 (4294967295)
+func binlogLiteral(text string) string {
+	text = strings.TrimSpace(text)
+	start := strings.IndexByte(text, '\'')
+	if start < 0 || start > 1 {
+		// 数值、NULL 等不含引号的值
+		if i := strings.IndexAny(text, " \t"); i >= 0 {
+			return text[:i]
+		}
+		return text
+	}
+	// 字符串及 b'0101' 等带前缀的值，反斜杠转义下一个字符
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '\'':
+			return text[:i+1]
+		}
+	}
+	return text
+}
+
+// IsBinlog 判断内容是否为 mysqlbinlog 输出的文本格式
+// 读取开头最多 binlogSniffLines 行，出现 "#240115 10:30:45 server id 1  end_log_pos ..." 事件头部即视为 binlog。
+// 参数:
+//   - reader: 文件内容
+//
+// 返回值:
+//   - bool: true 表示 mysqlbinlog 输出
+func IsBinlog(reader io.Reader) bool {
+	scanner := newLineScanner(reader)
+	for i := 0; i < binlogSniffLines && scanner.Scan(); i++ {
+		if binlogHeaderRe.MatchString(strings.TrimSpace(scanner.Text())) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package inputparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/ybMigration/internal/testutils"
+)
+
+// TestBinlogFileParser_ParseSegments 测试从 mysqlbinlog -v 输出中提取语句和行事件
+func TestBinlogFileParser_ParseSegments(t *testing.T) {
+	segments, err := NewBinlogFileParser().ParseSegments(testutils.MustGetTestDataPath("binlog_example.sql"))
+	require.NoError(t, err)
+	require.Len(t, segments, 5, "BEGIN/COMMIT、SET 和 BINLOG 块不应作为语句输出")
+
+	ddl := segments[0]
+	assert.Equal(t, 21, ddl.Line)
+	assert.True(t, strings.HasPrefix(ddl.SQL, "CREATE TABLE users ("))
+	assert.True(t, strings.HasSuffix(ddl.SQL, ") ENGINE=InnoDB"))
+	assert.Equal(t, map[string]string{
+		BinlogMetaPosition:    "234",
+		BinlogMetaEndPosition: "420",
+		BinlogMetaTime:        "240115 10:31:00",
+		BinlogMetaServerID:    "1",
+		BinlogMetaEvent:       "Query",
+		BinlogMetaThreadID:    "8",
+		BinlogMetaGTID:        "3e11fa47-71ca-11e1-9e33-c80aa9429562:1",
+		BinlogMetaSchema:      "shop",
+		BinlogMetaTimestamp:   "1705314660",
+	}, ddl.Meta)
+
+	// 行事件中的每行数据对应一个片段，没有表结构时列名为 col_N
+	insert := segments[1]
+	assert.Equal(t, 43, insert.Line)
+	assert.Equal(t, "INSERT INTO `shop`.`users` (col_1, col_2) VALUES (1, 'alice')", insert.SQL)
+	assert.Equal(t, "630", insert.Meta[BinlogMetaPosition])
+	assert.Equal(t, "Write_rows", insert.Meta[BinlogMetaEvent])
+	assert.Equal(t, "3e11fa47-71ca-11e1-9e33-c80aa9429562:2", insert.Meta[BinlogMetaGTID])
+	assert.Equal(t, "`shop`.`users`", insert.Meta[BinlogMetaTable])
+	assert.Equal(t, "INSERT INTO `shop`.`users` (col_1, col_2) VALUES (2, 'it\\'s bob')", segments[2].SQL)
+
+	// --print-table-metadata 提供列名，-vv 的类型注释被去除
+	assert.Equal(t, "UPDATE `shop`.`users` SET id = 1, name = NULL WHERE id = 1 AND name = 'alice'", segments[3].SQL)
+	assert.Equal(t, "931", segments[3].Meta[BinlogMetaPosition])
+	assert.Equal(t, "DELETE FROM `shop`.`users` WHERE id = 2 AND name IS NULL", segments[4].SQL)
+	assert.Equal(t, "1705314780", segments[4].Meta[BinlogMetaTimestamp])
+}
+
+// TestBinlogLiteral 测试行事件列值的提取
+func TestBinlogLiteral(t *testing.T) {
+	tests := map[string]string{
+		"1":                                    "1",
+		"-1 (4294967295)":                      "-1",
+		"'a b' /* VARSTRING(80) meta=80 */":    "'a b'",
+		`'it\'s' /* STRING(10) meta=65034 */`:  `'it\'s'`,
+		"b'0101' /* BIT(4) meta=4 */":          "b'0101'",
+		"NULL /* VARSTRING(80) is_null=1 */":   "NULL",
+		"'2024-01-15 10:30:00' /* DATETIME */": "'2024-01-15 10:30:00'",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, binlogLiteral(input), input)
+	}
+}
+
+// TestIsBinlog 测试 mysqlbinlog 输出识别
+func TestIsBinlog(t *testing.T) {
+	file, err := OpenFile(testutils.MustGetTestDataPath("binlog_example.sql"))
+	require.NoError(t, err)
+	defer func() {
+		_ = file.Close()
+	}()
+	assert.True(t, IsBinlog(file))
+	assert.False(t, IsBinlog(strings.NewReader("-- MySQL dump 10.13\nCREATE TABLE t (id INT);\n")))
+}
@@ -0,0 +1,386 @@
+package inputparser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// performance_schema 语句摘要片段元数据的键名
+const (
+	DigestMetaSchema       = "schema"         // SCHEMA_NAME，语句执行时的默认数据库
+	DigestMetaDigest       = "digest"         // DIGEST，语句摘要的哈希值
+	DigestMetaCountStar    = "count_star"     // COUNT_STAR，语句的执行次数
+	DigestMetaSumTimerWait = "sum_timer_wait" // SUM_TIMER_WAIT，语句的累计执行耗时（皮秒）
+	DigestMetaTextSource   = "text_source"    // 实际分析的 SQL 取自哪一列，见 DigestColumnDigestText 等
+)
+
+// 语句摘要导出文件中使用的列名
+const (
+	DigestColumnSchema          = "SCHEMA_NAME"
+	DigestColumnDigest          = "DIGEST"
+	DigestColumnDigestText      = "DIGEST_TEXT"
+	DigestColumnQuerySampleText = "QUERY_SAMPLE_TEXT"
+	DigestColumnCountStar       = "COUNT_STAR"
+	DigestColumnSumTimerWait    = "SUM_TIMER_WAIT"
+)
+
+// digestSniffBytes 判断导出格式时读取的最大字节数
+const digestSniffBytes = 4096
+
+// utf8BOM Excel 等工具导出 CSV 时写入的字节顺序标记
+var utf8BOM = []byte("\xEF\xBB\xBF")
+
+var (
+	// digestListRe 匹配摘要中被折叠的值列表，如 IN (...)
+	digestListRe = regexp.MustCompile(`\(\s*\.\.\.\s*\)`)
+	// digestRowsRe 匹配摘要中被折叠的多行 VALUES，如 VALUES (...) /* , ... */
+	digestRowsRe = regexp.MustCompile(`\s*/\*\s*,\s*\.\.\.\s*\*/`)
+	// digestCallRe 匹配摘要中函数名与括号之间的空格，如 NOW ( )；反引号中的标识符整体匹配以便跳过
+	digestCallRe = regexp.MustCompile("`[^`]*`|\\b([A-Za-z_][A-Za-z0-9_$]*)\\s+\\(")
+)
+
+// DigestFileParser 解析 performance_schema.events_statements_summary_by_digest 的导出文件
+// 支持带表头的 CSV，以及 JSON 数组、JSON Lines 和 MySQL Shell 逐行输出的 JSON 对象，
+// 列名不区分大小写。每一行对应一个片段：
+//   - QUERY_SAMPLE_TEXT 非空且未被截断时分析示例语句，否则分析 DIGEST_TEXT
+//   - DIGEST_TEXT 中折叠的值列表 (...) 还原为 (?)，函数名与括号之间的空格删除，以便解析
+//   - SCHEMA_NAME、DIGEST、COUNT_STAR、SUM_TIMER_WAIT 记录到片段的元数据中，
+//     COUNT_STAR 作为片段的执行次数，SUM_TIMER_WAIT 作为片段的累计耗时
+//
+// 片段的行号为该行记录在导出文件中的起始行号。
+type DigestFileParser struct{}
+
+// NewDigestFileParser 创建并返回一个新的语句摘要导出文件解析器
+func NewDigestFileParser() *DigestFileParser {
+	return &DigestFileParser{}
+}
+
+// Parse 解析语句摘要导出文件
+// 参数 path 是导出文件的路径
+// 返回值: 提取的SQL语句字符串和可能的错误
+func (p *DigestFileParser) Parse(path string) (string, error) {
+	segments, err := p.ParseSegments(path)
+	if err != nil {
+		return "", err
+	}
+	sql, _ := JoinSegments(segments)
+	return sql, nil
+}
+
+// ParseSegments 解析语句摘要导出文件，每行记录对应一个片段
+// 参数 path 是导出文件的路径
+// 返回值: SQL 片段列表（含元数据）和可能的错误
+func (p *DigestFileParser) ParseSegments(path string) ([]Segment, error) {
+	return collectSegments(func(fn func(Segment) error) error {
+		return p.StreamSegments(path, fn)
+	})
+}
+
+// StreamSegments 逐行解析语句摘要导出文件，每得到一个片段调用一次 fn
+// 参数:
+//   - path: 导出文件的路径，扩展名为 .csv 或 .json
+//   - fn: 片段回调，返回错误时停止解析
+//
+// 返回:
+//   - error: 文件错误、格式错误或 fn 返回的错误
+func (p *DigestFileParser) StreamSegments(path string, fn func(Segment) error) error {
+	if err := checkFile(path); err != nil {
+		return err
+	}
+
+	if ext := InputExt(path); ext != ".csv" && ext != ".json" {
+		return fmt.Errorf("不支持的文件类型: %s，语句摘要导出文件应为 .csv 或 .json 扩展名", ext)
+	}
+
+	file, err := openText(path, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "关闭文件 %s 失败: %v\n", path, err)
+		}
+	}()
+
+	return p.ReadSegments(file, fn)
+}
+
+// ReadSegments 从已打开的输入流中解析语句摘要导出，每得到一个片段调用一次 fn
+// 用于归档成员等没有独立文件路径的输入，调用方负责打开与关闭 reader。
+// 以 [ 或 { 开头的内容按 JSON 解析，其他按 CSV 解析。
+func (p *DigestFileParser) ReadSegments(reader io.Reader, fn func(Segment) error) error {
+	br := bufio.NewReader(reader)
+	if head, _ := br.Peek(len(utf8BOM)); bytes.Equal(head, utf8BOM) {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+	if isJSONInput(br) {
+		return readDigestJSON(br, fn)
+	}
+	return readDigestCSV(br, fn)
+}
+
+// readDigestCSV 解析带表头的 CSV 导出
+func readDigestCSV(reader io.Reader, fn func(Segment) error) error {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.ReuseRecord = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取 CSV 表头失败: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns[DigestColumnDigestText]; !ok {
+		return fmt.Errorf("不是语句摘要导出文件: CSV 表头缺少 %s 列", DigestColumnDigestText)
+	}
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取 CSV 记录失败: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		row := make(map[string]string, len(columns))
+		for name, i := range columns {
+			if i < len(record) {
+				row[name] = record[i]
+			}
+		}
+		if seg, ok := digestSegment(row, line); ok {
+			if err := fn(seg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readDigestJSON 解析 JSON 数组或连续的 JSON 对象
+func readDigestJSON(reader *bufio.Reader, fn func(Segment) error) error {
+	lines := &lineTracker{reader: reader, line: 1}
+	decoder := json.NewDecoder(lines)
+	decoder.UseNumber()
+
+	inArray := false
+	if first, _ := firstNonSpace(reader); first == '[' {
+		if _, err := decoder.Token(); err != nil {
+			return fmt.Errorf("读取 JSON 失败: %w", err)
+		}
+		inArray = true
+	}
+
+	for decoder.More() {
+		line := lines.lineAt(decoder.InputOffset())
+		var object map[string]any
+		if err := decoder.Decode(&object); err != nil {
+			return fmt.Errorf("第 %d 行 JSON 记录解析失败: %w", line, err)
+		}
+		row := make(map[string]string, len(object))
+		for name, value := range object {
+			row[strings.ToUpper(name)] = jsonString(value)
+		}
+		if _, ok := row[DigestColumnDigestText]; !ok {
+			return fmt.Errorf("不是语句摘要导出文件: 第 %d 行记录缺少 %s 字段", line, DigestColumnDigestText)
+		}
+		if seg, ok := digestSegment(row, line); ok {
+			if err := fn(seg); err != nil {
+				return err
+			}
+		}
+	}
+
+	if inArray {
+		if _, err := decoder.Token(); err != nil {
+			return fmt.Errorf("读取 JSON 失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// digestSegment 将一行导出记录转换为片段
+// 两列 SQL 文本都为空时（如 DIGEST 为 NULL 的汇总行）跳过该行。
+func digestSegment(row map[string]string, line int) (Segment, bool) {
+	sql, source := strings.TrimSpace(nullable(row[DigestColumnQuerySampleText])), DigestColumnQuerySampleText
+	if sql == "" || isTruncated(sql) {
+		sql, source = normalizeDigestText(nullable(row[DigestColumnDigestText])), DigestColumnDigestText
+	}
+	if sql == "" {
+		return Segment{}, false
+	}
+
+	meta := map[string]string{DigestMetaTextSource: source}
+	for key, column := range map[string]string{
+		DigestMetaSchema:       DigestColumnSchema,
+		DigestMetaDigest:       DigestColumnDigest,
+		DigestMetaCountStar:    DigestColumnCountStar,
+		DigestMetaSumTimerWait: DigestColumnSumTimerWait,
+	} {
+		if v := strings.TrimSpace(nullable(row[column])); v != "" {
+			meta[key] = v
+		}
+	}
+	return Segment{SQL: sql, Line: line, Meta: meta}, true
+}
+
+// normalizeDigestText 将 DIGEST_TEXT 还原为可解析的 SQL
+// 折叠的值列表 (...) 替换为 (?)，多行 VALUES 的 /* , ... */ 标记删除，
+// 字面量已由 MySQL 替换为 ?，标识符保留反引号。
+// DIGEST_TEXT 在每个记号之间加空格，函数调用写作 NOW ( )、COUNT ( * )，
+// 没有 IGNORE_SPACE 时 NOW、COUNT、GROUP_CONCAT 等内置函数后有空格无法解析为函数调用。
+// 摘要中的标识符都带反引号，不带反引号的单词只有关键字与函数名，删除其后与括号之间的空格不改变语义。
+func normalizeDigestText(text string) string {
+	text = digestRowsRe.ReplaceAllString(text, "")
+	text = digestListRe.ReplaceAllString(text, "(?)")
+	text = digestCallRe.ReplaceAllStringFunc(text, func(m string) string {
+		if strings.HasPrefix(m, "`") {
+			return m
+		}
+		return strings.TrimRight(m[:len(m)-1], " \t\r\n") + "("
+	})
+	return strings.TrimSpace(text)
+}
+
+// isTruncated 判断 SQL 文本是否因超过 performance_schema 的长度限制被截断
+func isTruncated(sql string) bool {
+	return strings.HasSuffix(sql, "...")
+}
+
+// nullable 将导出中表示 NULL 的值转换为空字符串
+func nullable(v string) string {
+	if v == "NULL" || v == `\N` {
+		return ""
+	}
+	return v
+}
+
+// jsonString 将 JSON 值转换为字符串，null 转换为空字符串
+func jsonString(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// isJSONInput 判断输入的第一个非空白字符是否为 [ 或 {
+func isJSONInput(reader *bufio.Reader) bool {
+	c, err := firstNonSpace(reader)
+	return err == nil && (c == '[' || c == '{')
+}
+
+// firstNonSpace 预读输入的第一个非空白字符，不消耗输入
+func firstNonSpace(reader *bufio.Reader) (byte, error) {
+	for n := 1; ; n++ {
+		head, err := reader.Peek(n)
+		if len(head) < n {
+			return 0, err
+		}
+		switch c := head[n-1]; c {
+		case ' ', '\t', '\r', '\n':
+		default:
+			return c, nil
+		}
+	}
+}
+
+// lineTracker 记录已读取内容的行号，用于将 JSON 解码器的偏移量换算为行号
+// 只保留解码器已读取但尚未换算的内容，内存占用与解码器的缓冲区相当。
+type lineTracker struct {
+	reader  io.Reader
+	pending []byte // 已读取、尚未换算的内容
+	base    int64  // pending 开头在输入中的偏移量
+	line    int    // base 所在的行号
+}
+
+// Read 读取内容并保留副本
+func (t *lineTracker) Read(p []byte) (int, error) {
+	n, err := t.reader.Read(p)
+	t.pending = append(t.pending, p[:n]...)
+	return n, err
+}
+
+// lineAt 返回偏移量 offset 之后第一个非空白字符所在的行号
+// offset 必须单调递增。
+func (t *lineTracker) lineAt(offset int64) int {
+	consumed := t.pending[:offset-t.base]
+	t.line += bytes.Count(consumed, []byte("\n"))
+	t.pending = t.pending[len(consumed):]
+	t.base = offset
+
+	line := t.line
+	for _, c := range t.pending {
+		if c == '\n' {
+			line++
+		} else if c != ' ' && c != '\t' && c != '\r' && c != ',' {
+			break
+		}
+	}
+	return line
+}
+
+// IsDigestExport 判断输入是否为语句摘要导出（CSV 表头或 JSON 对象中包含 DIGEST_TEXT）
+// 参数:
+//   - reader: 输入内容
+//
+// 返回值:
+//   - bool: true 表示语句摘要导出
+func IsDigestExport(reader io.Reader) bool {
+	head := make([]byte, digestSniffBytes)
+	n, _ := io.ReadFull(reader, head)
+	head = bytes.TrimLeft(bytes.TrimPrefix(head[:n], utf8BOM), " \t\r\n")
+	if len(head) == 0 {
+		return false
+	}
+
+	upper := bytes.ToUpper(head)
+	if head[0] == '[' || head[0] == '{' {
+		return bytes.Contains(upper, []byte(`"`+DigestColumnDigestText+`"`))
+	}
+	if i := bytes.IndexByte(upper, '\n'); i >= 0 {
+		upper = upper[:i]
+	}
+	for _, column := range strings.Split(string(upper), ",") {
+		if strings.Trim(strings.TrimSpace(column), `"`) == DigestColumnDigestText {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDigestExportFile 判断文件是否为语句摘要导出
+// 参数:
+//   - path: 文件路径
+//
+// 返回值:
+//   - bool: true 表示语句摘要导出，文件无法读取时返回 false
+func IsDigestExportFile(path string) bool {
+	file, err := OpenFile(path)
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return IsDigestExport(file)
+}
@@ -0,0 +1,121 @@
+package inputparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sqlparser "github.com/example/ybMigration/internal/sql-parser"
+	"github.com/example/ybMigration/internal/testutils"
+)
+
+// TestDigestFileParser_CSV 测试解析 CSV 格式的语句摘要导出
+func TestDigestFileParser_CSV(t *testing.T) {
+	segments, err := NewDigestFileParser().ParseSegments(testutils.MustGetTestDataPath("digest_example.csv"))
+	require.NoError(t, err)
+	require.Len(t, segments, 3, "DIGEST_TEXT 为 NULL 的汇总行应跳过")
+
+	// 优先分析示例语句，元数据保留执行次数与耗时
+	first := segments[0]
+	assert.Equal(t, 2, first.Line)
+	assert.Equal(t, "SELECT IFNULL(name, 'n/a') FROM users WHERE id IN (1, 2, 3)", first.SQL)
+	assert.Equal(t, map[string]string{
+		DigestMetaSchema:       "shop",
+		DigestMetaDigest:       "3b4c9f0e2a61d8c7b5e4f3a2d1c0b9a8e7f6d5c4b3a2918273645f4e3d2c1b0a",
+		DigestMetaCountStar:    "1520",
+		DigestMetaSumTimerWait: "98000000000000",
+		DigestMetaTextSource:   DigestColumnQuerySampleText,
+	}, first.Meta)
+	assert.Equal(t, 1520, first.Executions())
+	assert.False(t, first.Deferred())
+	assert.InDelta(t, 98.0, first.Latency(), 1e-9)
+
+	// 多行示例语句的行号为记录的起始行
+	assert.Equal(t, 3, segments[1].Line)
+	assert.Equal(t, "SELECT GROUP_CONCAT(name)\nFROM users\nWHERE status = 'active'", segments[1].SQL)
+
+	// 示例语句被截断时分析 DIGEST_TEXT，折叠的值列表还原为占位符
+	insert := segments[2]
+	assert.Equal(t, 6, insert.Line)
+	assert.Equal(t, "INSERT INTO `orders` ( `id` , `user_id` , `note` ) VALUES(?)", insert.SQL)
+	assert.Equal(t, DigestColumnDigestText, insert.Meta[DigestMetaTextSource])
+}
+
+// TestDigestFileParser_JSON 测试解析 JSON 数组与 JSON Lines 格式的语句摘要导出
+func TestDigestFileParser_JSON(t *testing.T) {
+	segments, err := NewDigestFileParser().ParseSegments(testutils.MustGetTestDataPath("digest_example.json"))
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+
+	assert.Equal(t, 2, segments[0].Line)
+	assert.Equal(t, "SELECT IFNULL(name, 'n/a') FROM users WHERE id IN (1, 2, 3)", segments[0].SQL)
+	assert.Equal(t, "98000000000000", segments[0].Meta[DigestMetaSumTimerWait])
+
+	// QUERY_SAMPLE_TEXT 为 null 时分析 DIGEST_TEXT，SCHEMA_NAME 为 null 时不记录
+	assert.Equal(t, 10, segments[1].Line)
+	assert.Equal(t, "SELECT GROUP_CONCAT( `name` ) FROM `users` WHERE `status` = ?", segments[1].SQL)
+	assert.Equal(t, 42, segments[1].Executions())
+	assert.NotContains(t, segments[1].Meta, DigestMetaSchema)
+
+	t.Run("json_lines", func(t *testing.T) {
+		input := "\xEF\xBB\xBF" + `{"schema_name":"shop","digest_text":"SELECT ?","count_star":3}` + "\n\n" +
+			`{"schema_name":"shop","digest_text":"SELECT NOW ( )","count_star":5}` + "\n"
+		var lines []int
+		err := NewDigestFileParser().ReadSegments(strings.NewReader(input), func(seg Segment) error {
+			lines = append(lines, seg.Line)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 3}, lines)
+	})
+
+	t.Run("missing_digest_text", func(t *testing.T) {
+		err := NewDigestFileParser().ReadSegments(strings.NewReader(`[{"name":"pom"}]`), func(Segment) error { return nil })
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "缺少 DIGEST_TEXT 字段")
+	})
+}
+
+// TestNormalizeDigestText 测试还原后的 DIGEST_TEXT 可以被解析
+func TestNormalizeDigestText(t *testing.T) {
+	tests := []struct {
+		digest string
+		want   string
+	}{
+		{"SELECT NOW ( )", "SELECT NOW( )"},
+		{"SELECT COUNT ( * ) FROM `orders` WHERE `created_at` > NOW ( ) - INTERVAL ? DAY",
+			"SELECT COUNT( * ) FROM `orders` WHERE `created_at` > NOW( ) - INTERVAL ? DAY"},
+		{"SELECT GROUP_CONCAT ( `name` ORDER BY `id` ) FROM `users` WHERE `id` IN (...)",
+			"SELECT GROUP_CONCAT( `name` ORDER BY `id` ) FROM `users` WHERE `id` IN(?)"},
+		{"INSERT INTO `count` ( `id` , `sum` ) VALUES (...) /* , ... */",
+			"INSERT INTO `count` ( `id` , `sum` ) VALUES(?)"},
+		{"SELECT `a` FROM `t` WHERE `b` = ? AND ( `c` = ? OR EXISTS ( SELECT ? ) )",
+			"SELECT `a` FROM `t` WHERE `b` = ? AND( `c` = ? OR EXISTS( SELECT ? ) )"},
+	}
+	parser := sqlparser.NewSQLParser()
+	for _, tt := range tests {
+		got := normalizeDigestText(tt.digest)
+		assert.Equal(t, tt.want, got)
+		_, err := parser.ParseSQL(got)
+		assert.NoError(t, err, "DIGEST_TEXT 应能解析: %s", got)
+	}
+
+	// 导出文件中只有 DIGEST_TEXT 的记录同样可以解析
+	segments, err := NewDigestFileParser().ParseSegments(testutils.MustGetTestDataPath("digest_example.json"))
+	require.NoError(t, err)
+	for _, seg := range segments {
+		_, err := parser.ParseSQL(seg.SQL)
+		assert.NoError(t, err, "片段应能解析: %s", seg.SQL)
+	}
+}
+
+// TestIsDigestExport 测试语句摘要导出的格式识别
+func TestIsDigestExport(t *testing.T) {
+	assert.True(t, IsDigestExportFile(testutils.MustGetTestDataPath("digest_example.csv")))
+	assert.True(t, IsDigestExportFile(testutils.MustGetTestDataPath("digest_example.json")))
+	assert.True(t, IsDigestExport(strings.NewReader("\"schema_name\",\"digest_text\"\n")))
+	assert.False(t, IsDigestExport(strings.NewReader("id,name\n1,digest_text\n")))
+	assert.False(t, IsDigestExport(strings.NewReader(`{"name": "ybMigration"}`)))
+}
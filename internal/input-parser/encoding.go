@@ -0,0 +1,318 @@
+package inputparser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// 输入编码名称，与 MySQL 字符集名称保持一致
+const (
+	EncodingAuto    = "auto"    // 自动识别，默认值
+	EncodingUTF8    = "utf8"    // UTF-8（MySQL 的 utf8、utf8mb3、utf8mb4）
+	EncodingUTF16LE = "utf16le" // UTF-16 小端，仅通过 BOM 识别
+	EncodingUTF16BE = "utf16be" // UTF-16 大端，仅通过 BOM 识别
+	EncodingGBK     = "gbk"     // GBK（兼容 GB2312）
+	EncodingGB18030 = "gb18030" // GB18030
+	EncodingBig5    = "big5"    // Big5
+	EncodingLatin1  = "latin1"  // MySQL 的 latin1，实际为 Windows-1252
+)
+
+// 编码的识别依据
+const (
+	EncodingSourceBOM      = "bom"       // 文件开头的字节顺序标记
+	EncodingSourceConfig   = "config"    // input.encoding 配置
+	EncodingSourceSetNames = "set_names" // SET NAMES / SET CHARACTER SET 语句
+	EncodingSourceDetected = "detected"  // 按内容推断
+)
+
+// 编码问题元数据的键名
+const (
+	EncodingMetaName   = "encoding"        // 输入的编码名称
+	EncodingMetaSource = "encoding_source" // 编码的识别依据
+)
+
+// encodingSniffBytes 识别编码时预读的字节数
+const encodingSniffBytes = 64 * 1024
+
+// maxInvalidLines 记录无法解码的字节序列的最大行数，超出部分只计数
+const maxInvalidLines = 100
+
+// utf8ValidRatio 内容中合法的 UTF-8 多字节字符数至少为非法字节数的该倍数时，仍按 UTF-8 处理
+// GBK 文本中偶然构成合法 UTF-8 多字节序列的字符数约为非法字节数的 0.1～0.2 倍，latin1 文本几乎为 0。
+const utf8ValidRatio = 4
+
+var (
+	// setNamesRe 匹配 SET NAMES、SET CHARACTER SET 语句中的字符集，包括 mysqldump 的版本注释形式
+	setNamesRe = regexp.MustCompile("(?i)\\bSET\\s+(?:NAMES|CHARACTER\\s+SET|CHARSET)\\s+['\"`]?(\\w+)")
+
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+
+	// replacementChar 解码器替换无法解码的字节序列时写入的 U+FFFD
+	replacementChar = []byte(string(utf8.RuneError))
+)
+
+// encodingAliases MySQL 字符集名称及常见别名到编码名称的映射
+var encodingAliases = map[string]string{
+	"utf8":     EncodingUTF8,
+	"utf8mb3":  EncodingUTF8,
+	"utf8mb4":  EncodingUTF8,
+	"ascii":    EncodingUTF8,
+	"utf16le":  EncodingUTF16LE,
+	"utf16be":  EncodingUTF16BE,
+	"gbk":      EncodingGBK,
+	"gb2312":   EncodingGBK,
+	"cp936":    EncodingGBK,
+	"gb18030":  EncodingGB18030,
+	"big5":     EncodingBig5,
+	"latin1":   EncodingLatin1,
+	"cp1252":   EncodingLatin1,
+	"iso88591": EncodingLatin1,
+}
+
+// encodings 编码名称对应的解码器
+var encodings = map[string]encoding.Encoding{
+	EncodingUTF8:    unicode.UTF8BOM,
+	EncodingUTF16LE: unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	EncodingUTF16BE: unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+	EncodingGBK:     simplifiedchinese.GBK,
+	EncodingGB18030: simplifiedchinese.GB18030,
+	EncodingBig5:    traditionalchinese.Big5,
+	EncodingLatin1:  charmap.Windows1252,
+}
+
+// NormalizeEncoding 将配置或 SET NAMES 中的字符集名称规范化为编码名称
+// 参数:
+//   - name: 字符集名称，不区分大小写，空字符串视为 auto
+//
+// 返回值:
+//   - string: 规范化后的编码名称，如 utf8mb4 返回 utf8
+//   - bool: 是否为受支持的编码
+func NormalizeEncoding(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == EncodingAuto {
+		return EncodingAuto, true
+	}
+	name = strings.NewReplacer("_", "", "-", "").Replace(name)
+	encoding, ok := encodingAliases[name]
+	return encoding, ok
+}
+
+// InvalidSequence 输入中一行内无法解码的字节序列
+type InvalidSequence struct {
+	Line  int // 行号（从 1 开始）
+	Count int // 该行中被替换为 U+FFFD 的字节序列数量
+}
+
+// DecodeReader 将输入转换为 UTF-8 的读取流
+// 编码按以下顺序确定：BOM、configured 指定的编码、开头的 SET NAMES 语句、按内容推断。
+// 无法解码的字节序列替换为 U+FFFD 并记录所在行号；转换前后换行符不变，行号与原始输入一致。
+type DecodeReader struct {
+	reader   io.Reader
+	encoding string
+	source   string
+
+	line    int               // 当前读取到的行号
+	match   int               // 已匹配的 U+FFFD 前缀字节数，U+FFFD 可能跨越两次读取
+	invalid []InvalidSequence // 包含无法解码字节序列的行，最多 maxInvalidLines 行
+	dropped int               // 超出 maxInvalidLines 后未记录的行数
+}
+
+// NewDecodeReader 识别输入编码并创建转换为 UTF-8 的读取流
+// 参数:
+//   - r: 原始输入
+//   - configured: 配置的编码名称，为空或 auto 时自动识别
+//
+// 返回值:
+//   - *DecodeReader: UTF-8 读取流
+//   - error: 配置的编码不受支持时返回错误
+func NewDecodeReader(r io.Reader, configured string) (*DecodeReader, error) {
+	name, ok := NormalizeEncoding(configured)
+	if !ok {
+		return nil, fmt.Errorf("不支持的输入编码: %s", configured)
+	}
+
+	br := bufio.NewReaderSize(r, encodingSniffBytes)
+	head, _ := br.Peek(encodingSniffBytes)
+	name, source := DetectEncoding(head, name)
+	return &DecodeReader{
+		reader:   transform.NewReader(br, encodings[name].NewDecoder()),
+		encoding: name,
+		source:   source,
+		line:     1,
+	}, nil
+}
+
+// Encoding 返回输入的编码名称
+func (d *DecodeReader) Encoding() string {
+	return d.encoding
+}
+
+// Source 返回编码的识别依据，见 EncodingSourceBOM 等
+func (d *DecodeReader) Source() string {
+	return d.source
+}
+
+// Invalid 返回包含无法解码字节序列的行，以及超出记录上限未记录的行数
+// 应在读取完输入后调用。
+func (d *DecodeReader) Invalid() ([]InvalidSequence, int) {
+	return d.invalid, d.dropped
+}
+
+// Read 读取转换后的 UTF-8 内容，并记录 U+FFFD 所在的行号
+func (d *DecodeReader) Read(p []byte) (int, error) {
+	n, err := d.reader.Read(p)
+	chunk := p[:n]
+	if d.match == 0 && bytes.IndexByte(chunk, replacementChar[0]) < 0 {
+		d.line += bytes.Count(chunk, []byte("\n"))
+		return n, err
+	}
+	for _, c := range chunk {
+		if c == replacementChar[d.match] {
+			d.match++
+			if d.match == len(replacementChar) {
+				d.match = 0
+				d.record()
+			}
+			continue
+		}
+		d.match = 0
+		if c == replacementChar[0] {
+			d.match = 1
+		}
+		if c == '\n' {
+			d.line++
+		}
+	}
+	return n, err
+}
+
+// record 记录当前行中的一处无法解码的字节序列
+func (d *DecodeReader) record() {
+	if last := len(d.invalid) - 1; last >= 0 && d.invalid[last].Line == d.line {
+		d.invalid[last].Count++
+		return
+	}
+	if len(d.invalid) >= maxInvalidLines {
+		d.dropped++
+		return
+	}
+	d.invalid = append(d.invalid, InvalidSequence{Line: d.line, Count: 1})
+}
+
+// DetectEncoding 根据输入开头的内容确定编码
+// 参数:
+//   - head: 输入开头的内容
+//   - configured: 规范化后的配置编码，EncodingAuto 表示自动识别
+//
+// 返回值:
+//   - string: 编码名称
+//   - string: 识别依据，见 EncodingSourceBOM 等
+//
+// 说明:
+//
+//	BOM 优先于配置；自动识别时采用开头 SET NAMES 语句声明的字符集，
+//	但声明为 latin1 而内容是包含非 ASCII 字符的合法 UTF-8 时，按 UTF-8 处理
+//	（常见于以 latin1 连接导出的 UTF-8 数据）；
+//	没有声明时，合法的 UTF-8 按 UTF-8 处理，可以按 GBK 完整解码的按 GBK 处理，其余按 latin1 处理。
+//	只有少量非法字节、其余都是合法 UTF-8 多字节字符的内容（如个别损坏的字节）同样按 UTF-8 处理，
+//	非法字节由 DecodeReader 替换为 U+FFFD 并报告所在行，不把整个文件按其他编码转换为乱码。
+func DetectEncoding(head []byte, configured string) (string, string) {
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		return EncodingUTF8, EncodingSourceBOM
+	case bytes.HasPrefix(head, bomUTF16LE):
+		return EncodingUTF16LE, EncodingSourceBOM
+	case bytes.HasPrefix(head, bomUTF16BE):
+		return EncodingUTF16BE, EncodingSourceBOM
+	}
+	if configured != EncodingAuto && configured != "" {
+		return configured, EncodingSourceConfig
+	}
+
+	// 预读的内容可能截断在多字节字符中间，只检查到最后一个换行符
+	if len(head) >= encodingSniffBytes {
+		if i := bytes.LastIndexByte(head, '\n'); i >= 0 {
+			head = head[:i+1]
+		}
+	}
+	validUTF8 := isMostlyUTF8(head)
+
+	if m := setNamesRe.FindSubmatch(head); m != nil {
+		if name, ok := NormalizeEncoding(string(m[1])); ok && name != EncodingAuto {
+			if !(name == EncodingLatin1 && validUTF8 && !isASCII(head)) {
+				return name, EncodingSourceSetNames
+			}
+		}
+	}
+
+	switch {
+	case validUTF8:
+		return EncodingUTF8, EncodingSourceDetected
+	case decodesCleanly(simplifiedchinese.GBK, head):
+		return EncodingGBK, EncodingSourceDetected
+	}
+	return EncodingLatin1, EncodingSourceDetected
+}
+
+// isMostlyUTF8 判断内容是否为 UTF-8：完全合法，或合法的多字节字符数不少于非法字节数的 utf8ValidRatio 倍
+func isMostlyUTF8(content []byte) bool {
+	if utf8.Valid(content) {
+		return true
+	}
+	valid, invalid := 0, 0
+	for len(content) > 0 {
+		r, size := utf8.DecodeRune(content)
+		switch {
+		case r == utf8.RuneError && size == 1:
+			invalid++
+		case size > 1:
+			valid++
+		}
+		content = content[size:]
+	}
+	return valid >= invalid*utf8ValidRatio
+}
+
+// decodesCleanly 判断内容能否按指定编码完整解码
+func decodesCleanly(enc encoding.Encoding, content []byte) bool {
+	decoded, err := enc.NewDecoder().Bytes(content)
+	return err == nil && !bytes.Contains(decoded, replacementChar)
+}
+
+// isASCII 判断内容是否只包含 ASCII 字符
+func isASCII(content []byte) bool {
+	for _, c := range content {
+		if c >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// openText 打开输入文件并自动识别编码，返回 UTF-8 内容，调用方负责关闭
+func openText(path, configured string) (io.ReadCloser, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := NewDecodeReader(file, configured)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &multiCloser{Reader: decoder, closers: []io.Closer{file}}, nil
+}
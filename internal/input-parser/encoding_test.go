@@ -0,0 +1,119 @@
+package inputparser
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// gbkString 将 UTF-8 文本编码为 GBK
+func gbkString(t *testing.T, s string) string {
+	t.Helper()
+	encoded, err := simplifiedchinese.GBK.NewEncoder().String(s)
+	require.NoError(t, err)
+	return encoded
+}
+
+// TestDetectEncoding 测试根据 BOM、配置、SET NAMES 和内容识别编码
+func TestDetectEncoding(t *testing.T) {
+	latin1, err := charmap.Windows1252.NewEncoder().String("INSERT INTO t VALUES ('café');\n")
+	require.NoError(t, err)
+	gbk := gbkString(t, "-- 用户表\nINSERT INTO users VALUES ('张三');\n")
+
+	tests := []struct {
+		name       string
+		head       string
+		configured string
+		encoding   string
+		source     string
+	}{
+		{"ascii", "SELECT 1;\n", EncodingAuto, EncodingUTF8, EncodingSourceDetected},
+		{"utf8", "-- 用户表\nSELECT 1;\n", EncodingAuto, EncodingUTF8, EncodingSourceDetected},
+		{"utf8_bom", "\xEF\xBB\xBFSELECT 1;\n", EncodingGBK, EncodingUTF8, EncodingSourceBOM},
+		{"utf16le_bom", "\xFF\xFES\x00", EncodingAuto, EncodingUTF16LE, EncodingSourceBOM},
+		{"configured", "SELECT 1;\n", EncodingLatin1, EncodingLatin1, EncodingSourceConfig},
+		{"set_names", "/*!40101 SET NAMES gbk */;\nSELECT 1;\n", EncodingAuto, EncodingGBK, EncodingSourceSetNames},
+		{"set_names_utf8mb4", "SET NAMES utf8mb4;\n" + gbk, EncodingAuto, EncodingUTF8, EncodingSourceSetNames},
+		{"set_names_latin1_utf8_content", "SET NAMES latin1;\n-- 用户表\n", EncodingAuto, EncodingUTF8, EncodingSourceDetected},
+		{"gbk", gbk, EncodingAuto, EncodingGBK, EncodingSourceDetected},
+		{"latin1", latin1, EncodingAuto, EncodingLatin1, EncodingSourceDetected},
+		{"utf8_with_invalid_byte", "-- 用户表\nINSERT INTO users VALUES ('张三\xFF');\n", EncodingAuto, EncodingUTF8, EncodingSourceDetected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoding, source := DetectEncoding([]byte(tt.head), tt.configured)
+			assert.Equal(t, tt.encoding, encoding)
+			assert.Equal(t, tt.source, source)
+		})
+	}
+
+	name, ok := NormalizeEncoding("UTF8MB4")
+	assert.True(t, ok)
+	assert.Equal(t, EncodingUTF8, name)
+	name, ok = NormalizeEncoding("ISO-8859-1")
+	assert.True(t, ok)
+	assert.Equal(t, EncodingLatin1, name)
+	_, ok = NormalizeEncoding("ebcdic")
+	assert.False(t, ok)
+}
+
+// TestDecodeReader 测试转换为 UTF-8 并记录无法解码的字节序列
+func TestDecodeReader(t *testing.T) {
+	t.Run("gbk", func(t *testing.T) {
+		d, err := NewDecodeReader(strings.NewReader(gbkString(t, "-- 用户表\nSELECT '张三';\n")), "")
+		require.NoError(t, err)
+		content, err := io.ReadAll(d)
+		require.NoError(t, err)
+		assert.Equal(t, "-- 用户表\nSELECT '张三';\n", string(content))
+		assert.Equal(t, EncodingGBK, d.Encoding())
+		invalid, dropped := d.Invalid()
+		assert.Empty(t, invalid)
+		assert.Zero(t, dropped)
+	})
+
+	t.Run("invalid_sequences", func(t *testing.T) {
+		input := "SELECT 1;\nSELECT 'a\xFFb\xFE';\nSELECT 2;\nSELECT '\xFF';\n"
+		d, err := NewDecodeReader(strings.NewReader(input), EncodingUTF8)
+		require.NoError(t, err)
+		content, err := io.ReadAll(d)
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT 1;\nSELECT 'a�b�';\nSELECT 2;\nSELECT '�';\n", string(content))
+		invalid, dropped := d.Invalid()
+		assert.Equal(t, []InvalidSequence{{Line: 2, Count: 2}, {Line: 4, Count: 1}}, invalid)
+		assert.Zero(t, dropped)
+	})
+
+	t.Run("utf8_with_invalid_byte", func(t *testing.T) {
+		// 个别损坏的字节不影响其余内容按 UTF-8 读取
+		input := "-- 中文注释\nSELECT '中文\xFF';\n"
+		d, err := NewDecodeReader(strings.NewReader(input), "")
+		require.NoError(t, err)
+		content, err := io.ReadAll(d)
+		require.NoError(t, err)
+		assert.Equal(t, EncodingUTF8, d.Encoding())
+		assert.Equal(t, "-- 中文注释\nSELECT '中文�';\n", string(content))
+		invalid, _ := d.Invalid()
+		assert.Equal(t, []InvalidSequence{{Line: 2, Count: 1}}, invalid)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := NewDecodeReader(strings.NewReader("SELECT 1;"), "ebcdic")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "不支持的输入编码: ebcdic")
+	})
+
+	t.Run("sql_file_parser", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "legacy.sql")
+		require.NoError(t, os.WriteFile(path, []byte(gbkString(t, "INSERT INTO users VALUES ('李四');\n")), 0600))
+		sql, err := NewSQLFileParser().Parse(path)
+		require.NoError(t, err)
+		assert.Equal(t, "INSERT INTO users VALUES ('李四');\n", sql)
+	})
+}
@@ -3,13 +3,14 @@
 package inputparser
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+
+	sqlparser "github.com/example/ybMigration/internal/sql-parser"
 )
 
 // 日志解析常量
@@ -18,17 +19,48 @@ const (
 	MinLogMatches = 5
 )
 
+// general log 元数据的键名
+const (
+	GeneralMetaTime       = "time"       // 日志头部中的时间
+	GeneralMetaThreadID   = "id"         // 连接（线程）ID
+	GeneralMetaCommand    = "command"    // 命令类型，Query 或 Prepare
+	GeneralMetaExecutions = "executions" // 预处理语句通过 Execute 执行的次数
+)
+
+// generalLogCommands general log 中可能出现的命令类型
+// 多个单词的命令放在前面，避免被单个单词的命令（如 Connect、Reset）截断。
+var generalLogCommands = []string{
+	"Init DB", "Close stmt", "Field List", "Change user", "Binlog Dump",
+	"Long Data", "Reset stmt", "Set option", "Table Dump", "Connect Out",
+	"Register Slave", "Delayed insert", "Reset connection",
+	"Query", "Connect", "Quit", "Prepare", "Execute", "Fetch", "Statistics",
+	"Ping", "Shutdown", "Kill", "Refresh", "Debug", "Processlist", "Time",
+	"Daemon", "Sleep", "Error",
+}
+
 // GeneralLogFileParser 专门用于解析MySQL general log文件
-// 支持从MySQL general log中提取SQL查询语句
-// 注意：此解析器仅处理标准格式的MySQL general log。
+// 支持从MySQL general log中提取SQL查询语句，支持以下头部格式：
+//   - MySQL 5.7+: 2023-12-23T08:00:01.234567Z     1 Query     SELECT * FROM users
+//   - MySQL 5.6 及更早版本: 231223  8:00:01\t    1 Query\tSELECT * FROM users
+//   - 同一秒内的后续记录省略时间: \t\t    2 Query\tSELECT 1
+//
+// 多行 SQL 的后续行没有头部，会被拼接到上一条记录上，直到遇到下一个头部行。
+//
+// 除 Query 外还处理预处理语句：每个不同的 Prepare 语句只输出一次（保留 ? 占位符），
+// 同一连接中 Execute 命令记录的已代入参数的语句会匹配到对应的 Prepare 语句并计数，
+// 执行次数记录在片段元数据 GeneralMetaExecutions 中；匹配不到 Prepare 的 Execute 作为普通片段逐条输出，
+// 由分析器按语句指纹合并。
+//
+// 解析器按线程ID维护每个连接的会话状态（当前数据库、sql_mode、autocommit、所在事务），
+// USE、SET 和事务控制语句只用于更新会话状态，其余语句的元数据中记录执行时的会话状态。
 type GeneralLogFileParser struct {
-	// 匹配MySQL general log中的行
-	// 格式示例: 2023-12-23T08:00:01.234567Z     1 Query     SELECT * FROM users
+	// 匹配MySQL general log中的头部行
 	// 组1: 完整匹配
-	// 组2: 时间戳
-	// 组3: 线程ID
-	// 组4: 命令类型(Query, Connect等)
-	// 组5: SQL语句
+	// 组2: 时间戳（ISO 8601 格式）
+	// 组3: 时间戳（YYMMDD HH:MM:SS 格式）
+	// 组4: 线程ID
+	// 组5: 命令类型(Query, Connect等)
+	// 组6: 命令参数（SQL语句的第一行）
 	logLinePattern *regexp.Regexp
 	// 存储非标准格式的日志行
 	nonStandardLines []string
@@ -36,7 +68,8 @@ type GeneralLogFileParser struct {
 
 // NewGeneralLogFileParser 创建并初始化一个新的MySQL general log解析器
 func NewGeneralLogFileParser() *GeneralLogFileParser {
-	pattern := `^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d+Z)\s+(\d+)\s+(\w+)\s+(.*)$`
+	pattern := `^(?:(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?)|(\d{6}\s+\d{1,2}:\d{2}:\d{2})|\s)` +
+		`\s*(\d+)\s+(` + strings.Join(generalLogCommands, "|") + `)(?:[ \t]+(.*))?$`
 
 	return &GeneralLogFileParser{
 		logLinePattern:   regexp.MustCompile(pattern),
@@ -52,32 +85,47 @@ func (p *GeneralLogFileParser) GetNonStandardLines() []string {
 // Parse 解析MySQL general log文件
 // 参数 path 是日志文件的路径
 // 返回值: 提取的SQL语句字符串和可能的错误
-// 注意：不支持目录，请使用 analyzer 的 AnalyzeInput 方法处理目录。
+// 注意：不支持目录，请使用 analyzer 的 AnalyzeDirectory 方法处理目录。
 func (p *GeneralLogFileParser) Parse(path string) (string, error) {
-	if path == "" {
-		return "", fmt.Errorf("文件路径不能为空")
-	}
-
-	// 检查文件是否存在
-	fileInfo, err := os.Stat(path)
+	segments, err := p.ParseSegments(path)
 	if err != nil {
-		return "", fmt.Errorf("文件 %s 不存在: %w", path, err)
+		return "", err
 	}
+	sql, _ := JoinSegments(segments)
+	return sql, nil
+}
+
+// ParseSegments 解析MySQL general log文件，每条 Query 记录对应一个片段
+// 参数 path 是日志文件的路径
+// 返回值: SQL 片段列表（含时间、线程ID）和可能的错误
+func (p *GeneralLogFileParser) ParseSegments(path string) ([]Segment, error) {
+	return collectSegments(func(fn func(Segment) error) error {
+		return p.StreamSegments(path, fn)
+	})
+}
 
-	// 不支持目录
-	if fileInfo.IsDir() {
-		return "", fmt.Errorf("不支持目录，请使用 analyzer 的 AnalyzeInput 方法")
+// StreamSegments 逐条解析MySQL general log文件，每得到一个片段调用一次 fn
+// 参数:
+//   - path: 日志文件的路径
+//   - fn: 片段回调，返回错误时停止解析
+//
+// 返回:
+//   - error: 文件错误、读取错误或 fn 返回的错误
+//
+// 注意: 预处理语句在 Prepare 时即输出，其元数据中的执行次数随后续 Execute 记录增长。
+func (p *GeneralLogFileParser) StreamSegments(path string, fn func(Segment) error) error {
+	if err := checkFile(path); err != nil {
+		return err
 	}
 
-	// 检查文件扩展名
+	// 检查文件扩展名，压缩文件与归档成员按解压后的文件名判断
 	if !isLogFile(path) {
-		return "", fmt.Errorf("不支持的文件类型: %s，日志文件通常为 .log 扩展名", filepath.Ext(path))
+		return fmt.Errorf("不支持的文件类型: %s，日志文件通常为 .log 扩展名", InputExt(path))
 	}
 
-	// 读取文件内容
-	file, err := os.Open(path) //nolint:gosec
+	file, err := openText(path, "")
 	if err != nil {
-		return "", fmt.Errorf("打开文件 %s 失败: %w", path, err)
+		return err
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
@@ -85,71 +133,258 @@ func (p *GeneralLogFileParser) Parse(path string) (string, error) {
 		}
 	}()
 
-	return p.parseGeneralLog(file)
+	return p.parseGeneralLog(file, fn)
+}
+
+// ReadSegments 从已打开的输入流中逐条解析 general log，每得到一个片段调用一次 fn
+// 用于归档成员等没有独立文件路径的输入，调用方负责打开与关闭 reader。
+func (p *GeneralLogFileParser) ReadSegments(reader io.Reader, fn func(Segment) error) error {
+	return p.parseGeneralLog(reader, fn)
+}
+
+// generalLogEntry general log 中的一条记录
+type generalLogEntry struct {
+	header  string            // 头部行原文
+	line    int               // 头部行的行号
+	command string            // 命令类型
+	meta    map[string]string // 时间、线程ID
+	body    []string          // 命令参数，第一行来自头部行，其余为后续行
 }
 
 // parseGeneralLog 从io.Reader中解析general log内容
-func (p *GeneralLogFileParser) parseGeneralLog(reader io.Reader) (string, error) {
-	var sqlContent strings.Builder
-	scanner := bufio.NewScanner(reader)
+func (p *GeneralLogFileParser) parseGeneralLog(reader io.Reader, fn func(Segment) error) error {
+	var (
+		state    = newGeneralLogState(fn)
+		entry    *generalLogEntry
+		lastTime string
+		lineNo   int
+	)
 
+	scanner := newLineScanner(reader)
 	for scanner.Scan() {
-		line := scanner.Text()
-		sql := p.parseLogLine(line)
-		if sql != "" {
-			sqlContent.WriteString(sql)
-			// 添加分号和换行符，确保多条SQL语句正确分隔
-			if !strings.HasSuffix(sql, ";") {
-				sqlContent.WriteString(";")
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+
+		if next := p.parseLogLine(line, lineNo); next != nil {
+			if err := p.flushEntry(state, entry); err != nil {
+				return err
+			}
+			// 同一秒内的后续记录省略时间，沿用上一条记录的时间
+			if t, ok := next.meta[GeneralMetaTime]; ok {
+				lastTime = t
+			} else if lastTime != "" {
+				next.meta[GeneralMetaTime] = lastTime
+			}
+			entry = next
+			continue
+		}
+
+		if entry == nil {
+			// 第一个头部之前的内容（如服务器启动信息）
+			if strings.TrimSpace(line) != "" {
+				p.nonStandardLines = append(p.nonStandardLines, strings.TrimSpace(line))
 			}
-			sqlContent.WriteString("\n")
+			continue
 		}
+		// 多行 SQL 的后续行
+		entry.body = append(entry.body, line)
 	}
 
 	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("读取日志内容时出错: %w", err)
+		return fmt.Errorf("读取日志内容时出错: %w", err)
 	}
-
-	return sqlContent.String(), nil
+	return p.flushEntry(state, entry)
 }
 
-// parseLogLine 解析单行日志
+// parseLogLine 解析头部行
 // 返回:
-//   - 解析出的SQL语句
-//   - 错误信息
-//     注意：非标准格式的日志行会被记录到 nonStandardLines 中
-func (p *GeneralLogFileParser) parseLogLine(line string) string {
-	// 跳过空行
-	line = strings.TrimSpace(line)
-	if line == "" {
-		return ""
-	}
-
+//   - 头部行对应的新记录，不是头部行时返回 nil
+func (p *GeneralLogFileParser) parseLogLine(line string, lineNo int) *generalLogEntry {
 	// 匹配日志行格式
 	matches := p.logLinePattern.FindStringSubmatch(line)
 	if len(matches) < MinLogMatches {
-		// 记录非标准格式的日志行
-		p.nonStandardLines = append(p.nonStandardLines, line)
-		return ""
+		return nil
 	}
 
-	// 获取命令类型
-	commandType := matches[3]
+	entry := &generalLogEntry{
+		header:  strings.TrimSpace(line),
+		line:    lineNo,
+		command: matches[4],
+		meta:    map[string]string{GeneralMetaThreadID: matches[3]},
+		body:    []string{matches[5]},
+	}
+	switch {
+	case matches[1] != "":
+		entry.meta[GeneralMetaTime] = matches[1]
+	case matches[2] != "":
+		entry.meta[GeneralMetaTime] = strings.Join(strings.Fields(matches[2]), " ")
+	}
+	return entry
+}
+
+// flushEntry 处理一条记录，产生的片段交给解析状态中的回调
+// 注意：Query、Prepare、Execute 以外的记录会被记录到 nonStandardLines 中，
+// 其中 Connect、Init DB、Quit 同时用于维护连接的会话状态。
+func (p *GeneralLogFileParser) flushEntry(state *generalLogState, entry *generalLogEntry) error {
+	if entry == nil {
+		return nil
+	}
 
-	// 只处理Query类型的日志
-	if commandType != "Query" {
-		// 记录非Query类型的日志行
-		p.nonStandardLines = append(p.nonStandardLines, fmt.Sprintf("[Non-Query] %s", line))
-		return ""
+	// 跳过开头的空行，使片段行号指向 SQL 的第一行
+	first := 0
+	for first < len(entry.body) && strings.TrimSpace(entry.body[first]) == "" {
+		first++
 	}
+	arg := strings.TrimSpace(strings.Join(entry.body[first:], "\n"))
+	thread := entry.meta[GeneralMetaThreadID]
 
-	// 提取并清理SQL语句
-	sql := strings.TrimSpace(matches[4])
-	if sql == "" || isIgnoredSQL(sql) {
-		return ""
+	// 只处理Query类型的日志和预处理语句
+	switch entry.command {
+	case "Query", "Prepare", "Execute":
+	default:
+		state.command(entry.command, thread, arg)
+		p.nonStandardLines = append(p.nonStandardLines, fmt.Sprintf("[Non-Query] %s", entry.header))
+		return nil
 	}
 
-	return sql
+	// 提取并清理SQL语句，USE、SET、事务控制语句只更新会话状态
+	conn := state.connection(thread)
+	if arg == "" || entry.command == "Query" && conn.apply(arg) || isIgnoredSQL(arg) {
+		return nil
+	}
+	conn.statement(arg, entry.meta)
+
+	seg := Segment{SQL: arg, Line: entry.line + first, Meta: entry.meta}
+	switch entry.command {
+	case "Prepare":
+		return state.prepare(conn, seg)
+	case "Execute":
+		return state.execute(conn, seg)
+	}
+	seg.Meta[GeneralMetaCommand] = entry.command
+	return state.emit(seg)
+}
+
+// preparedStmt 通过 Prepare 命令创建的预处理语句
+type preparedStmt struct {
+	meta       map[string]string // 已输出片段的元数据，执行次数写入其中
+	pattern    *regexp.Regexp    // 匹配 Execute 命令中已代入参数的语句
+	executions int
+}
+
+// executed 记录一次执行，并更新已输出片段元数据中的执行次数
+func (ps *preparedStmt) executed() {
+	ps.executions++
+	ps.meta[GeneralMetaExecutions] = strconv.Itoa(ps.executions)
+}
+
+// generalLogState general log 解析过程中的状态
+type generalLogState struct {
+	emit        func(Segment) error      // 片段回调
+	prepared    map[string]*preparedStmt // 按规范化文本索引的预处理语句
+	connections map[string]*session      // 线程ID -> 连接的会话状态
+}
+
+// newGeneralLogState 创建解析状态
+func newGeneralLogState(emit func(Segment) error) *generalLogState {
+	return &generalLogState{
+		emit:        emit,
+		prepared:    map[string]*preparedStmt{},
+		connections: map[string]*session{},
+	}
+}
+
+// connection 返回线程对应的会话状态，不存在时创建
+// 日志开启前已建立的连接没有 Connect 记录，按默认会话状态处理。
+func (s *generalLogState) connection(thread string) *session {
+	conn, ok := s.connections[thread]
+	if !ok {
+		conn = newSession(thread)
+		s.connections[thread] = conn
+	}
+	return conn
+}
+
+// disconnect 清除线程的会话状态，线程ID被新连接复用时从默认状态开始
+func (s *generalLogState) disconnect(thread string) {
+	delete(s.connections, thread)
+}
+
+// command 根据 Connect、Init DB、Close stmt、Quit 命令更新连接的会话状态
+func (s *generalLogState) command(command, thread, arg string) {
+	switch command {
+	case "Connect":
+		s.disconnect(thread)
+		s.connection(thread).connect(arg)
+	case "Init DB":
+		s.connection(thread).schema = arg
+	case "Close stmt":
+		s.connection(thread).closeStmt()
+	case "Quit":
+		s.disconnect(thread)
+	}
+}
+
+// prepare 记录 Prepare 命令，相同文本的预处理语句只输出一次
+func (s *generalLogState) prepare(conn *session, seg Segment) error {
+	ps, err := s.lookup(seg)
+	if err != nil {
+		return err
+	}
+	conn.use(ps)
+	return nil
+}
+
+// execute 将 Execute 命令计入同一连接中匹配的预处理语句
+// 找不到匹配的 Prepare（如日志开启前已准备）时，已代入参数的语句作为普通片段输出，不记录为预处理语句：
+// 参数值各不相同的语句由分析器按语句指纹合并，解析器不为其保留状态。
+func (s *generalLogState) execute(conn *session, seg Segment) error {
+	key := normalizeSQL(seg.SQL)
+	// 从最近使用的语句开始匹配
+	for i := len(conn.prepared) - 1; i >= 0; i-- {
+		if ps := conn.prepared[i]; ps.pattern.MatchString(key) {
+			ps.executed()
+			conn.use(ps)
+			return nil
+		}
+	}
+	seg.Meta[GeneralMetaCommand] = "Execute"
+	return s.emit(seg)
+}
+
+// lookup 返回与片段文本相同的预处理语句，不存在时创建并输出该片段
+func (s *generalLogState) lookup(seg Segment) (*preparedStmt, error) {
+	key := normalizeSQL(seg.SQL)
+	if ps, ok := s.prepared[key]; ok {
+		return ps, nil
+	}
+
+	seg.Meta[GeneralMetaCommand] = "Prepare"
+	seg.Meta[GeneralMetaExecutions] = "0"
+	ps := &preparedStmt{meta: seg.Meta, pattern: executePattern(key)}
+	s.prepared[key] = ps
+	return ps, s.emit(seg)
+}
+
+// normalizeSQL 将连续空白压缩为一个空格，用于比较语句文本
+func normalizeSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// executePattern 根据预处理语句生成匹配 Execute 语句的正则表达式
+// 每个 ? 占位符匹配任意非空的参数值，其余部分按原文匹配。
+func executePattern(stmt string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString(`(?s)^`)
+	last := 0
+	for _, off := range sqlparser.Placeholders(stmt) {
+		sb.WriteString(regexp.QuoteMeta(stmt[last:off]))
+		sb.WriteString(`.+?`)
+		last = off + 1
+	}
+	sb.WriteString(regexp.QuoteMeta(stmt[last:]))
+	sb.WriteString(`$`)
+	return regexp.MustCompile(sb.String())
 }
 
 // isLogFile 检查文件是否为日志文件
@@ -161,10 +396,10 @@ func (p *GeneralLogFileParser) parseLogLine(line string) string {
 //
 // 说明:
 //
-//	通过文件扩展名判断，仅支持 .log 文件
+//	通过文件扩展名判断，仅支持 .log 文件；压缩文件与轮转日志按 InputExt 判断，
+//	如 general.log.1.gz
 func isLogFile(path string) bool {
-	ext := strings.ToLower(filepath.Ext(path))
-	return ext == ".log"
+	return InputExt(path) == ".log"
 }
 
 // isIgnoredSQL 检查是否是需要忽略的SQL语句
@@ -92,6 +92,158 @@ func TestLogParser_Parse(t *testing.T) {
 	}
 }
 
+func TestLogParser_ParseSegments(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantSQL   []string
+		wantLines []int
+		wantTimes []string
+	}{
+		{
+			name: "多行语句拼接到上一条 Query",
+			content: "2023-12-23T08:00:01.234567Z     1 Query     SELECT id,\n" +
+				"       name\n" +
+				"FROM users\n" +
+				"WHERE id = 1\n" +
+				"2023-12-23T08:00:02.234567Z     1 Quit\n",
+			wantSQL:   []string{"SELECT id,\n       name\nFROM users\nWHERE id = 1"},
+			wantLines: []int{1},
+			wantTimes: []string{"2023-12-23T08:00:01.234567Z"},
+		},
+		{
+			name: "MySQL 5.6 头部格式与制表符分隔",
+			content: "/usr/sbin/mysqld, Version: 5.6.51-log (MySQL Community Server (GPL)). started with:\n" +
+				"Tcp port: 3306  Unix socket: /var/lib/mysql/mysql.sock\n" +
+				"Time                 Id Command    Argument\n" +
+				"231223  8:00:01\t    1 Connect\troot@localhost on shop\n" +
+				"\t\t    1 Query\tSELECT * FROM orders\n" +
+				"231223 12:30:45\t    2 Query\tUPDATE orders\n" +
+				"SET status = 'done'\n" +
+				"\t\t    2 Query\tDELETE FROM carts\n",
+			wantSQL:   []string{"SELECT * FROM orders", "UPDATE orders\nSET status = 'done'", "DELETE FROM carts"},
+			wantLines: []int{5, 6, 8},
+			wantTimes: []string{"231223 8:00:01", "231223 12:30:45", "231223 12:30:45"},
+		},
+		{
+			name:      "SQL 从头部的下一行开始",
+			content:   "2023-12-23T08:00:01Z\t    7 Query\t\nINSERT INTO t VALUES (1)\n",
+			wantSQL:   []string{"INSERT INTO t VALUES (1)"},
+			wantLines: []int{2},
+			wantTimes: []string{"2023-12-23T08:00:01Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir() + string(os.PathSeparator) + "general.log"
+			requireWriteFile(t, path, tt.content)
+
+			p := NewGeneralLogFileParser()
+			segments, err := p.ParseSegments(path)
+			if err != nil {
+				t.Fatalf("ParseSegments() error = %v", err)
+			}
+			assertEqual(t, len(tt.wantSQL), len(segments))
+			for i, seg := range segments {
+				assertEqual(t, tt.wantSQL[i], seg.SQL)
+				assertEqual(t, tt.wantLines[i], seg.Line)
+				assertEqual(t, tt.wantTimes[i], seg.Meta[GeneralMetaTime])
+			}
+
+			// 后续行不应被记录为非标准格式的日志行
+			for _, line := range p.GetNonStandardLines() {
+				if strings.HasPrefix(line, "FROM") || strings.HasPrefix(line, "SET") {
+					t.Fatalf("continuation line recorded as non-standard: %q", line)
+				}
+			}
+		})
+	}
+}
+
+func TestLogParser_PreparedStatements(t *testing.T) {
+	p := NewGeneralLogFileParser()
+	segments, err := p.ParseSegments(testutils.MustGetTestDataPath("general_log_prepared.log"))
+	if err != nil {
+		t.Fatalf("ParseSegments() error = %v", err)
+	}
+
+	// 每个不同的预处理语句只输出一次；未匹配到 Prepare 的 Execute（包括 Close stmt 之后的执行）逐条按原文输出，
+	// 不记录执行次数，由分析器按语句指纹合并
+	want := []struct {
+		sql        string
+		line       int
+		command    string
+		executions string
+	}{
+		{sql: "SELECT IFNULL(nickname, ?) FROM users WHERE id = ?", line: 2, command: "Prepare", executions: "3"},
+		{sql: "INSERT INTO orders (user_id, amount)\nVALUES (?, ?)", line: 5, command: "Prepare", executions: "1"},
+		{sql: "SELECT GROUP_CONCAT(name) FROM tags WHERE user_id = 7", line: 12, command: "Execute"},
+		{sql: "SELECT GROUP_CONCAT(name) FROM tags WHERE user_id = 7", line: 13, command: "Execute"},
+		{sql: "SELECT IFNULL(nickname, 'guest') FROM users WHERE id = 8", line: 14, command: "Execute"},
+	}
+	assertEqual(t, len(want), len(segments))
+	for i, w := range want {
+		assertEqual(t, w.sql, segments[i].SQL)
+		assertEqual(t, w.line, segments[i].Line)
+		assertEqual(t, w.command, segments[i].Meta[GeneralMetaCommand])
+		assertEqual(t, w.executions, segments[i].Meta[GeneralMetaExecutions])
+	}
+
+	// Prepare/Execute 不再记录为非标准行
+	for _, line := range p.GetNonStandardLines() {
+		if strings.Contains(line, "Prepare") || strings.Contains(line, "Execute") {
+			t.Fatalf("prepared statement recorded as non-standard: %q", line)
+		}
+	}
+}
+
+func TestLogParser_Sessions(t *testing.T) {
+	content := "2023-12-23T08:00:00.000001Z\t   20 Connect\tapp@10.0.0.12 on shop using TCP/IP\n" +
+		"2023-12-23T08:00:00.000002Z\t   21 Connect\treport@localhost on  using Socket\n" +
+		"2023-12-23T08:00:01.000001Z\t   20 Query\tSET SESSION sql_mode = 'ANSI_QUOTES', autocommit = 0\n" +
+		"2023-12-23T08:00:01.000002Z\t   21 Query\tuse `stats`\n" +
+		"2023-12-23T08:00:01.000003Z\t   20 Query\tUPDATE stock SET qty = qty - 1 WHERE id = 1\n" +
+		"2023-12-23T08:00:01.000004Z\t   21 Query\tSELECT COUNT(*) FROM visits\n" +
+		"2023-12-23T08:00:01.000005Z\t   20 Query\tCOMMIT\n" +
+		"2023-12-23T08:00:01.000006Z\t   21 Query\tSTART TRANSACTION READ ONLY\n" +
+		"2023-12-23T08:00:01.000007Z\t   21 Init DB\tshop\n" +
+		"2023-12-23T08:00:01.000008Z\t   21 Query\tSELECT * FROM orders\n" +
+		"2023-12-23T08:00:01.000009Z\t   20 Query\tINSERT INTO audit VALUES (1)\n" +
+		"2023-12-23T08:00:02.000001Z\t   21 Quit\t\n" +
+		"2023-12-23T08:00:03.000001Z\t   21 Query\tSELECT 1 FROM dual\n"
+
+	path := t.TempDir() + string(os.PathSeparator) + "general.log"
+	requireWriteFile(t, path, content)
+
+	p := NewGeneralLogFileParser()
+	segments, err := p.ParseSegments(path)
+	if err != nil {
+		t.Fatalf("ParseSegments() error = %v", err)
+	}
+
+	// USE、SET、事务控制语句只更新会话状态，不输出
+	want := []map[string]string{
+		{GeneralMetaThreadID: "20", GeneralMetaUser: "app", GeneralMetaHost: "10.0.0.12", GeneralMetaSchema: "shop", GeneralMetaSQLMode: "ANSI_QUOTES", GeneralMetaAutocommit: "0", GeneralMetaTransaction: "20#1"},
+		{GeneralMetaThreadID: "21", GeneralMetaUser: "report", GeneralMetaHost: "localhost", GeneralMetaSchema: "stats", GeneralMetaAutocommit: "1"},
+		{GeneralMetaThreadID: "21", GeneralMetaUser: "report", GeneralMetaHost: "localhost", GeneralMetaSchema: "shop", GeneralMetaAutocommit: "1", GeneralMetaTransaction: "21#1"},
+		{GeneralMetaThreadID: "20", GeneralMetaUser: "app", GeneralMetaHost: "10.0.0.12", GeneralMetaSchema: "shop", GeneralMetaSQLMode: "ANSI_QUOTES", GeneralMetaAutocommit: "0", GeneralMetaTransaction: "20#2"},
+		// Quit 之后线程ID被复用，会话状态从默认值开始
+		{GeneralMetaThreadID: "21", GeneralMetaAutocommit: "1"},
+	}
+	assertEqual(t, len(want), len(segments))
+	for i, w := range want {
+		for key, value := range w {
+			assertEqual(t, value, segments[i].Meta[key])
+		}
+		for _, key := range []string{GeneralMetaUser, GeneralMetaHost, GeneralMetaSchema, GeneralMetaSQLMode, GeneralMetaTransaction} {
+			if _, ok := w[key]; !ok && segments[i].Meta[key] != "" {
+				t.Fatalf("segment %d: unexpected %s = %q", i, key, segments[i].Meta[key])
+			}
+		}
+	}
+}
+
 // assertEqual 断言两个值相等（泛型版本）
 // 参数:
 //   - t: 测试实例
@@ -134,3 +286,37 @@ func requireWriteFile(t *testing.T, path string, content string) {
 		t.Fatalf("write temp file failed: %v", err)
 	}
 }
+
+func TestLogParser_StreamSegments(t *testing.T) {
+	// 超过 bufio.Scanner 默认 64KB 限制的单行批量 INSERT
+	insert := "INSERT INTO t VALUES " + strings.Repeat("(1,'abc'),", 10000) + "(2,'def')"
+	content := "2023-12-23T08:00:01.000001Z\t    5 Prepare\tSELECT * FROM t WHERE id = ?\n" +
+		"2023-12-23T08:00:01.000002Z\t    5 Query\t" + insert + "\n" +
+		"2023-12-23T08:00:01.000003Z\t    5 Execute\tSELECT * FROM t WHERE id = 1\n" +
+		"2023-12-23T08:00:01.000004Z\t    5 Execute\tSELECT * FROM t WHERE id = 2\n"
+
+	path := t.TempDir() + string(os.PathSeparator) + "general.log"
+	requireWriteFile(t, path, content)
+
+	// 片段在读取到对应记录时立即产生，预处理语句的执行次数随后续 Execute 增长
+	var (
+		segments   []Segment
+		executions []string
+	)
+	err := NewGeneralLogFileParser().StreamSegments(path, func(seg Segment) error {
+		segments = append(segments, seg)
+		executions = append(executions, seg.Meta[GeneralMetaExecutions])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamSegments() error = %v", err)
+	}
+
+	assertEqual(t, 2, len(segments))
+	assertEqual(t, "0", executions[0])
+	assertEqual(t, true, segments[0].Deferred())
+	assertEqual(t, 2, segments[0].Executions())
+	assertEqual(t, insert, segments[1].SQL)
+	assertEqual(t, false, segments[1].Deferred())
+	assertEqual(t, 1, segments[1].Executions())
+}
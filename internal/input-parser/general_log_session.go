@@ -0,0 +1,154 @@
+package inputparser
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// general log 会话状态元数据的键名
+const (
+	GeneralMetaUser        = "user"        // Connect 记录中的用户
+	GeneralMetaHost        = "host"        // Connect 记录中的客户端主机
+	GeneralMetaSchema      = "schema"      // 语句执行时的当前数据库
+	GeneralMetaSQLMode     = "sql_mode"    // 会话设置的 sql_mode，未设置时不记录
+	GeneralMetaAutocommit  = "autocommit"  // 会话的 autocommit 设置，1 或 0
+	GeneralMetaTransaction = "transaction" // 语句所在的事务，格式为 "线程ID#序号"，不在事务中时不记录
+)
+
+var (
+	// sessionSetRe 匹配 SET 语句中的会话变量赋值
+	// 格式示例: SET SESSION sql_mode = 'ANSI_QUOTES', @@autocommit = 0
+	// 组1: 变量名
+	// 组2: 变量值
+	sessionSetRe = regexp.MustCompile(`(?i)(?:^|,)\s*(?:@@session\.|@@local\.|@@|session\s+|local\s+)?(sql_mode|autocommit)\s*(?:=|:=)\s*('[^']*'|"[^"]*"|[^,;\s]+)`)
+	// beginRe 匹配显式开启事务的语句
+	beginRe = regexp.MustCompile(`(?i)^(?:BEGIN(?:\s+WORK)?|START\s+TRANSACTION\b.*)\s*;?$`)
+	// endTxRe 匹配结束事务的语句（不含 ROLLBACK TO SAVEPOINT）
+	endTxRe = regexp.MustCompile(`(?i)^(?:COMMIT|ROLLBACK)(?:\s+WORK)?(?:\s+AND\s+(?:NO\s+)?CHAIN)?(?:\s+(?:NO\s+)?RELEASE)?\s*;?$`)
+	// savepointRe 匹配保存点相关语句
+	savepointRe = regexp.MustCompile(`(?i)^(?:SAVEPOINT|RELEASE\s+SAVEPOINT|ROLLBACK(?:\s+WORK)?\s+TO)\b`)
+	// implicitCommitRe 匹配会隐式提交当前事务的 DDL 语句
+	implicitCommitRe = regexp.MustCompile(`(?i)^(?:CREATE|ALTER|DROP|TRUNCATE|RENAME)\b`)
+)
+
+// session general log 中一个连接（线程）的会话状态
+type session struct {
+	thread     string
+	user       string
+	host       string
+	schema     string
+	sqlMode    string
+	autocommit bool
+	tx         int             // 当前事务序号，0 表示不在事务中
+	txCount    int             // 已开启的事务数量
+	prepared   []*preparedStmt // 连接中未关闭的预处理语句，按最近使用的顺序排列，最后一个为最近使用的
+}
+
+// newSession 创建默认状态的会话：autocommit 开启，不在事务中
+func newSession(thread string) *session {
+	return &session{thread: thread, autocommit: true}
+}
+
+// use 将预处理语句记为连接中最近使用的语句
+func (s *session) use(ps *preparedStmt) {
+	s.prepared = slices.DeleteFunc(s.prepared, func(existing *preparedStmt) bool { return existing == ps })
+	s.prepared = append(s.prepared, ps)
+}
+
+// closeStmt 根据 Close stmt 命令移除预处理语句
+// general log 中的 Close stmt 不记录语句文本，按 Prepare、Execute、Close 的常见顺序移除最近使用的语句。
+func (s *session) closeStmt() {
+	if n := len(s.prepared); n > 0 {
+		s.prepared[n-1] = nil
+		s.prepared = s.prepared[:n-1]
+	}
+}
+
+// connect 根据 Connect 命令的参数设置用户和初始数据库
+// 格式示例: app@10.0.0.12 on shop using TCP/IP
+func (s *session) connect(arg string) {
+	account, rest, ok := strings.Cut(arg, " on")
+	if !ok {
+		return
+	}
+	s.user, s.host, _ = strings.Cut(account, "@")
+	if fields := strings.Fields(rest); len(fields) > 0 && fields[0] != "using" {
+		s.schema = fields[0]
+	}
+}
+
+// apply 处理只影响会话状态的语句
+// 返回 true 表示语句为 USE、SET 或事务控制语句，已更新会话状态，不需要作为 SQL 分析。
+func (s *session) apply(sql string) bool {
+	upper := strings.ToUpper(sql)
+	switch {
+	case useStmtRe.MatchString(sql):
+		s.schema = useStmtRe.FindStringSubmatch(sql)[1]
+	case strings.HasPrefix(upper, "SET ") || strings.HasPrefix(upper, "SET\n"):
+		s.set(strings.TrimSpace(sql[len("SET"):]))
+	case beginRe.MatchString(sql):
+		s.begin()
+	case endTxRe.MatchString(sql):
+		s.tx = 0
+	case savepointRe.MatchString(sql):
+	default:
+		return false
+	}
+	return true
+}
+
+// set 处理 SET 语句中的 sql_mode 和 autocommit 赋值，GLOBAL 变量不影响会话
+func (s *session) set(assignments string) {
+	for _, m := range sessionSetRe.FindAllStringSubmatch(assignments, -1) {
+		value := strings.Trim(m[2], `'"`)
+		switch strings.ToLower(m[1]) {
+		case "sql_mode":
+			s.sqlMode = value
+		case "autocommit":
+			on := value == "1" || strings.EqualFold(value, "ON") || strings.EqualFold(value, "TRUE")
+			// 开启 autocommit 会提交当前事务
+			if on && !s.autocommit {
+				s.tx = 0
+			}
+			s.autocommit = on
+		}
+	}
+}
+
+// begin 开启新事务
+func (s *session) begin() {
+	s.txCount++
+	s.tx = s.txCount
+}
+
+// statement 记录一条需要分析的语句，并将会话状态写入元数据
+// autocommit 关闭时，不在事务中的语句隐式开启事务；DDL 语句隐式提交当前事务。
+func (s *session) statement(sql string, meta map[string]string) {
+	if implicitCommitRe.MatchString(sql) {
+		s.tx = 0
+	} else if !s.autocommit && s.tx == 0 {
+		s.begin()
+	}
+
+	if s.user != "" {
+		meta[GeneralMetaUser] = s.user
+	}
+	if s.host != "" {
+		meta[GeneralMetaHost] = s.host
+	}
+	if s.schema != "" {
+		meta[GeneralMetaSchema] = s.schema
+	}
+	if s.sqlMode != "" {
+		meta[GeneralMetaSQLMode] = s.sqlMode
+	}
+	meta[GeneralMetaAutocommit] = "1"
+	if !s.autocommit {
+		meta[GeneralMetaAutocommit] = "0"
+	}
+	if s.tx > 0 {
+		meta[GeneralMetaTransaction] = s.thread + "#" + strconv.Itoa(s.tx)
+	}
+}
@@ -1,14 +1,45 @@
 // Package inputparser 提供输入解析器接口和实现，支持从不同来源（文件、字符串、流）解析 SQL 内容。
 // 支持多种输入类型：
 //   - SQL 文件：直接读取 .sql 文件内容
+//   - mysqldump 文件：展开版本注释、去除 LOCK TABLES，并标记语句所在的导出分段
 //   - 日志文件：从 MySQL general log 中提取 SQL 语句
+//   - 慢查询日志：从 MySQL slow query log 中提取 SQL 语句及执行耗时等元数据
+//   - binlog：从 mysqlbinlog -v 的输出中提取基于语句的事件和行事件的伪 SQL，并保留事件位置与 GTID
+//   - MyBatis mapper：从 mapper XML 中提取语句，并将动态 SQL 展开为有代表性的语句
+//   - 语句摘要：performance_schema 语句摘要表的 CSV、JSON 导出，保留执行次数与累计耗时
+//   - 压缩与归档：.gz、.zst 压缩文件和 .tar、.zip 归档中的成员，边读边解压
 //   - 字符串：直接传入 SQL 字符串
 //   - 流输入：基于 io.Reader 的流式输入
 package inputparser
 
+import (
+	"bufio"
+	"io"
+)
+
+// maxLineBytes 按行读取日志时单行的最大长度
+// bufio.Scanner 默认只支持 64KB 的行，批量 INSERT 很容易超过该限制；
+// 缓冲区按需增长，只有遇到长行时才会占用对应的内存。
+const maxLineBytes = 1 << 30
+
 // InputParser 定义输入解析器接口
 type InputParser interface {
 	// Parse 从输入源解析内容并返回SQL语句字符串
 	// 参数 path 是文件路径或SQL字符串
 	Parse(path string) (string, error)
 }
+
+// StreamParser 可选接口：以流的形式打开输入
+// 实现该接口的解析器由 analyzer 逐条读取、分析语句，内存占用与输入大小无关。
+type StreamParser interface {
+	InputParser
+	// Open 检查并打开输入文件，调用方负责关闭
+	Open(path string) (io.ReadCloser, error)
+}
+
+// newLineScanner 创建按行读取的 Scanner，单行最长 maxLineBytes
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineBytes)
+	return scanner
+}
@@ -0,0 +1,512 @@
+package inputparser
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MyBatis mapper 片段元数据的键名
+const (
+	MapperMetaNamespace = "namespace" // <mapper namespace="...">
+	MapperMetaStatement = "statement" // 语句 id，如 <select id="findUser">
+	MapperMetaVariant   = "variant"   // 动态 SQL 展开方式，见 MapperVariantFull 等
+)
+
+// 动态 SQL 的展开方式
+// 除以下两种外，含多个 <when> 分支的语句还会按 "when-N" 选择第 N 个分支展开。
+const (
+	MapperVariantFull    = "full"    // 所有 <if> 成立，<choose> 选择第一个 <when>
+	MapperVariantMinimal = "minimal" // 所有 <if> 不成立，<choose> 选择 <otherwise>
+)
+
+// mapperStatements 提取 SQL 的语句元素
+var mapperStatements = map[string]bool{"select": true, "insert": true, "update": true, "delete": true}
+
+// whereOverrides <where> 去除的开头连接词，与 MyBatis WhereSqlNode 一致
+var whereOverrides = []string{"AND ", "OR ", "AND\n", "OR\n", "AND\r", "OR\r", "AND\t", "OR\t"}
+
+var (
+	// hashParamRe 匹配预编译参数 #{...}
+	hashParamRe = regexp.MustCompile(`#\{[^}]*\}`)
+	// dollarParamRe 匹配文本替换参数 ${...}
+	dollarParamRe = regexp.MustCompile(`\$\{([^}]*)\}`)
+	// identifierContextRe 匹配 ${...} 前表示其后为表名的关键字
+	identifierContextRe = regexp.MustCompile(`(?i)\b(FROM|JOIN|INTO|UPDATE|TABLE)\s+$`)
+	// nonWordRe 匹配标识符中不允许的字符
+	nonWordRe = regexp.MustCompile(`\W+`)
+)
+
+// MapperFileParser 解析 MyBatis mapper XML 文件
+// 提取 <select>/<insert>/<update>/<delete> 中的 SQL，并将动态 SQL 展开为有代表性的语句：
+//   - <if> 全部成立与全部不成立各展开一次，<choose> 的每个 <when> 分支各展开一次
+//   - <where>、<set>、<trim> 按 MyBatis 的规则添加前缀并去除多余的连接词和逗号
+//   - <foreach> 展开为一次迭代，<include> 替换为对应的 <sql> 片段
+//   - #{...} 替换为参数占位符 ?，${...} 在表名位置替换为参数名，其他位置替换为 ?
+//
+// 展开时保留原文中的换行，问题的行号对应回 mapper 文件；
+// 每个片段的元数据记录 namespace、语句 id 和展开方式。
+type MapperFileParser struct{}
+
+// NewMapperFileParser 创建并返回一个新的 MyBatis mapper 解析器
+func NewMapperFileParser() *MapperFileParser {
+	return &MapperFileParser{}
+}
+
+// Parse 解析 mapper 文件
+// 参数 path 是 mapper 文件的路径
+// 返回值: 展开后的SQL语句字符串和可能的错误
+func (p *MapperFileParser) Parse(path string) (string, error) {
+	segments, err := p.ParseSegments(path)
+	if err != nil {
+		return "", err
+	}
+	sql, _ := JoinSegments(segments)
+	return sql, nil
+}
+
+// ParseSegments 解析 mapper 文件，每条语句的每种展开方式对应一个片段
+// 参数 path 是 mapper 文件的路径
+// 返回值: SQL 片段列表（含元数据）和可能的错误
+func (p *MapperFileParser) ParseSegments(path string) ([]Segment, error) {
+	return collectSegments(func(fn func(Segment) error) error {
+		return p.StreamSegments(path, fn)
+	})
+}
+
+// StreamSegments 解析 mapper 文件，每得到一个片段调用一次 fn
+// 参数:
+//   - path: mapper 文件的路径
+//   - fn: 片段回调，返回错误时停止解析
+//
+// 返回:
+//   - error: 文件错误、XML 格式错误或 fn 返回的错误
+func (p *MapperFileParser) StreamSegments(path string, fn func(Segment) error) error {
+	if err := checkFile(path); err != nil {
+		return err
+	}
+
+	if ext := InputExt(path); ext != ".xml" {
+		return fmt.Errorf("不支持的文件类型: %s，MyBatis mapper 文件应为 .xml 扩展名", ext)
+	}
+
+	file, err := openText(path, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "关闭文件 %s 失败: %v\n", path, err)
+		}
+	}()
+
+	return p.ReadSegments(file, fn)
+}
+
+// ReadSegments 从已打开的输入流中解析 mapper，每得到一个片段调用一次 fn
+// 用于归档成员等没有独立文件路径的输入，调用方负责打开与关闭 reader。
+// mapper 文件通常较小，整个文件先解析为元素树，以便 <include> 引用定义在其后的 <sql> 片段。
+func (p *MapperFileParser) ReadSegments(reader io.Reader, fn func(Segment) error) error {
+	root, err := parseMapperTree(reader)
+	if err != nil {
+		return err
+	}
+
+	mapper := root.child("mapper")
+	if mapper == nil {
+		return fmt.Errorf("不是 MyBatis mapper 文件: 缺少 <mapper> 根元素")
+	}
+
+	fragments := make(map[string]*mapperNode)
+	for _, n := range mapper.children {
+		if n.name == "sql" {
+			fragments[n.attrs["id"]] = n
+		}
+	}
+
+	namespace := mapper.attrs["namespace"]
+	for _, stmt := range mapper.children {
+		if !mapperStatements[stmt.name] {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, variant := range mapperVariants(stmt) {
+			g := &mapperGenerator{variant: variant, fragments: fragments}
+			var sb strings.Builder
+			g.writeChildren(&sb, stmt)
+			text := replaceParams(sb.String())
+
+			sql := strings.TrimSpace(text)
+			key := strings.Join(strings.Fields(sql), " ")
+			if sql == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			leading := text[:strings.Index(text, sql)]
+			seg := Segment{
+				SQL:  sql,
+				Line: stmt.line + strings.Count(leading, "\n"),
+				Meta: map[string]string{
+					MapperMetaNamespace: namespace,
+					MapperMetaStatement: stmt.attrs["id"],
+					MapperMetaVariant:   variant.name,
+				},
+			}
+			if err := fn(seg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mapperNode mapper XML 中的元素或文本
+type mapperNode struct {
+	name     string            // 元素名，文本节点为空
+	attrs    map[string]string // 元素属性
+	text     string            // 文本节点的内容
+	line     int               // 元素内容（或文本）的起始行号
+	children []*mapperNode
+}
+
+// child 返回第一个指定名称的子元素
+func (n *mapperNode) child(name string) *mapperNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// parseMapperTree 将 mapper XML 解析为元素树
+// 标签、注释本身跨越的换行以纯换行文本节点保留，使展开后的 SQL 与原文行号一致。
+func parseMapperTree(reader io.Reader) (*mapperNode, error) {
+	decoder := xml.NewDecoder(reader)
+	root := &mapperNode{}
+	stack := []*mapperNode{root}
+
+	for {
+		line, _ := decoder.InputPos()
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return root, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 mapper XML 失败: %w", err)
+		}
+		after, _ := decoder.InputPos()
+		top := stack[len(stack)-1]
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &mapperNode{name: t.Name.Local, attrs: make(map[string]string), line: after}
+			for _, attr := range t.Attr {
+				n.attrs[attr.Name.Local] = attr.Value
+			}
+			top.children = append(top.children, n)
+			stack = append(stack, n)
+			n.addNewlines(after - line)
+
+		case xml.EndElement:
+			top.addNewlines(after - line)
+			stack = stack[:len(stack)-1]
+
+		case xml.CharData:
+			top.children = append(top.children, &mapperNode{text: string(t), line: line})
+
+		default:
+			// 注释、处理指令等不产生 SQL，只保留换行
+			top.addNewlines(after - line)
+		}
+	}
+}
+
+// addNewlines 追加只包含换行的文本节点
+func (n *mapperNode) addNewlines(count int) {
+	if count > 0 {
+		n.children = append(n.children, &mapperNode{text: strings.Repeat("\n", count)})
+	}
+}
+
+// newlines 返回节点及其子节点中的换行数
+func (n *mapperNode) newlines() int {
+	count := strings.Count(n.text, "\n")
+	for _, c := range n.children {
+		count += c.newlines()
+	}
+	return count
+}
+
+// mapperVariant 动态 SQL 的一种展开方式
+type mapperVariant struct {
+	name       string
+	conditions bool // <if> 是否成立
+	branch     int  // <choose> 选择的 <when> 序号，超出范围时选择 <otherwise>
+}
+
+// mapperVariants 返回语句需要展开的方式
+func mapperVariants(stmt *mapperNode) []mapperVariant {
+	variants := []mapperVariant{{name: MapperVariantFull, conditions: true}}
+	for i := 1; i < maxWhenBranches(stmt); i++ {
+		variants = append(variants, mapperVariant{name: "when-" + strconv.Itoa(i+1), conditions: true, branch: i})
+	}
+	return append(variants, mapperVariant{name: MapperVariantMinimal, branch: -1})
+}
+
+// maxWhenBranches 返回语句中 <choose> 元素最多的 <when> 分支数
+func maxWhenBranches(n *mapperNode) int {
+	count := 0
+	if n.name == "choose" {
+		for _, c := range n.children {
+			if c.name == "when" {
+				count++
+			}
+		}
+	}
+	for _, c := range n.children {
+		count = max(count, maxWhenBranches(c))
+	}
+	return count
+}
+
+// mapperGenerator 按一种展开方式生成语句的 SQL
+type mapperGenerator struct {
+	variant   mapperVariant
+	fragments map[string]*mapperNode
+	including int // <include> 嵌套深度，防止循环引用
+}
+
+// maxIncludeDepth <include> 的最大嵌套深度
+const maxIncludeDepth = 8
+
+// write 输出节点对应的 SQL
+func (g *mapperGenerator) write(sb *strings.Builder, n *mapperNode) {
+	switch n.name {
+	case "":
+		sb.WriteString(n.text)
+
+	case "if":
+		if g.variant.conditions {
+			g.writeChildren(sb, n)
+		} else {
+			skip(sb, n)
+		}
+
+	case "choose":
+		g.writeChoose(sb, n)
+
+	case "where":
+		sb.WriteString(trimSQL(g.render(n), "WHERE", "", whereOverrides, nil))
+
+	case "set":
+		sb.WriteString(trimSQL(g.render(n), "SET", "", nil, []string{","}))
+
+	case "trim":
+		sb.WriteString(trimSQL(g.render(n), n.attrs["prefix"], n.attrs["suffix"],
+			splitOverrides(n.attrs["prefixOverrides"]), splitOverrides(n.attrs["suffixOverrides"])))
+
+	case "foreach":
+		// 只展开一次迭代，保持行号不变
+		sb.WriteString(n.attrs["open"])
+		g.writeChildren(sb, n)
+		sb.WriteString(n.attrs["close"])
+
+	case "include":
+		g.writeInclude(sb, n)
+
+	case "bind", "selectKey":
+		skip(sb, n)
+
+	default:
+		g.writeChildren(sb, n)
+	}
+}
+
+// writeChildren 依次输出子节点
+func (g *mapperGenerator) writeChildren(sb *strings.Builder, n *mapperNode) {
+	for _, c := range n.children {
+		g.write(sb, c)
+	}
+}
+
+// render 返回子节点输出的 SQL
+func (g *mapperGenerator) render(n *mapperNode) string {
+	var sb strings.Builder
+	g.writeChildren(&sb, n)
+	return sb.String()
+}
+
+// writeChoose 按展开方式选择 <choose> 的一个分支，其余分支只保留换行
+func (g *mapperGenerator) writeChoose(sb *strings.Builder, n *mapperNode) {
+	var whens []*mapperNode
+	otherwise := n.child("otherwise")
+	for _, c := range n.children {
+		if c.name == "when" {
+			whens = append(whens, c)
+		}
+	}
+
+	chosen := otherwise
+	if b := g.variant.branch; b >= 0 && b < len(whens) {
+		chosen = whens[b]
+	} else if b >= 0 && otherwise == nil && len(whens) > 0 {
+		chosen = whens[len(whens)-1]
+	}
+
+	for _, c := range n.children {
+		switch {
+		case c == chosen:
+			g.writeChildren(sb, c)
+		case c.name == "":
+			sb.WriteString(c.text)
+		default:
+			skip(sb, c)
+		}
+	}
+}
+
+// writeInclude 将 <include> 替换为 <sql> 片段，片段中的换行替换为空格，保持行号不变
+// refid 可以带 namespace 前缀；找不到片段时不输出内容。
+func (g *mapperGenerator) writeInclude(sb *strings.Builder, n *mapperNode) {
+	refid := n.attrs["refid"]
+	fragment, ok := g.fragments[refid]
+	if !ok {
+		if i := strings.LastIndex(refid, "."); i >= 0 {
+			fragment, ok = g.fragments[refid[i+1:]]
+		}
+	}
+	if ok && g.including < maxIncludeDepth {
+		g.including++
+		text := g.render(fragment)
+		g.including--
+		sb.WriteString(" " + strings.Join(strings.Fields(text), " ") + " ")
+	}
+	skip(sb, n)
+}
+
+// skip 跳过节点，只输出其中的换行
+func skip(sb *strings.Builder, n *mapperNode) {
+	sb.WriteString(strings.Repeat("\n", n.newlines()))
+}
+
+// trimSQL 按 <trim> 的规则处理内容：非空时添加前缀和后缀，并去除开头、结尾多余的内容
+// 内容前后的空白（包括换行）原样保留；内容为空时只保留换行。
+func trimSQL(content, prefix, suffix string, prefixOverrides, suffixOverrides []string) string {
+	body := strings.TrimSpace(content)
+	if body == "" {
+		return strings.Repeat("\n", strings.Count(content, "\n"))
+	}
+	start := strings.Index(content, body)
+	leading, trailing := content[:start], content[start+len(body):]
+
+	upper := strings.ToUpper(body)
+	for _, o := range prefixOverrides {
+		if strings.HasPrefix(upper, strings.ToUpper(o)) {
+			body = body[len(o):]
+			break
+		}
+	}
+	upper = strings.ToUpper(body)
+	for _, o := range suffixOverrides {
+		if strings.HasSuffix(upper, strings.ToUpper(o)) {
+			body = body[:len(body)-len(o)]
+			break
+		}
+	}
+
+	var sb strings.Builder
+	if prefix != "" {
+		sb.WriteString(" " + prefix + " ")
+	}
+	sb.WriteString(leading)
+	sb.WriteString(body)
+	if suffix != "" {
+		sb.WriteString(" " + suffix + " ")
+	}
+	sb.WriteString(trailing)
+	return sb.String()
+}
+
+// splitOverrides 拆分 prefixOverrides/suffixOverrides 属性，多个值以 | 分隔
+func splitOverrides(attr string) []string {
+	if attr == "" {
+		return nil
+	}
+	return strings.Split(attr, "|")
+}
+
+// replaceParams 将 #{...} 替换为 ?，${...} 在表名位置替换为参数名，其他位置替换为 ?
+func replaceParams(sql string) string {
+	sql = hashParamRe.ReplaceAllString(sql, "?")
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range dollarParamRe.FindAllStringSubmatchIndex(sql, -1) {
+		sb.WriteString(sql[last:m[0]])
+		if identifierContextRe.MatchString(sql[:m[0]]) {
+			sb.WriteString(paramIdentifier(sql[m[2]:m[3]]))
+		} else {
+			sb.WriteString("?")
+		}
+		last = m[1]
+	}
+	sb.WriteString(sql[last:])
+	return sb.String()
+}
+
+// paramIdentifier 将 ${...} 中的参数表达式转为标识符，如 ${tableName} 转为 tableName
+func paramIdentifier(expr string) string {
+	name := strings.TrimSpace(strings.SplitN(expr, ",", 2)[0])
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Trim(nonWordRe.ReplaceAllString(name, "_"), "_")
+	if name == "" {
+		return "param"
+	}
+	return name
+}
+
+// IsMapper 判断内容是否为 MyBatis mapper XML
+// 第一个元素为 <mapper> 即视为 mapper 文件。
+// 参数:
+//   - reader: XML 内容，只需包含文件开头
+//
+// 返回值:
+//   - bool: true 表示 mapper 文件
+func IsMapper(reader io.Reader) bool {
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "mapper"
+		}
+	}
+}
+
+// IsMapperFile 判断文件是否为 MyBatis mapper XML
+// 参数:
+//   - path: 文件路径
+//
+// 返回值:
+//   - bool: true 表示 mapper 文件，文件无法读取时返回 false
+func IsMapperFile(path string) bool {
+	file, err := OpenFile(path)
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return IsMapper(file)
+}
@@ -0,0 +1,78 @@
+package inputparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/ybMigration/internal/testutils"
+)
+
+// TestMapperFileParser_ParseSegments 测试 mapper 语句的提取与动态 SQL 展开
+func TestMapperFileParser_ParseSegments(t *testing.T) {
+	segments, err := NewMapperFileParser().ParseSegments(testutils.MustGetTestDataPath("UserMapper.xml"))
+	require.NoError(t, err)
+
+	byVariant := make(map[string]Segment)
+	for _, seg := range segments {
+		assert.Equal(t, "com.example.mapper.UserMapper", seg.Meta[MapperMetaNamespace])
+		byVariant[seg.Meta[MapperMetaStatement]+"/"+seg.Meta[MapperMetaVariant]] = seg
+	}
+	// 不含动态元素的 upsertUser 两种展开相同，只保留一个
+	assert.Len(t, segments, 8)
+
+	full := byVariant["findUsers/full"]
+	assert.Equal(t, 9, full.Line, "行号指向 mapper 中 SQL 的起始行")
+	sql := strings.Join(strings.Fields(full.SQL), " ")
+	assert.Equal(t, "SELECT id, name, IFNULL(nickname, name) AS nickname FROM users WHERE name LIKE CONCAT('%', ?, '%') "+
+		"AND id IN ( ? ) ORDER BY ? LIMIT ?, ?", sql)
+	assert.Equal(t, 23-9, strings.Count(full.SQL, "\n"), "展开后保留原文中的换行")
+
+	minimal := byVariant["findUsers/minimal"]
+	assert.Equal(t, "SELECT id, name, IFNULL(nickname, name) AS nickname FROM users ORDER BY ? LIMIT ?, ?",
+		strings.Join(strings.Fields(minimal.SQL), " "))
+	assert.Equal(t, strings.Count(full.SQL, "\n"), strings.Count(minimal.SQL, "\n"))
+
+	// <choose> 每个分支各展开一次，${} 在表名位置替换为参数名
+	assert.Contains(t, byVariant["findByStatus/full"].SQL, "FROM tableName")
+	assert.Contains(t, byVariant["findByStatus/full"].SQL, "WHERE status = 1")
+	assert.Contains(t, byVariant["findByStatus/when-2"].SQL, "WHERE status = 2 AND deleted_at IS NULL")
+	assert.Contains(t, byVariant["findByStatus/minimal"].SQL, "WHERE status <> 0")
+
+	// <set> 去除结尾多余的逗号
+	assert.Equal(t, "UPDATE users SET name = ?, nickname = ?, updated_at = NOW() WHERE id = ?",
+		strings.Join(strings.Fields(byVariant["updateUser/full"].SQL), " "))
+}
+
+// TestMapperFileParser_Trim 测试 <trim> 与未找到的 <include>
+func TestMapperFileParser_Trim(t *testing.T) {
+	const mapper = `<mapper namespace="m">
+<insert id="add">
+INSERT INTO t
+<trim prefix="(" suffix=")" suffixOverrides=","><if test="a">a,</if><if test="b">b,</if></trim>
+VALUES (#{a}, #{b})<include refid="other.missing"/>
+</insert>
+</mapper>`
+	var segments []Segment
+	err := NewMapperFileParser().ReadSegments(strings.NewReader(mapper), func(seg Segment) error {
+		segments = append(segments, seg)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+	assert.Equal(t, 3, segments[0].Line)
+	assert.Equal(t, "INSERT INTO t ( a,b ) VALUES (?, ?)", strings.Join(strings.Fields(segments[0].SQL), " "))
+	assert.Equal(t, "INSERT INTO t VALUES (?, ?)", strings.Join(strings.Fields(segments[1].SQL), " "))
+}
+
+// TestIsMapper 测试 mapper 文件识别
+func TestIsMapper(t *testing.T) {
+	assert.True(t, IsMapperFile(testutils.MustGetTestDataPath("UserMapper.xml")))
+	assert.False(t, IsMapper(strings.NewReader(`<?xml version="1.0"?><project></project>`)))
+	assert.False(t, IsMapper(strings.NewReader("not xml")))
+
+	err := NewMapperFileParser().ReadSegments(strings.NewReader("<project/>"), func(Segment) error { return nil })
+	require.Error(t, err)
+}
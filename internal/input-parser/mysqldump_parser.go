@@ -0,0 +1,250 @@
+package inputparser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	sqlparser "github.com/example/ybMigration/internal/sql-parser"
+)
+
+// mysqldump 语句元数据的键名
+const (
+	DumpMetaSection  = "section"  // 语句所在的导出分段
+	DumpMetaTable    = "table"    // 分段对应的表或视图
+	DumpMetaDatabase = "database" // -- Current Database 指定的数据库
+)
+
+// mysqldump 导出分段
+const (
+	DumpSectionHeader   = "header"   // 文件开头的会话设置
+	DumpSectionDatabase = "database" // CREATE DATABASE、USE
+	DumpSectionSchema   = "schema"   // 表结构
+	DumpSectionData     = "data"     // 表数据
+	DumpSectionView     = "view"     // 视图结构
+	DumpSectionTrigger  = "trigger"  // 触发器
+	DumpSectionRoutine  = "routine"  // 存储过程与函数
+	DumpSectionEvent    = "event"    // 事件
+)
+
+// dumpSniffLines 判断是否为 mysqldump 输出时读取的最大行数
+const dumpSniffLines = 8
+
+var (
+	// dumpHeaderRe 匹配 mysqldump 的分段标记注释
+	// 格式示例:
+	//   -- Table structure for table `users`
+	//   -- Dumping data for table `users`
+	//   -- Temporary view structure for view `v_users`
+	//   -- Final view structure for view `v_users`
+	//   -- Dumping routines for database 'shop'
+	//   -- Dumping events for database 'shop'
+	//   -- Current Database: `shop`
+	dumpHeaderRe = regexp.MustCompile("^--\\s+(Table structure for table|Dumping data for table|Temporary (?:view|table) structure for view|Final view structure for view|Dumping routines for database|Dumping events for database|Current Database:)\\s+[`']?([^`']*)[`']?")
+	// dumpLockRe 匹配 LOCK TABLES 和 UNLOCK TABLES
+	dumpLockRe = regexp.MustCompile(`(?i)^(?:LOCK\s+TABLES?\b|UNLOCK\s+TABLES?\b)`)
+	// dumpObjectRe 匹配展开版本注释后的触发器、存储过程、函数与事件定义
+	dumpObjectRe = regexp.MustCompile(`(?i)^CREATE\s+(?:DEFINER\s*=\s*\S+\s+)?(?:SQL\s+SECURITY\s+\w+\s+)?(TRIGGER|PROCEDURE|FUNCTION|EVENT)\b`)
+)
+
+// dumpHeaderSections 分段标记与导出分段的对应关系
+var dumpHeaderSections = map[string]string{
+	"Table structure for table":          DumpSectionSchema,
+	"Dumping data for table":             DumpSectionData,
+	"Temporary view structure for view":  DumpSectionView,
+	"Temporary table structure for view": DumpSectionView,
+	"Final view structure for view":      DumpSectionView,
+	"Dumping routines for database":      DumpSectionRoutine,
+	"Dumping events for database":        DumpSectionEvent,
+	"Current Database:":                  DumpSectionDatabase,
+}
+
+// MysqldumpFileParser 解析 mysqldump 导出的 SQL 文件
+// 在 SQLFileParser 的基础上做以下预处理：
+//   - /*!NNNNN ... */ 版本注释按配置的服务器版本展开或去除，如 /*!50003 CREATE*/ /*!50017 DEFINER=...*/
+//   - 去除 LOCK TABLES / UNLOCK TABLES
+//   - 按 DELIMITER 命令切分触发器、存储过程的定义体
+//   - 根据 "-- Table structure for table" 等标记记录每条语句所在的导出分段
+type MysqldumpFileParser struct {
+	files   *SQLFileParser
+	version int // 服务器版本，格式与版本注释相同
+}
+
+// NewMysqldumpFileParser 创建 mysqldump 文件解析器
+// 参数:
+//   - version: 源 MySQL 服务器版本，格式与版本注释相同（如 80035 表示 8.0.35）
+func NewMysqldumpFileParser(version int) *MysqldumpFileParser {
+	return &MysqldumpFileParser{files: NewSQLFileParser(), version: version}
+}
+
+// Parse 解析 mysqldump 文件，返回预处理后的 SQL 文本
+// 参数 path 是SQL文件的路径
+// 返回值: 预处理后的SQL文本和可能的错误
+// 注意：包含分号的语句（如触发器定义）以 DELIMITER 包围，保证重新切分时不会被拆开。
+func (p *MysqldumpFileParser) Parse(path string) (string, error) {
+	var sb strings.Builder
+	err := p.StreamStatements(path, func(stmt sqlparser.Statement, _ map[string]string) error {
+		sb.WriteString(FormatStatement(stmt.Text))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// StreamStatements 逐条读取并预处理 mysqldump 文件中的语句
+// 参数:
+//   - path: SQL文件的路径
+//   - fn: 语句回调，meta 包含语句所在的导出分段、表和数据库；返回错误时停止解析
+//
+// 返回:
+//   - error: 文件错误、读取错误或 fn 返回的错误
+//
+// 注意: 展开版本注释后语句的起始位置不变，但同一行内注释之后的列号可能与原文不同。
+func (p *MysqldumpFileParser) StreamStatements(path string, fn func(stmt sqlparser.Statement, meta map[string]string) error) error {
+	file, err := p.files.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "关闭文件 %s 失败: %v\n", path, err)
+		}
+	}()
+
+	return p.parseDump(file, fn)
+}
+
+// ReadStatements 从已打开的输入流中逐条读取并预处理 mysqldump 语句
+// 用于归档成员等没有独立文件路径的输入，调用方负责打开与关闭 reader。
+func (p *MysqldumpFileParser) ReadStatements(reader io.Reader, fn func(stmt sqlparser.Statement, meta map[string]string) error) error {
+	return p.parseDump(reader, fn)
+}
+
+// dumpSection 导出分段的起始位置
+type dumpSection struct {
+	line     int
+	section  string
+	table    string
+	database string
+}
+
+// parseDump 从io.Reader中解析 mysqldump 内容
+func (p *MysqldumpFileParser) parseDump(reader io.Reader, fn func(stmt sqlparser.Statement, meta map[string]string) error) error {
+	// 分段标记按行号记录，语句返回时取起始行之前最近的分段
+	sections := []dumpSection{{section: DumpSectionHeader}}
+	scanner := sqlparser.NewStatementScanner(reader)
+	scanner.OnComment(func(line int, text string) {
+		m := dumpHeaderRe.FindStringSubmatch(strings.TrimSpace(text))
+		if m == nil {
+			return
+		}
+		next := sections[len(sections)-1]
+		next.line, next.section, next.table = line, dumpHeaderSections[m[1]], ""
+		switch next.section {
+		case DumpSectionDatabase:
+			next.database = m[2]
+		case DumpSectionSchema, DumpSectionData, DumpSectionView:
+			next.table = m[2]
+		}
+		sections = append(sections, next)
+	})
+
+	for scanner.Scan() {
+		stmt := scanner.Statement()
+
+		// 丢弃已经过去的分段，只保留当前语句所在的分段及之后的分段
+		for len(sections) > 1 && sections[1].line <= stmt.Line {
+			sections = sections[1:]
+		}
+		current := sections[0]
+
+		stmt.Text = sqlparser.ExpandVersionedComments(stmt.Text, p.version)
+		if stmt.Text == "" || dumpLockRe.MatchString(stmt.Text) {
+			continue
+		}
+
+		meta := map[string]string{DumpMetaSection: current.section}
+		if m := dumpObjectRe.FindStringSubmatch(stmt.Text); m != nil {
+			meta[DumpMetaSection] = dumpObjectSection(m[1])
+		}
+		if current.table != "" {
+			meta[DumpMetaTable] = current.table
+		}
+		if current.database != "" {
+			meta[DumpMetaDatabase] = current.database
+		}
+
+		if err := fn(stmt, meta); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取 mysqldump 内容时出错: %w", err)
+	}
+	return nil
+}
+
+// dumpObjectSection 返回对象定义所属的导出分段
+func dumpObjectSection(object string) string {
+	switch strings.ToUpper(object) {
+	case "TRIGGER":
+		return DumpSectionTrigger
+	case "EVENT":
+		return DumpSectionEvent
+	}
+	return DumpSectionRoutine
+}
+
+// FormatStatement 将单条语句格式化为可重新切分的 SQL 文本
+// 语句以分号和换行符结尾；包含分号的语句（如触发器定义体）使用 DELIMITER ;; 包围。
+// 参数:
+//   - text: 语句文本，不含结尾分隔符
+//
+// 返回:
+//   - string: 格式化后的文本
+func FormatStatement(text string) string {
+	if strings.Contains(text, ";") {
+		return "DELIMITER ;;\n" + text + ";;\nDELIMITER ;\n"
+	}
+	return text + ";\n"
+}
+
+// IsMysqldump 判断内容是否为 mysqldump 的输出
+// 读取开头最多 dumpSniffLines 行，出现 "-- MySQL dump" 或 "-- MariaDB dump" 即视为 mysqldump 输出。
+// 参数:
+//   - reader: SQL 内容
+//
+// 返回值:
+//   - bool: true 表示 mysqldump 输出
+func IsMysqldump(reader io.Reader) bool {
+	scanner := newLineScanner(reader)
+	for i := 0; i < dumpSniffLines && scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "-- MySQL dump") || strings.HasPrefix(line, "-- MariaDB dump") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMysqldumpFile 判断文件是否为 mysqldump 的输出
+// 参数:
+//   - path: 文件路径
+//
+// 返回值:
+//   - bool: true 表示 mysqldump 输出，文件无法读取时返回 false
+func IsMysqldumpFile(path string) bool {
+	file, err := openText(path, "")
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return IsMysqldump(file)
+}
@@ -0,0 +1,97 @@
+package inputparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sqlparser "github.com/example/ybMigration/internal/sql-parser"
+	"github.com/example/ybMigration/internal/testutils"
+)
+
+// dumpStatement 测试中记录的预处理结果
+type dumpStatement struct {
+	stmt sqlparser.Statement
+	meta map[string]string
+}
+
+// streamDump 按指定服务器版本读取测试用的 mysqldump 文件
+func streamDump(t *testing.T, version int) []dumpStatement {
+	t.Helper()
+	var stmts []dumpStatement
+	err := NewMysqldumpFileParser(version).StreamStatements(testutils.MustGetTestDataPath("mysqldump_example.sql"),
+		func(stmt sqlparser.Statement, meta map[string]string) error {
+			stmts = append(stmts, dumpStatement{stmt: stmt, meta: meta})
+			return nil
+		})
+	require.NoError(t, err)
+	return stmts
+}
+
+func TestMysqldumpParser_StreamStatements(t *testing.T) {
+	stmts := streamDump(t, 80035)
+
+	byLine := make(map[int]dumpStatement)
+	for _, s := range stmts {
+		assert.NotRegexp(t, `(?i)^(UNLOCK|LOCK) TABLES`, s.stmt.Text, "LOCK/UNLOCK TABLES 应被去除")
+		assert.NotContains(t, s.stmt.Text, "/*!", "版本注释应被展开")
+		byLine[s.stmt.Line] = s
+	}
+
+	tests := []struct {
+		line    int
+		text    string
+		section string
+		table   string
+	}{
+		{line: 8, text: "SET NAMES utf8mb4", section: DumpSectionHeader},
+		{line: 9, text: "SET @@SESSION.default_table_encryption=OFF", section: DumpSectionHeader},
+		{line: 15, text: "CREATE DATABASE IF NOT EXISTS `shop` DEFAULT CHARACTER SET utf8mb4", section: DumpSectionDatabase},
+		{line: 23, text: "DROP TABLE IF EXISTS `users`", section: DumpSectionSchema, table: "users"},
+		{line: 39, text: "INSERT INTO `users` VALUES (1,'alice','a;b'),(2,'bob',NULL)", section: DumpSectionData, table: "users"},
+		{line: 44, section: DumpSectionTrigger, table: "users"},
+		{line: 54, section: DumpSectionRoutine},
+	}
+	for _, tt := range tests {
+		s, ok := byLine[tt.line]
+		require.True(t, ok, "第 %d 行应有语句", tt.line)
+		if tt.text != "" {
+			assert.Equal(t, tt.text, s.stmt.Text)
+		}
+		assert.Equal(t, tt.section, s.meta[DumpMetaSection], "第 %d 行", tt.line)
+		assert.Equal(t, tt.table, s.meta[DumpMetaTable], "第 %d 行", tt.line)
+	}
+	assert.Equal(t, "shop", byLine[54].meta[DumpMetaDatabase])
+
+	// DELIMITER ;; 中的触发器定义体保持完整
+	trigger := byLine[44].stmt.Text
+	assert.True(t, strings.HasPrefix(trigger, "CREATE DEFINER=`root`@`localhost` TRIGGER `users_bi`"), trigger)
+	assert.True(t, strings.HasSuffix(trigger, "NEW.name);\nEND"), trigger)
+	assert.Contains(t, byLine[54].stmt.Text, "WHERE id = uid;\nEND")
+
+	// 低版本服务器不执行高版本的版本注释
+	old := streamDump(t, 50500)
+	for _, s := range old {
+		assert.NotContains(t, s.stmt.Text, "SET NAMES", "50503 版本注释不应在 5.5.0 上生效")
+		assert.NotContains(t, s.stmt.Text, "default_table_encryption")
+	}
+	assert.Len(t, old, len(stmts)-2)
+}
+
+func TestMysqldumpParser_Parse(t *testing.T) {
+	got, err := NewMysqldumpFileParser(80000).Parse(testutils.MustGetTestDataPath("mysqldump_example.sql"))
+	require.NoError(t, err)
+
+	// 预处理结果可以重新切分，包含分号的定义体由 DELIMITER 包围
+	stmts := sqlparser.SplitStatements(got)
+	assert.Len(t, stmts, len(streamDump(t, 80000)))
+	assert.Contains(t, got, "DELIMITER ;;\nCREATE DEFINER=`root`@`localhost` TRIGGER")
+}
+
+func TestIsMysqldump(t *testing.T) {
+	assert.True(t, IsMysqldumpFile(testutils.MustGetTestDataPath("mysqldump_example.sql")))
+	assert.True(t, IsMysqldump(strings.NewReader("-- MariaDB dump 10.19  Distrib 10.6.12-MariaDB\n")))
+	assert.False(t, IsMysqldump(strings.NewReader("CREATE TABLE t (id INT);\n")))
+}
@@ -0,0 +1,120 @@
+package inputparser
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	sqlparser "github.com/example/ybMigration/internal/sql-parser"
+)
+
+// picosecondsPerSecond performance_schema 计时器（皮秒）与秒的换算
+const picosecondsPerSecond = 1e12
+
+// Segment 从输入中提取的一段 SQL 及其来源信息
+// 日志类输入中的 SQL 与原始文件的行号不一致，并且带有执行耗时等附加信息，
+// Segment 保留这些信息，使分析结果中的问题可以对应回原始输入。
+type Segment struct {
+	SQL  string            // SQL 文本，可以包含多行
+	Line int               // SQL 在原始输入中的起始行号（从 1 开始）
+	Meta map[string]string // 附加信息，如慢查询日志中的 query_time、rows_examined
+}
+
+// Executions 返回片段的执行次数
+// 预处理语句的执行次数记录在元数据 GeneralMetaExecutions 中，语句摘要记录在 DigestMetaCountStar 中，
+// 其他片段每条记录计 1 次。
+// 流式解析时预处理语句的执行次数随后续 Execute 记录增长，应在读取完所有片段后再调用。
+func (s Segment) Executions() int {
+	for _, key := range []string{GeneralMetaExecutions, DigestMetaCountStar} {
+		if v, ok := s.Meta[key]; ok {
+			if n, err := strconv.Atoi(v); err == nil {
+				return n
+			}
+		}
+	}
+	return 1
+}
+
+// Latency 返回片段的累计执行耗时（秒）
+// 目前只有语句摘要提供该信息（DigestMetaSumTimerWait，单位为皮秒），其他片段返回 0。
+func (s Segment) Latency() float64 {
+	v, err := strconv.ParseFloat(s.Meta[DigestMetaSumTimerWait], 64)
+	if err != nil {
+		return 0
+	}
+	return v / picosecondsPerSecond
+}
+
+// Deferred 判断片段的执行次数是否由后续记录累计（预处理语句）
+func (s Segment) Deferred() bool {
+	_, ok := s.Meta[GeneralMetaExecutions]
+	return ok
+}
+
+// SegmentParser 可选接口：按片段返回输入中的 SQL
+// 实现该接口的解析器由 analyzer 逐片段分析，问题的行号与附加信息取自对应片段。
+type SegmentParser interface {
+	InputParser
+	// ParseSegments 解析输入文件并返回 SQL 片段列表
+	ParseSegments(path string) ([]Segment, error)
+}
+
+// SegmentStreamer 可选接口：逐个产生输入中的 SQL 片段
+// 解析器只保留当前记录和少量状态，适合处理长时间积累的大日志文件。
+type SegmentStreamer interface {
+	SegmentParser
+	// StreamSegments 解析输入文件，每得到一个片段调用一次 fn
+	// fn 返回错误时停止解析并返回该错误。
+	StreamSegments(path string, fn func(Segment) error) error
+	// ReadSegments 从已打开的输入流中解析，用于归档成员等没有独立路径的输入
+	ReadSegments(reader io.Reader, fn func(Segment) error) error
+}
+
+// StatementStreamer 可选接口：逐条产生经过预处理的语句
+// 与 SegmentStreamer 不同，语句不按指纹合并，每条语句都会被分析并输出转换结果，
+// 适合 mysqldump 这类需要完整转换的输入。
+type StatementStreamer interface {
+	InputParser
+	// StreamStatements 解析输入文件，每得到一条语句调用一次 fn
+	// meta 为附加到该语句问题上的信息；fn 返回错误时停止解析并返回该错误。
+	StreamStatements(path string, fn func(stmt sqlparser.Statement, meta map[string]string) error) error
+	// ReadStatements 从已打开的输入流中解析，用于归档成员等没有独立路径的输入
+	ReadStatements(reader io.Reader, fn func(stmt sqlparser.Statement, meta map[string]string) error) error
+}
+
+// collectSegments 通过流式解析收集所有片段
+func collectSegments(stream func(fn func(Segment) error) error) ([]Segment, error) {
+	var segments []Segment
+	err := stream(func(seg Segment) error {
+		segments = append(segments, seg)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+// JoinSegments 将片段拼接为可直接分析的 SQL 文本
+// 每个片段以分号和换行符结尾，保证片段之间正确分隔。
+// 参数:
+//   - segments: SQL 片段列表
+//
+// 返回:
+//   - string: 拼接后的 SQL 文本
+//   - []int: 每个片段在拼接文本中的起始行号（从 1 开始）
+func JoinSegments(segments []Segment) (string, []int) {
+	var sb strings.Builder
+	starts := make([]int, len(segments))
+	line := 1
+	for i, seg := range segments {
+		starts[i] = line
+		sb.WriteString(seg.SQL)
+		if !strings.HasSuffix(seg.SQL, ";") {
+			sb.WriteString(";")
+		}
+		sb.WriteString("\n")
+		line += strings.Count(seg.SQL, "\n") + 1
+	}
+	return sb.String(), starts
+}
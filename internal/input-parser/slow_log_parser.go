@@ -0,0 +1,253 @@
+package inputparser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// 慢查询日志元数据的键名
+const (
+	SlowMetaTime         = "time"          // # Time 行中的时间
+	SlowMetaUser         = "user"          // 执行用户
+	SlowMetaHost         = "host"          // 客户端主机
+	SlowMetaThreadID     = "id"            // 连接 ID
+	SlowMetaQueryTime    = "query_time"    // 执行耗时（秒）
+	SlowMetaLockTime     = "lock_time"     // 锁等待耗时（秒）
+	SlowMetaRowsSent     = "rows_sent"     // 返回行数
+	SlowMetaRowsExamined = "rows_examined" // 扫描行数
+	SlowMetaTimestamp    = "timestamp"     // SET timestamp= 中的 Unix 时间戳
+	SlowMetaSchema       = "schema"        // use 语句指定的数据库
+)
+
+// slowLogSniffLines 判断日志格式时读取的最大行数
+const slowLogSniffLines = 64
+
+var (
+	// slowHeaderPairRe 匹配慢查询日志注释行中的 "Key: value" 对
+	// 格式示例: # Query_time: 2.000160  Lock_time: 0.000000 Rows_sent: 1  Rows_examined: 0
+	slowHeaderPairRe = regexp.MustCompile(`([A-Za-z_]+):\s+(\S+)`)
+	// slowUserHostRe 匹配 # User@Host 行
+	// 格式示例: # User@Host: root[root] @ localhost [127.0.0.1]  Id:     8
+	slowUserHostRe = regexp.MustCompile(`^#\s*User@Host:\s*(\S*?)\[[^\]]*\]\s*@\s*(\S*)\s*\[([^\]]*)\](?:\s+Id:\s*(\d+))?`)
+	// slowTimestampRe 匹配 SET timestamp=...; 行
+	slowTimestampRe = regexp.MustCompile(`(?i)^SET\s+timestamp\s*=\s*(\d+)\s*;?$`)
+	// useStmtRe 匹配 use db; 语句
+	useStmtRe = regexp.MustCompile("(?i)^use\\s+`?([^`;\\s]+)`?\\s*;?$")
+)
+
+// SlowLogFileParser 专门用于解析 MySQL 慢查询日志文件
+// 支持从慢查询日志中提取 SQL 语句，并保留每条语句的时间、用户、执行耗时、扫描行数等元数据。
+// 注意：日志文件开头的服务器启动信息、SET timestamp= 行和 use 语句不会作为 SQL 输出，
+// 其中的时间戳与数据库名记录到对应语句的元数据中。
+type SlowLogFileParser struct{}
+
+// NewSlowLogFileParser 创建并返回一个新的慢查询日志解析器
+func NewSlowLogFileParser() *SlowLogFileParser {
+	return &SlowLogFileParser{}
+}
+
+// Parse 解析慢查询日志文件
+// 参数 path 是日志文件的路径
+// 返回值: 提取的SQL语句字符串和可能的错误
+// 注意：不支持目录，请使用 analyzer 的 AnalyzeDirectory 方法处理目录。
+func (p *SlowLogFileParser) Parse(path string) (string, error) {
+	segments, err := p.ParseSegments(path)
+	if err != nil {
+		return "", err
+	}
+	sql, _ := JoinSegments(segments)
+	return sql, nil
+}
+
+// ParseSegments 解析慢查询日志文件，每条日志记录对应一个片段
+// 参数 path 是日志文件的路径
+// 返回值: SQL 片段列表（含元数据）和可能的错误
+func (p *SlowLogFileParser) ParseSegments(path string) ([]Segment, error) {
+	return collectSegments(func(fn func(Segment) error) error {
+		return p.StreamSegments(path, fn)
+	})
+}
+
+// StreamSegments 逐条解析慢查询日志文件，每得到一个片段调用一次 fn
+// 参数:
+//   - path: 日志文件的路径
+//   - fn: 片段回调，返回错误时停止解析
+//
+// 返回:
+//   - error: 文件错误、读取错误或 fn 返回的错误
+func (p *SlowLogFileParser) StreamSegments(path string, fn func(Segment) error) error {
+	if err := checkFile(path); err != nil {
+		return err
+	}
+
+	// 检查文件扩展名，压缩文件与归档成员按解压后的文件名判断
+	if !isLogFile(path) {
+		return fmt.Errorf("不支持的文件类型: %s，日志文件通常为 .log 扩展名", InputExt(path))
+	}
+
+	file, err := openText(path, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "关闭文件 %s 失败: %v\n", path, err)
+		}
+	}()
+
+	return p.parseSlowLog(file, fn)
+}
+
+// ReadSegments 从已打开的输入流中逐条解析慢查询日志，每得到一个片段调用一次 fn
+// 用于归档成员等没有独立文件路径的输入，调用方负责打开与关闭 reader。
+func (p *SlowLogFileParser) ReadSegments(reader io.Reader, fn func(Segment) error) error {
+	return p.parseSlowLog(reader, fn)
+}
+
+// slowLogEntry 慢查询日志中的一条记录
+type slowLogEntry struct {
+	meta      map[string]string
+	body      []string
+	bodyLine  int  // 第一行 SQL 的行号
+	hasHeader bool // 是否已读到 # User@Host 或 # Query_time 等头部
+}
+
+// parseSlowLog 从io.Reader中解析慢查询日志内容
+func (p *SlowLogFileParser) parseSlowLog(reader io.Reader, fn func(Segment) error) error {
+	var (
+		entry  = &slowLogEntry{meta: map[string]string{}}
+		lineNo int
+	)
+
+	flush := func() error {
+		sql := strings.TrimSpace(strings.Join(entry.body, "\n"))
+		seg := Segment{SQL: sql, Line: entry.bodyLine, Meta: entry.meta}
+		entry = &slowLogEntry{meta: map[string]string{}}
+		if sql == "" || isIgnoredSQL(sql) {
+			return nil
+		}
+		return fn(seg)
+	}
+
+	scanner := newLineScanner(reader)
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			continue
+
+		case strings.HasPrefix(trimmed, "#"):
+			// 已有 SQL 的记录遇到新的头部，说明上一条记录结束
+			if len(entry.body) > 0 || strings.HasPrefix(trimmed, "# Time:") && entry.hasHeader {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			p.parseHeader(trimmed, entry)
+
+		case len(entry.body) == 0 && isServerBanner(trimmed):
+			continue
+
+		case len(entry.body) == 0 && slowTimestampRe.MatchString(trimmed):
+			entry.meta[SlowMetaTimestamp] = slowTimestampRe.FindStringSubmatch(trimmed)[1]
+
+		case len(entry.body) == 0 && useStmtRe.MatchString(trimmed):
+			entry.meta[SlowMetaSchema] = useStmtRe.FindStringSubmatch(trimmed)[1]
+
+		default:
+			if len(entry.body) == 0 {
+				entry.bodyLine = lineNo
+			}
+			entry.body = append(entry.body, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取日志内容时出错: %w", err)
+	}
+	return flush()
+}
+
+// parseHeader 解析以 # 开头的头部行，结果写入记录的元数据
+func (p *SlowLogFileParser) parseHeader(line string, entry *slowLogEntry) {
+	switch {
+	case strings.HasPrefix(line, "# Time:"):
+		entry.meta[SlowMetaTime] = strings.TrimSpace(strings.TrimPrefix(line, "# Time:"))
+		return
+
+	case strings.HasPrefix(line, "# administrator command:"):
+		// 管理命令（如 Ping、Quit）不是 SQL
+		return
+	}
+
+	entry.hasHeader = true
+	if m := slowUserHostRe.FindStringSubmatch(line); m != nil {
+		entry.meta[SlowMetaUser] = m[1]
+		host := m[2]
+		if host == "" {
+			host = m[3]
+		}
+		entry.meta[SlowMetaHost] = host
+		if m[4] != "" {
+			entry.meta[SlowMetaThreadID] = m[4]
+		}
+		return
+	}
+
+	for _, m := range slowHeaderPairRe.FindAllStringSubmatch(line, -1) {
+		entry.meta[strings.ToLower(m[1])] = m[2]
+	}
+}
+
+// isServerBanner 判断是否为日志文件开头的服务器启动信息
+// 格式示例:
+//
+//	/usr/sbin/mysqld, Version: 8.0.35 (MySQL Community Server - GPL). started with:
+//	Tcp port: 3306  Unix socket: /var/run/mysqld/mysqld.sock
+//	Time                 Id Command    Argument
+func isServerBanner(line string) bool {
+	return strings.HasSuffix(line, "started with:") ||
+		strings.HasPrefix(line, "Tcp port:") ||
+		strings.HasPrefix(line, "Time ") && strings.Contains(line, "Command") && strings.Contains(line, "Argument")
+}
+
+// IsSlowLog 判断内容是否为 MySQL 慢查询日志
+// 读取开头最多 slowLogSniffLines 行，出现 # Query_time 或 # User@Host 头部即视为慢查询日志。
+// 参数:
+//   - reader: 日志内容
+//
+// 返回值:
+//   - bool: true 表示慢查询日志
+func IsSlowLog(reader io.Reader) bool {
+	scanner := newLineScanner(reader)
+	for i := 0; i < slowLogSniffLines && scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "# Query_time:") || strings.HasPrefix(line, "# User@Host:") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSlowLogFile 判断文件是否为 MySQL 慢查询日志
+// 参数:
+//   - path: 文件路径
+//
+// 返回值:
+//   - bool: true 表示慢查询日志，文件无法读取时返回 false
+func IsSlowLogFile(path string) bool {
+	file, err := OpenFile(path)
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return IsSlowLog(file)
+}
@@ -0,0 +1,79 @@
+package inputparser
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/ybMigration/internal/testutils"
+)
+
+func TestSlowLogParser_ParseSegments(t *testing.T) {
+	p := NewSlowLogFileParser()
+	segments, err := p.ParseSegments(testutils.MustGetTestDataPath("slow_query_example.log"))
+	require.NoError(t, err)
+	require.Len(t, segments, 2, "管理命令和 SET timestamp 不应作为 SQL 输出")
+
+	first := segments[0]
+	assert.Equal(t, "SELECT IFNULL(orderid, 'N/A') FROM orders WHERE created_at > NOW() - INTERVAL 1 DAY;", first.SQL)
+	assert.Equal(t, 9, first.Line)
+	assert.Equal(t, map[string]string{
+		SlowMetaTime:         "2023-12-23T08:00:01.234567Z",
+		SlowMetaUser:         "app",
+		SlowMetaHost:         "web01",
+		SlowMetaThreadID:     "12",
+		SlowMetaQueryTime:    "2.000160",
+		SlowMetaLockTime:     "0.000120",
+		SlowMetaRowsSent:     "1",
+		SlowMetaRowsExamined: "120000",
+		SlowMetaTimestamp:    "1703318401",
+		SlowMetaSchema:       "shop",
+	}, first.Meta)
+
+	// 多行语句保留原始换行，行号指向第一行
+	second := segments[1]
+	assert.Equal(t, 14, second.Line)
+	assert.Equal(t, 3, strings.Count(second.SQL, "\n"))
+	assert.Equal(t, "localhost", second.Meta[SlowMetaHost])
+	assert.Equal(t, "5.512000", second.Meta[SlowMetaQueryTime])
+}
+
+func TestSlowLogParser_Parse(t *testing.T) {
+	p := NewSlowLogFileParser()
+
+	got, err := p.Parse(testutils.MustGetTestDataPath("slow_query_example.log"))
+	require.NoError(t, err)
+	assertContainsAll(t, got, "SELECT IFNULL(orderid, 'N/A') FROM orders", "GROUP BY user_id;\n")
+	assert.NotContains(t, got, "timestamp")
+	assert.NotContains(t, got, "use shop")
+
+	unsupported := filepath.Join(t.TempDir(), "slow.txt")
+	requireWriteFile(t, unsupported, "# Query_time: 1")
+	_, err = p.Parse(unsupported)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "不支持的文件类型")
+}
+
+func TestIsSlowLog(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{name: "慢查询日志", content: "# Time: 231223  8:00:01\n# User@Host: root[root] @ localhost []\nSELECT 1;", want: true},
+		{name: "general log", content: "2023-12-23T08:00:01.234567Z     1 Query     SELECT 1", want: false},
+		{name: "空内容", content: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsSlowLog(strings.NewReader(tt.content)))
+		})
+	}
+
+	assert.True(t, IsSlowLogFile(testutils.MustGetTestDataPath("slow_query_example.log")))
+	assert.False(t, IsSlowLogFile(testutils.MustGetTestDataPath("general_log_example.log")))
+}
@@ -2,20 +2,19 @@ package inputparser
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
-	"strings"
 )
 
 // SQLFileParser 处理SQL文件输入
-// 支持 .sql 文件
+// 支持 .sql 文件，以及压缩后的 .sql.gz、.sql.zst 和归档中的 .sql 成员
 // 支持目录递归解析
-// 注意：不实现文件分片处理，原因：
-// 1. 现代服务器通常有足够的内存处理大文件
-// 2. 分片处理可能破坏完整的SQL语句，导致解析错误
-// 3. 保持代码简洁，将复杂的SQL解析逻辑交给专门的SQL解析器处理
+// 文件内容按 Encoding 转换为 UTF-8，GBK、latin1 等编码的旧导出文件不会出现乱码。
+// 注意：Parse 会一次性读取整个文件，大文件（如多 GB 的 mysqldump 导出）应通过 Open
+// 打开后交给 sqlparser.StatementScanner 逐条读取，不会在语句中间切分。
 type SQLFileParser struct {
-	// 可以添加配置选项，如字符集、是否忽略错误等
+	// Encoding 输入编码，如 gbk、latin1，为空时自动识别，见 NewDecodeReader
+	Encoding string
 }
 
 // NewSQLFileParser 创建并返回一个新的SQL文件解析器
@@ -26,34 +25,44 @@ func NewSQLFileParser() *SQLFileParser {
 // Parse 解析SQL文件
 // 参数 path 是SQL文件的路径
 // 返回值: SQL内容字符串和可能的错误
-// 注意：不支持目录，请使用 analyzer 的 AnalyzeInput 方法处理目录
+// 注意：不支持目录，请使用 analyzer 的 AnalyzeDirectory 方法处理目录
 func (p *SQLFileParser) Parse(path string) (string, error) {
-	// 路径检查
-	if path == "" {
-		return "", fmt.Errorf("文件路径不能为空")
+	file, err := p.Open(path)
+	if err != nil {
+		return "", err
 	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "关闭文件 %s 失败: %v\n", path, err)
+		}
+	}()
 
-	// 检查文件是否存在
-	fileInfo, err := os.Stat(path)
+	content, err := io.ReadAll(file)
 	if err != nil {
-		return "", fmt.Errorf("文件 %s 不存在: %w", path, err)
+		return "", fmt.Errorf("读取文件 %s 失败: %w", path, err)
 	}
 
-	// 不支持目录
-	if fileInfo.IsDir() {
-		return "", fmt.Errorf("不支持目录，请使用 analyzer 的 AnalyzeInput 方法")
+	// 返回转换为 UTF-8 的文件内容字符串
+	return string(content), nil
+}
+
+// Open 检查并打开SQL文件，压缩文件边读边解压，内容转换为 UTF-8
+// 参数 path 是SQL文件的路径或归档成员的虚拟路径
+// 返回值: 文件读取流和可能的错误，调用方负责关闭
+func (p *SQLFileParser) Open(path string) (io.ReadCloser, error) {
+	// 路径检查：不能为空、必须存在且不是目录
+	if err := checkFile(path); err != nil {
+		return nil, err
 	}
 
-	// 检查文件扩展名
-	if ext := strings.ToLower(filepath.Ext(path)); ext != ".sql" {
-		return "", fmt.Errorf("不支持的文件类型: %s，仅支持 .sql 文件", ext)
+	// 检查文件扩展名，压缩文件与归档成员按解压后的文件名判断
+	if ext := InputExt(path); ext != ".sql" {
+		return nil, fmt.Errorf("不支持的文件类型: %s，仅支持 .sql 文件", ext)
 	}
 
-	content, err := os.ReadFile(path) //nolint:gosec
+	file, err := openText(path, p.Encoding)
 	if err != nil {
-		return "", fmt.Errorf("读取文件 %s 失败: %w", path, err)
+		return nil, err
 	}
-
-	// 返回文件内容字符串
-	return string(content), nil
+	return file, nil
 }
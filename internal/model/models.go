@@ -14,12 +14,21 @@ type AutoFix struct {
 }
 
 // Issue 表示兼容性问题的数据模型。
+// 位置字段均指向原始输入：StatementIndex 为语句在输入中的序号，
+// Line/Column 为问题节点的起始行列号，Snippet 为问题节点本身的原文（过长时截断）。
 type Issue struct {
-	Checker string  `json:"checker"`
-	Message string  `json:"message"`
-	File    string  `json:"file,omitempty"`
-	Line    int     `json:"line,omitempty"`
-	AutoFix AutoFix `json:"autofix,omitempty"`
+	Checker        string            `json:"checker"`
+	Message        string            `json:"message"`
+	File           string            `json:"file,omitempty"`
+	StatementIndex int               `json:"statement_index,omitempty"` // 语句序号（从 1 开始）
+	Line           int               `json:"line,omitempty"`            // 行号（从 1 开始）
+	Column         int               `json:"column,omitempty"`          // 列号（从 1 开始，按字符计）
+	Snippet        string            `json:"snippet,omitempty"`         // 源码片段
+	Meta           map[string]string `json:"meta,omitempty"`            // 来源附加信息，如慢查询日志的执行耗时
+	Fingerprint    string            `json:"fingerprint,omitempty"`     // 日志类输入中语句的指纹
+	Executions     int               `json:"executions,omitempty"`      // 日志类输入中相同指纹语句的累计执行次数
+	TotalLatency   float64           `json:"total_latency,omitempty"`   // 语句摘要输入中相同指纹语句的累计执行耗时（秒）
+	AutoFix        AutoFix           `json:"autofix,omitempty"`
 }
 
 // UniqueIssue 表示唯一的 issue 类型
@@ -28,12 +37,25 @@ type UniqueIssue struct {
 	Message string `json:"message"` // 问题描述
 }
 
+// RankedIssue 表示按执行频次排序的问题
+// 日志类输入中同一问题可能由执行了成千上万次的语句触发，
+// 按累计耗时和执行次数排序可以优先处理对实际负载影响最大的问题。
+type RankedIssue struct {
+	Checker      string  `json:"checker"`                 // 检查器名称
+	Message      string  `json:"message"`                 // 问题描述
+	Executions   int     `json:"executions"`              // 触发该问题的语句累计执行次数，非日志输入每次出现计 1 次
+	Fingerprints int     `json:"fingerprints"`            // 触发该问题的不同语句（指纹）数量
+	TotalLatency float64 `json:"total_latency,omitempty"` // 触发该问题的语句累计执行耗时（秒），仅语句摘要输入提供
+}
+
 // AnalysisResult 表示 SQL 分析的结果
 type AnalysisResult struct {
-	SQL            string  `json:"sql"`                       // 原始 SQL 语句，可能包含多条 SQL 语句
-	Issues         []Issue `json:"issues"`                    // 发现的问题列表
-	Source         string  `json:"source,omitempty"`          // SQL 来源（文件、IO 等）
-	TransformedSQL string  `json:"transformed_sql,omitempty"` // 转换后的SQL语句
+	SQL             string  `json:"sql,omitempty"`              // 原始 SQL 语句，可能包含多条 SQL 语句；流式分析时为空
+	Issues          []Issue `json:"issues"`                     // 发现的问题列表
+	Source          string  `json:"source,omitempty"`           // SQL 来源（文件、IO 等）
+	TransformedSQL  string  `json:"transformed_sql,omitempty"`  // 转换后的SQL语句；流式分析时为空
+	TransformedFile string  `json:"transformed_file,omitempty"` // 流式分析时转换后的 SQL 写入的文件，如 dump_transformed.sql
+	Encoding        string  `json:"encoding,omitempty"`         // 输入文件的编码，如 utf8、gbk，分析前已转换为 UTF-8
 }
 
 // Report 表示 SQL 分析报告
@@ -42,6 +64,7 @@ type Report struct {
 	TotalAnalyses int              `json:"total_analyses"` // 总分析项数量
 	TotalIssues   int              `json:"total_issues"`   // 总问题数量（去重后）
 	UniqueIssues  []UniqueIssue    `json:"unique_issues"`  // 唯一问题列表
+	RankedIssues  []RankedIssue    `json:"ranked_issues"`  // 按执行频次降序排列的问题
 	Results       []AnalysisResult `json:"results"`        // 每个分析项的结果
 	GeneratedAt   time.Time        `json:"generated_at"`   // 报告生成时间
 
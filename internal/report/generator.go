@@ -5,6 +5,7 @@ package report
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/example/ybMigration/internal/checker"
@@ -51,6 +52,29 @@ func SupportedFormats() []string {
 // formats: 要生成的报告格式，如 "json"、"markdown"、"html"
 // 返回值: 错误信息
 func GenerateReports(basePath string, result model.AnalysisResult, cfg *config.Config, checkers []checker.Checker, formats ...string) error {
+	// 生成包含统计信息的报告
+	report := GenerateReport(result, cfg, checkers)
+	return writeReports(basePath, report, formats)
+}
+
+// GenerateReportsFromMultiple 根据多个分析结果生成多种格式的合并报告
+// basePath: 报告文件的基础路径（不包含扩展名）
+// results: 多个分析结果（如目录中每个文件的结果）
+// cfg: 配置实例（用于统计规则信息）
+// checkers: 检查器列表（用于统计检查器信息）
+// formats: 要生成的报告格式，如 "json"、"markdown"、"html"
+// 返回值: 错误信息
+func GenerateReportsFromMultiple(basePath string, results []model.AnalysisResult, cfg *config.Config, checkers []checker.Checker, formats ...string) error {
+	report := GenerateReportFromMultiple(results, cfg, checkers)
+	return writeReports(basePath, report, formats)
+}
+
+// writeReports 将报告按指定格式写入 basePath 目录
+// basePath: 报告输出目录
+// report: 已生成的报告
+// formats: 要生成的报告格式，为空时生成所有支持的格式
+// 返回值: 错误信息
+func writeReports(basePath string, report *model.Report, formats []string) error {
 	if len(formats) == 0 {
 		formats = SupportedFormats()
 	}
@@ -58,9 +82,6 @@ func GenerateReports(basePath string, result model.AnalysisResult, cfg *config.C
 	// 固定报告文件名（可配置，但通常固定为 summary）
 	const reportFileName = "summary"
 
-	// 生成包含统计信息的报告
-	report := GenerateReport(result, cfg, checkers)
-
 	for _, format := range formats {
 		generator, ok := GetGenerator(format)
 		if !ok {
@@ -90,6 +111,7 @@ func GenerateReport(result model.AnalysisResult, cfg *config.Config, checkers []
 		TotalAnalyses: 1,
 		TotalIssues:   len(uniqueIssues),
 		UniqueIssues:  uniqueIssues,
+		RankedIssues:  collectRankedIssues(result.Issues),
 		Results:       []model.AnalysisResult{result},
 		GeneratedAt:   time.Now(),
 		RuleStats:     collectRuleStats(cfg),
@@ -114,6 +136,7 @@ func GenerateReportFromMultiple(results []model.AnalysisResult, cfg *config.Conf
 		TotalAnalyses: len(results),
 		TotalIssues:   len(uniqueIssues),
 		UniqueIssues:  uniqueIssues,
+		RankedIssues:  collectRankedIssues(allIssues),
 		Results:       results,
 		GeneratedAt:   time.Now(),
 		RuleStats:     collectRuleStats(cfg),
@@ -153,6 +176,85 @@ func collectUniqueIssues(issues []model.Issue) []model.UniqueIssue {
 	return result
 }
 
+// collectRankedIssues 按执行频次统计并排序问题
+// 参数:
+//   - issues: 问题列表
+//
+// 返回值:
+//   - []model.RankedIssue: 按累计耗时、累计执行次数降序排列的问题列表
+//
+// 实现细节:
+//  1. 按检查器名称和消息内容分组，与 collectUniqueIssues 的去重依据相同
+//  2. 带指纹的问题（日志类输入）累加其语句的执行次数和耗时，同一文件中的同一指纹只计一次，
+//     不同文件中的同一指纹分别统计后相加（各文件的执行次数是独立的记录）
+//  3. 不带指纹的问题（SQL 文件等）每次出现计 1 次
+//  4. 语句摘要输入提供累计耗时，优先按耗时排序，反映实际生产负载
+//  5. 耗时与执行次数相同时按指纹数量、检查器名称、消息排序，保证顺序稳定
+func collectRankedIssues(issues []model.Issue) []model.RankedIssue {
+	type rankKey struct {
+		checker, message string
+	}
+	type statementKey struct {
+		file, fingerprint string
+	}
+	ranked := make(map[rankKey]*model.RankedIssue)
+	counted := make(map[rankKey]map[statementKey]bool)
+	fingerprints := make(map[rankKey]map[string]bool)
+	var keys []rankKey
+
+	for _, issue := range issues {
+		key := rankKey{checker: issue.Checker, message: issue.Message}
+		r, ok := ranked[key]
+		if !ok {
+			r = &model.RankedIssue{Checker: issue.Checker, Message: issue.Message}
+			ranked[key] = r
+			counted[key] = make(map[statementKey]bool)
+			fingerprints[key] = make(map[string]bool)
+			keys = append(keys, key)
+		}
+
+		if issue.Fingerprint == "" {
+			r.Executions++
+			r.Fingerprints++
+			continue
+		}
+		// 同一语句中的同一问题可能出现多次（如多个 TINYINT 列），执行次数只计一次
+		stmt := statementKey{file: issue.File, fingerprint: issue.Fingerprint}
+		if counted[key][stmt] {
+			continue
+		}
+		counted[key][stmt] = true
+		r.Executions += issue.Executions
+		r.TotalLatency += issue.TotalLatency
+		if !fingerprints[key][issue.Fingerprint] {
+			fingerprints[key][issue.Fingerprint] = true
+			r.Fingerprints++
+		}
+	}
+
+	result := make([]model.RankedIssue, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *ranked[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.TotalLatency != b.TotalLatency {
+			return a.TotalLatency > b.TotalLatency
+		}
+		if a.Executions != b.Executions {
+			return a.Executions > b.Executions
+		}
+		if a.Fingerprints != b.Fingerprints {
+			return a.Fingerprints > b.Fingerprints
+		}
+		if a.Checker != b.Checker {
+			return a.Checker < b.Checker
+		}
+		return a.Message < b.Message
+	})
+	return result
+}
+
 // collectRuleStats 收集规则统计信息
 // 参数:
 //   - cfg: 配置实例
@@ -22,6 +22,9 @@ func (g *HTMLGenerator) Write(path string, report model.Report) error {
 		TotalIssues  int
 		RuleStats    model.RuleStats
 		CheckerStats model.CheckerStats
+		Located      []model.Issue
+		Encoded      []model.AnalysisResult
+		Transformed  []model.AnalysisResult
 	}{
 		Title:        "SQL 分析报告",
 		Report:       report,
@@ -29,11 +32,17 @@ func (g *HTMLGenerator) Write(path string, report model.Report) error {
 		TotalIssues:  report.TotalIssues,
 		RuleStats:    report.RuleStats,
 		CheckerStats: report.CheckerStats,
+		Located:      locatedIssues(report.Results),
+		Encoded:      encodedResults(report.Results),
+		Transformed:  transformedResults(report.Results),
 	}
 
 	// 解析模板
 	tmpl, err := template.New("report").Funcs(template.FuncMap{
-		"add": func(a, b int) int { return a + b },
+		"add":      func(a, b int) int { return a + b },
+		"location": issueLocation,
+		"meta":     issueMeta,
+		"latency":  formatLatency,
 	}).Parse(htmlTemplate)
 	if err != nil {
 		return fmt.Errorf("解析模板失败: %w", err)
@@ -86,6 +95,8 @@ const htmlTemplate = `<!DOCTYPE html>
         code { font-family: 'Courier New', Courier, monospace; }
         ul { list-style-type: none; padding-left: 0; }
         li { margin-bottom: 5px; }
+        table { border-collapse: collapse; width: 100%; }
+        th, td { border: 1px solid #ddd; padding: 6px 8px; text-align: left; vertical-align: top; }
     </style>
 </head>
 <body>
@@ -137,11 +148,55 @@ const htmlTemplate = `<!DOCTYPE html>
             </div>
             {{end}}
         </div>
+        {{if .Report.RankedIssues}}
+        <div class="ranking">
+            <h2>按执行频次排序的问题</h2>
+            <table>
+                <tr><th>排名</th><th>检查器</th><th>描述</th><th>累计耗时（秒）</th><th>执行次数</th><th>语句指纹数</th></tr>
+                {{range $index, $issue := .Report.RankedIssues}}
+                <tr><td>{{add $index 1}}</td><td>{{$issue.Checker}}</td><td>{{$issue.Message}}</td><td>{{latency $issue.TotalLatency}}</td><td>{{$issue.Executions}}</td><td>{{$issue.Fingerprints}}</td></tr>
+                {{end}}
+            </table>
+        </div>
+        {{end}}
+        {{if .Located}}
+        <div class="locations">
+            <h2>问题位置</h2>
+            <table>
+                <tr><th>位置</th><th>检查器</th><th>描述</th><th>源码片段</th><th>附加信息</th></tr>
+                {{range $issue := .Located}}
+                <tr><td><code>{{location $issue}}</code></td><td>{{$issue.Checker}}</td><td>{{$issue.Message}}</td><td><code>{{$issue.Snippet}}</code></td><td class="meta">{{meta $issue}}</td></tr>
+                {{end}}
+            </table>
+        </div>
+        {{end}}
         {{else}}
         <div class="success-message">
             <p class="success">✓ 未发现兼容性问题</p>
         </div>
         {{end}}
+        {{if .Encoded}}
+        <div class="encodings">
+            <h2>输入编码</h2>
+            <table>
+                <tr><th>文件</th><th>编码</th></tr>
+                {{range $result := .Encoded}}
+                <tr><td><code>{{$result.Source}}</code></td><td>{{$result.Encoding}}</td></tr>
+                {{end}}
+            </table>
+        </div>
+        {{end}}
+        {{if .Transformed}}
+        <div class="transformed">
+            <h2>转换后的 SQL</h2>
+            <table>
+                <tr><th>文件</th><th>转换后的 SQL 文件</th></tr>
+                {{range $result := .Transformed}}
+                <tr><td><code>{{$result.Source}}</code></td><td><code>{{$result.TransformedFile}}</code></td></tr>
+                {{end}}
+            </table>
+        </div>
+        {{end}}
     </div>
 </body>
 </html>`
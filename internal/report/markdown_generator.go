@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/example/ybMigration/internal/model"
 )
@@ -83,6 +84,38 @@ func (g *MarkdownGenerator) Write(path string, report model.Report) error {
 			fmt.Fprintln(&buf, "---")
 			fmt.Fprintln(&buf)
 		}
+
+		// 按累计耗时与执行频次排序，优先处理对实际负载影响最大的问题
+		if len(report.RankedIssues) > 0 {
+			fmt.Fprintln(&buf, "## 按执行频次排序的问题")
+			fmt.Fprintln(&buf)
+			fmt.Fprintln(&buf, "| 排名 | 检查器 | 描述 | 累计耗时（秒） | 执行次数 | 语句指纹数 |")
+			fmt.Fprintln(&buf, "| --- | --- | --- | --- | --- | --- |")
+			for i, issue := range report.RankedIssues {
+				fmt.Fprintf(&buf, "| %d | %s | %s | %s | %d | %d |\n",
+					i+1, issue.Checker, markdownCell(issue.Message), formatLatency(issue.TotalLatency), issue.Executions, issue.Fingerprints)
+			}
+			fmt.Fprintln(&buf)
+		}
+
+		// 写入每个问题的出现位置，便于在原始输入中定位
+		if located := locatedIssues(report.Results); len(located) > 0 {
+			fmt.Fprintln(&buf, "## 问题位置")
+			fmt.Fprintln(&buf)
+			fmt.Fprintln(&buf, "| 位置 | 检查器 | 描述 | 源码片段 | 附加信息 |")
+			fmt.Fprintln(&buf, "| --- | --- | --- | --- | --- |")
+			for _, issue := range located {
+				snippet := ""
+				if issue.Snippet != "" {
+					// 片段中常含反引号标识符，使用双反引号包围
+					snippet = "`` " + markdownCell(issue.Snippet) + " ``"
+				}
+				fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s |\n",
+					markdownCell(issueLocation(issue)), issue.Checker, markdownCell(issue.Message), snippet,
+					markdownCell(issueMeta(issue)))
+			}
+			fmt.Fprintln(&buf)
+		}
 	} else {
 		fmt.Fprintln(&buf, "## 状态")
 		fmt.Fprintln(&buf)
@@ -90,9 +123,39 @@ func (g *MarkdownGenerator) Write(path string, report model.Report) error {
 		fmt.Fprintln(&buf)
 	}
 
+	// 写入每个输入文件识别出的编码
+	if encoded := encodedResults(report.Results); len(encoded) > 0 {
+		fmt.Fprintln(&buf, "## 输入编码")
+		fmt.Fprintln(&buf)
+		fmt.Fprintln(&buf, "| 文件 | 编码 |")
+		fmt.Fprintln(&buf, "| --- | --- |")
+		for _, result := range encoded {
+			fmt.Fprintf(&buf, "| %s | %s |\n", markdownCell(result.Source), result.Encoding)
+		}
+		fmt.Fprintln(&buf)
+	}
+
+	// 写入转换后 SQL 所在的文件，流式分析的结果中不保留 SQL
+	if transformed := transformedResults(report.Results); len(transformed) > 0 {
+		fmt.Fprintln(&buf, "## 转换后的 SQL")
+		fmt.Fprintln(&buf)
+		fmt.Fprintln(&buf, "| 文件 | 转换后的 SQL 文件 |")
+		fmt.Fprintln(&buf, "| --- | --- |")
+		for _, result := range transformed {
+			fmt.Fprintf(&buf, "| %s | %s |\n", markdownCell(result.Source), markdownCell(result.TransformedFile))
+		}
+		fmt.Fprintln(&buf)
+	}
+
 	if _, err := f.Write(buf.Bytes()); err != nil {
 		return fmt.Errorf("写入文件失败: %w", err)
 	}
 
 	return nil
 }
+
+// markdownCell 转义 Markdown 表格单元格中的竖线和换行
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -2,11 +2,14 @@
 package report
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/example/ybMigration/internal/constants"
+	inputparser "github.com/example/ybMigration/internal/input-parser"
 	"github.com/example/ybMigration/internal/model"
 )
 
@@ -14,17 +17,178 @@ import (
 // sourcePath: 源文件路径
 // outputDir: 输出目录
 // 返回值: 转换后的SQL文件路径
+// 压缩文件先去除压缩扩展名，如 dump.sql.gz 输出为 dump_transformed.sql。
+// 只去除 .sql 扩展名，其他扩展名与日志的轮转序号保留在输出文件名中，
+// 使同一目录下的 a.sql 与 a.log 分别输出为 a_transformed.sql 与 a.log_transformed.sql，
+// general.log.1 与 general.log.2.gz 分别输出为 general.log.1_transformed.sql 与 general.log.2_transformed.sql。
 func GenerateTransformedSQLPath(sourcePath, outputDir string) string {
-	// 获取文件名（不含扩展名）
-	baseName := filepath.Base(sourcePath)
-	ext := filepath.Ext(baseName)
-	nameWithoutExt := baseName[:len(baseName)-len(ext)]
+	// 获取文件名（不含 .sql 扩展名）
+	baseName := inputparser.TrimCompressionExt(filepath.Base(sourcePath))
+	if ext := filepath.Ext(baseName); strings.EqualFold(ext, ".sql") {
+		baseName = baseName[:len(baseName)-len(ext)]
+	}
 
 	// 构造输出文件名
-	outputFileName := fmt.Sprintf("%s_transformed.sql", nameWithoutExt)
+	outputFileName := fmt.Sprintf("%s_transformed.sql", baseName)
 	return filepath.Join(outputDir, outputFileName)
 }
 
+// SaveTransformedSQLTree 将目录分析的逐文件结果保存到与输入目录结构一致的输出目录
+// results: 每个文件的分析结果，Source 为文件路径
+// inputDir: 输入目录，用于计算每个文件的相对路径
+// outputDir: 输出根目录
+// 返回值: 已保存的文件路径和错误信息
+//
+// 没有转换后 SQL 的结果（如解析失败的文件）会被跳过，其问题已记录在报告中；
+// 两个文件对应同一输出路径时（如 a.sql 与 a.sql.gz）返回错误，不覆盖已保存的文件。
+func SaveTransformedSQLTree(results []model.AnalysisResult, inputDir, outputDir string) ([]string, error) {
+	var saved []string
+	owners := make(map[string]string)
+	for _, result := range results {
+		if result.TransformedSQL == "" {
+			continue
+		}
+
+		outputPath, err := treeOutputPath(result.Source, inputDir, outputDir)
+		if err != nil {
+			return saved, err
+		}
+		if err := claimOutput(owners, outputPath, result.Source); err != nil {
+			return saved, err
+		}
+		if err := SaveTransformedSQL(result, outputPath); err != nil {
+			return saved, fmt.Errorf("保存 %s 失败: %w", result.Source, err)
+		}
+		saved = append(saved, outputPath)
+	}
+
+	return saved, nil
+}
+
+// treeOutputPath 计算输入目录中的文件对应的转换 SQL 输出路径
+// 归档成员的虚拟路径按归档名作为目录展开，如 dir/a.tar.gz!/db/users.sql 对应 a.tar.gz/db/users；
+// inputDir 为归档文件本身时，成员直接输出到 outputDir 下。
+func treeOutputPath(sourcePath, inputDir, outputDir string) (string, error) {
+	sourcePath = strings.ReplaceAll(sourcePath, inputparser.VirtualPathSeparator, string(filepath.Separator))
+	relPath, err := filepath.Rel(inputDir, sourcePath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return "", fmt.Errorf("文件 %s 不在输入目录 %s 中", sourcePath, inputDir)
+	}
+	return GenerateTransformedSQLPath(relPath, filepath.Join(outputDir, filepath.Dir(relPath))), nil
+}
+
+// claimOutput 登记输出路径对应的输入文件，输出路径已被其他输入文件使用时返回错误
+// owners: 输出路径到输入文件的映射
+func claimOutput(owners map[string]string, outputPath, sourcePath string) error {
+	if owner, ok := owners[outputPath]; ok {
+		return fmt.Errorf("%s 与 %s 的转换结果都将输出到 %s，为避免覆盖已跳过", sourcePath, owner, outputPath)
+	}
+	owners[outputPath] = sourcePath
+	return nil
+}
+
+// TransformedSQLTree 将目录中各文件的转换结果流式写入与输入目录结构一致的输出目录
+// 与 SaveTransformedSQLTree 的输出位置相同，但不需要在内存中保留转换后的 SQL。
+type TransformedSQLTree struct {
+	inputDir  string
+	outputDir string
+	files     []*TransformedSQLFile
+	owners    map[string]string // 输出路径到输入文件的映射，用于发现输出冲突
+}
+
+// NewTransformedSQLTree 创建目录输出
+// inputDir: 输入目录，用于计算每个文件的相对路径
+// outputDir: 输出根目录
+func NewTransformedSQLTree(inputDir, outputDir string) *TransformedSQLTree {
+	return &TransformedSQLTree{inputDir: inputDir, outputDir: outputDir, owners: make(map[string]string)}
+}
+
+// Open 返回输入文件对应的转换 SQL 输出，文件在第一次写入时才创建
+// sourcePath: 输入文件路径
+// 输出路径已被之前打开的输入文件使用时返回错误
+func (t *TransformedSQLTree) Open(sourcePath string) (*TransformedSQLFile, error) {
+	outputPath, err := treeOutputPath(sourcePath, t.inputDir, t.outputDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := claimOutput(t.owners, outputPath, sourcePath); err != nil {
+		return nil, err
+	}
+	file := NewTransformedSQLFile(outputPath)
+	t.files = append(t.files, file)
+	return file, nil
+}
+
+// Saved 返回已写入内容的输出文件路径
+func (t *TransformedSQLTree) Saved() []string {
+	var saved []string
+	for _, file := range t.files {
+		if file.Written() {
+			saved = append(saved, file.Path())
+		}
+	}
+	return saved
+}
+
+// TransformedSQLFile 流式写入的转换 SQL 文件
+// 文件及其目录在第一次写入时才创建，没有任何转换结果时不会留下空文件。
+type TransformedSQLFile struct {
+	path string
+	file *os.File
+	w    *bufio.Writer
+}
+
+// NewTransformedSQLFile 创建转换 SQL 文件输出
+// outputPath: 输出文件路径
+func NewTransformedSQLFile(outputPath string) *TransformedSQLFile {
+	return &TransformedSQLFile{path: outputPath}
+}
+
+// Path 返回输出文件路径
+func (f *TransformedSQLFile) Path() string {
+	return f.path
+}
+
+// Written 判断是否已写入内容
+func (f *TransformedSQLFile) Written() bool {
+	return f.file != nil
+}
+
+// Write 写入转换后的 SQL，第一次写入时创建文件
+func (f *TransformedSQLFile) Write(p []byte) (int, error) {
+	if f.file == nil {
+		if err := os.MkdirAll(filepath.Dir(f.path), constants.DirPermission); err != nil {
+			return 0, fmt.Errorf("创建输出目录失败: %w", err)
+		}
+		file, err := os.Create(f.path) //nolint:gosec
+		if err != nil {
+			return 0, fmt.Errorf("创建文件失败: %w", err)
+		}
+		f.file = file
+		f.w = bufio.NewWriter(file)
+	}
+	return f.w.Write(p)
+}
+
+// Close 将缓冲内容写入磁盘并关闭文件，未写入过内容时不做任何操作
+func (f *TransformedSQLFile) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	if err := f.w.Flush(); err != nil {
+		_ = f.file.Close()
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := f.file.Sync(); err != nil {
+		_ = f.file.Close()
+		return fmt.Errorf("同步文件失败: %w", err)
+	}
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("关闭文件失败: %w", err)
+	}
+	return nil
+}
+
 // SaveTransformedSQL 保存转换后的SQL到文件
 // result: 分析结果
 // outputPath: 输出文件路径
@@ -3,7 +3,10 @@ package report
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/example/ybMigration/internal/model"
 )
 
 // validateOutputPath 验证输出路径的安全性
@@ -47,3 +50,85 @@ func validateOutputPath(path string) error {
 
 	return nil
 }
+
+// issueLocation 格式化问题的位置，如 "schema.sql:12:5 (语句 #3)"
+// 没有任何位置信息时返回空字符串
+func issueLocation(issue model.Issue) string {
+	var b strings.Builder
+	b.WriteString(issue.File)
+	if issue.Line > 0 {
+		fmt.Fprintf(&b, ":%d", issue.Line)
+		if issue.Column > 0 {
+			fmt.Fprintf(&b, ":%d", issue.Column)
+		}
+	}
+	if issue.StatementIndex > 0 {
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(&b, "(语句 #%d)", issue.StatementIndex)
+	}
+	return b.String()
+}
+
+// issueMeta 格式化问题的来源附加信息，如 "query_time=2.000160, rows_examined=10"
+// 键按字母顺序排列，没有附加信息时返回空字符串
+func issueMeta(issue model.Issue) string {
+	if len(issue.Meta) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(issue.Meta))
+	for key := range issue.Meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+issue.Meta[key])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// formatLatency 格式化累计执行耗时，没有耗时信息时返回 "-"
+func formatLatency(seconds float64) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+// encodedResults 返回记录了输入编码的分析结果，按结果顺序排列
+func encodedResults(results []model.AnalysisResult) []model.AnalysisResult {
+	var encoded []model.AnalysisResult
+	for _, result := range results {
+		if result.Encoding != "" {
+			encoded = append(encoded, result)
+		}
+	}
+	return encoded
+}
+
+// transformedResults 返回转换后的 SQL 写入了文件的分析结果
+func transformedResults(results []model.AnalysisResult) []model.AnalysisResult {
+	var transformed []model.AnalysisResult
+	for _, result := range results {
+		if result.TransformedFile != "" {
+			transformed = append(transformed, result)
+		}
+	}
+	return transformed
+}
+
+// locatedIssues 返回所有带位置信息的问题，按结果顺序排列
+func locatedIssues(results []model.AnalysisResult) []model.Issue {
+	var issues []model.Issue
+	for _, result := range results {
+		for _, issue := range result.Issues {
+			if issueLocation(issue) != "" {
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,697 @@
+package sqlemitter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/types"
+)
+
+// ignoredTableOptions 仅影响 MySQL 存储层的表选项，在 YSQL 中没有意义，输出时直接省略
+var ignoredTableOptions = map[ast.TableOptionType]bool{
+	ast.TableOptionEngine:              true,
+	ast.TableOptionCharset:             true,
+	ast.TableOptionCollate:             true,
+	ast.TableOptionAvgRowLength:        true,
+	ast.TableOptionCheckSum:            true,
+	ast.TableOptionTableCheckSum:       true,
+	ast.TableOptionCompression:         true,
+	ast.TableOptionKeyBlockSize:        true,
+	ast.TableOptionMaxRows:             true,
+	ast.TableOptionMinRows:             true,
+	ast.TableOptionDelayKeyWrite:       true,
+	ast.TableOptionRowFormat:           true,
+	ast.TableOptionStatsPersistent:     true,
+	ast.TableOptionStatsAutoRecalc:     true,
+	ast.TableOptionStatsSamplePages:    true,
+	ast.TableOptionPackKeys:            true,
+	ast.TableOptionInsertMethod:        true,
+	ast.TableOptionAutoextendSize:      true,
+	ast.TableOptionPageChecksum:        true,
+	ast.TableOptionPageCompressed:      true,
+	ast.TableOptionTransactional:       true,
+	ast.TableOptionEncryption:          true,
+	ast.TableOptionSecondaryEngine:     true,
+	ast.TableOptionEngineAttribute:     true,
+	ast.TableOptionStatsBuckets:        true,
+	ast.TableOptionStatsTopN:           true,
+	ast.TableOptionStatsColsChoice:     true,
+	ast.TableOptionStatsColList:        true,
+	ast.TableOptionStatsSampleRate:     true,
+	ast.TableOptionSecondaryEngineNull: true,
+}
+
+// createTableStmt 输出 CREATE TABLE 语句
+func (e *Emitter) createTableStmt(n *ast.CreateTableStmt) {
+	e.table = n.Table
+
+	e.w("CREATE ")
+	if n.TemporaryKeyword != ast.TemporaryNone {
+		e.w("TEMPORARY ")
+	}
+	e.w("TABLE ")
+	if n.IfNotExists {
+		e.w("IF NOT EXISTS ")
+	}
+	e.tableName(n.Table)
+
+	if n.ReferTable != nil {
+		e.w(" (LIKE ")
+		e.tableName(n.ReferTable)
+		e.w(" INCLUDING ALL)")
+		e.updateTrigger(n)
+		return
+	}
+
+	elements := make([]string, 0, len(n.Cols)+len(n.Constraints))
+	for _, col := range n.Cols {
+		elements = append(elements, e.capture(func() { e.columnDef(col) }))
+	}
+	for _, constraint := range n.Constraints {
+		if s := e.capture(func() { e.constraint(constraint) }); s != "" {
+			elements = append(elements, s)
+		}
+	}
+	if len(elements) > 0 {
+		if n.Select != nil {
+			e.addIssue("CREATE TABLE ... SELECT 同时定义列时 YSQL 仅支持列名列表，列定义已省略")
+		} else {
+			e.w(" (" + strings.Join(elements, ",") + ")")
+		}
+	}
+	if n.Select != nil {
+		e.w(" AS ")
+		e.resultSet(n.Select)
+	}
+
+	e.tableOptions(n.Options)
+	if n.Partition != nil {
+		e.addIssue("表 %s 的 MySQL 分区定义在 YSQL 中需要改写为声明式分区，已省略", n.Table.Name.O)
+	}
+	e.extractedIndexes(n)
+	e.updateTrigger(n)
+}
+
+// tableOptions 处理表选项
+// COMMENT 转换为单独的 COMMENT ON TABLE 语句，存储层选项直接省略，其余选项记录为问题
+func (e *Emitter) tableOptions(options []*ast.TableOption) {
+	for _, opt := range options {
+		switch {
+		case opt.Tp == ast.TableOptionComment:
+			e.trailing = append(e.trailing, e.capture(func() {
+				e.w("COMMENT ON TABLE ")
+				e.tableName(e.table)
+				e.w(" IS ")
+				e.stringLiteral(opt.StrValue)
+			}))
+		case opt.Tp == ast.TableOptionAutoIncrement:
+			e.addIssue("表选项 AUTO_INCREMENT=%d 需要改写为对应序列的 ALTER SEQUENCE ... RESTART WITH", opt.UintValue)
+		case ignoredTableOptions[opt.Tp]:
+			continue
+		default:
+			e.addIssue("表选项 #%d 在 YSQL 中不支持，已省略", int(opt.Tp))
+		}
+	}
+}
+
+// columnDef 输出列定义
+func (e *Emitter) columnDef(col *ast.ColumnDef) {
+	e.name(col.Name.Name.O)
+	e.w(" ")
+	e.w(e.columnType(col))
+	for _, opt := range col.Options {
+		if s := e.capture(func() { e.columnOption(col, opt) }); s != "" {
+			e.w(" " + s)
+		}
+	}
+}
+
+// columnType 返回列在 YSQL 中的类型，检查器通过 Hints 指定的类型优先
+func (e *Emitter) columnType(col *ast.ColumnDef) string {
+	if typeName, ok := e.hints.ColumnType(col); ok {
+		return typeName
+	}
+	if e.hints.EnumType(col) && e.table != nil && col.Tp != nil && col.Tp.GetType() == mysql.TypeEnum {
+		return e.enumType(col)
+	}
+	return e.fieldType(col.Tp)
+}
+
+// enumType 在当前语句之前输出 ENUM 列对应的 CREATE TYPE 语句，返回枚举类型名
+// 类型名为 <表名>_<列名>，与表位于同一个 schema
+func (e *Emitter) enumType(col *ast.ColumnDef) string {
+	typeName := QuoteIdent(e.table.Name.O + "_" + col.Name.Name.O)
+	if e.table.Schema.O != "" {
+		typeName = QuoteIdent(e.table.Schema.O) + "." + typeName
+	}
+	elems := make([]string, len(col.Tp.GetElems()))
+	for i, elem := range col.Tp.GetElems() {
+		elems[i] = QuoteString(elem)
+	}
+	e.leading = append(e.leading, "CREATE TYPE "+typeName+" AS ENUM ("+strings.Join(elems, ",")+")")
+	return typeName
+}
+
+// columnOption 输出列选项
+func (e *Emitter) columnOption(col *ast.ColumnDef, opt *ast.ColumnOption) {
+	switch opt.Tp {
+	case ast.ColumnOptionNoOption:
+	case ast.ColumnOptionPrimaryKey:
+		e.w("PRIMARY KEY")
+	case ast.ColumnOptionNotNull:
+		e.w("NOT NULL")
+	case ast.ColumnOptionNull:
+		e.w("NULL")
+	case ast.ColumnOptionAutoIncrement:
+		e.w("GENERATED BY DEFAULT AS IDENTITY")
+	case ast.ColumnOptionDefaultValue:
+		e.w("DEFAULT ")
+		e.expr(opt.Expr)
+	case ast.ColumnOptionUniqKey:
+		e.w("UNIQUE")
+	case ast.ColumnOptionComment:
+		if v, ok := opt.Expr.(ast.ValueExpr); ok {
+			e.trailing = append(e.trailing, e.capture(func() {
+				e.w("COMMENT ON COLUMN ")
+				e.tableName(e.table)
+				e.w(".")
+				e.name(col.Name.Name.O)
+				e.w(" IS ")
+				e.stringLiteral(v.GetString())
+			}))
+		}
+	case ast.ColumnOptionGenerated:
+		if !opt.Stored {
+			e.addIssue("列 %s 的虚拟生成列在 YSQL 中不支持，已输出为 STORED 生成列", col.Name.Name.O)
+		}
+		e.w("GENERATED ALWAYS AS (")
+		e.expr(opt.Expr)
+		e.w(") STORED")
+	case ast.ColumnOptionReference:
+		e.reference(opt.Refer)
+	case ast.ColumnOptionCheck:
+		if !opt.Enforced {
+			e.addIssue("列 %s 的 NOT ENFORCED CHECK 约束在 YSQL 中不支持，已省略", col.Name.Name.O)
+			return
+		}
+		if opt.ConstraintName != "" {
+			e.w("CONSTRAINT ")
+			e.name(opt.ConstraintName)
+			e.w(" ")
+		}
+		e.w("CHECK (")
+		e.expr(opt.Expr)
+		e.w(")")
+	case ast.ColumnOptionOnUpdate:
+		e.addIssue("列 %s 的 ON UPDATE 选项在 YSQL 中没有列级写法，已省略，需要改用触发器", col.Name.Name.O)
+	case ast.ColumnOptionCollate:
+		e.addIssue("列 %s 的排序规则 %s 在 YSQL 中不存在，已省略", col.Name.Name.O, opt.StrValue)
+	default:
+		e.addIssue("列 %s 的选项 %s 在 YSQL 中不支持，已省略", col.Name.Name.O, columnOptionName(opt.Tp))
+	}
+}
+
+// columnOptionName 返回列选项的可读名称
+func columnOptionName(tp ast.ColumnOptionType) string {
+	switch tp {
+	case ast.ColumnOptionColumnFormat:
+		return "COLUMN_FORMAT"
+	case ast.ColumnOptionStorage:
+		return "STORAGE"
+	case ast.ColumnOptionAutoRandom:
+		return "AUTO_RANDOM"
+	default:
+		return "#" + strconv.Itoa(int(tp))
+	}
+}
+
+// constraint 输出表级约束
+// 内联的普通索引、全文索引无法在 CREATE TABLE 中表达，省略并记录问题
+func (e *Emitter) constraint(c *ast.Constraint) {
+	switch c.Tp {
+	case ast.ConstraintPrimaryKey:
+		e.w("PRIMARY KEY (")
+		e.keyColumns(c.Keys)
+		e.w(")")
+	case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		e.constraintName(c.Name)
+		e.w("UNIQUE (")
+		e.keyColumns(c.Keys)
+		e.w(")")
+	case ast.ConstraintForeignKey:
+		e.constraintName(c.Name)
+		e.w("FOREIGN KEY (")
+		e.keyColumns(c.Keys)
+		e.w(") ")
+		e.reference(c.Refer)
+	case ast.ConstraintCheck:
+		if !c.Enforced {
+			e.addIssue("NOT ENFORCED CHECK 约束 %s 在 YSQL 中不支持，已省略", c.Name)
+			return
+		}
+		e.constraintName(c.Name)
+		e.w("CHECK (")
+		e.expr(c.Expr)
+		e.w(")")
+	case ast.ConstraintKey, ast.ConstraintIndex:
+		e.addIssue("CREATE TABLE 中的内联索引 %s 在 YSQL 中不支持，已省略，需要改写为单独的 CREATE INDEX", c.Name)
+	case ast.ConstraintFulltext:
+		e.addIssue("全文索引 %s 在 YSQL 中不支持，已省略，需要改用 tsvector + GIN 索引", c.Name)
+	default:
+		e.addIssue("约束 %s 在 YSQL 中不支持，已省略", c.Name)
+	}
+}
+
+// constraintName 输出 CONSTRAINT name 前缀
+func (e *Emitter) constraintName(name string) {
+	if name == "" {
+		return
+	}
+	e.w("CONSTRAINT ")
+	e.name(name)
+	e.w(" ")
+}
+
+// keyColumns 输出约束列列表
+// YSQL 的 PRIMARY KEY/UNIQUE 约束只接受列名，前缀长度与排序方向会被省略
+func (e *Emitter) keyColumns(keys []*ast.IndexPartSpecification) {
+	for i, key := range keys {
+		if i > 0 {
+			e.w(",")
+		}
+		if key.Expr != nil || key.Column == nil {
+			e.addIssue("约束中的表达式键在 YSQL 中不支持，需要改写为表达式唯一索引")
+			e.unsupportedNode(key)
+			continue
+		}
+		e.name(key.Column.Name.O)
+		if key.Length > 0 {
+			e.addIssue("列 %s 的前缀长度 (%d) 在 YSQL 约束中不支持，已省略", key.Column.Name.O, key.Length)
+		}
+	}
+}
+
+// indexParts 输出索引列列表（支持表达式与排序方向）
+func (e *Emitter) indexParts(parts []*ast.IndexPartSpecification) {
+	for i, part := range parts {
+		if i > 0 {
+			e.w(",")
+		}
+		if part.Expr != nil {
+			e.w("(")
+			e.expr(part.Expr)
+			e.w(")")
+		} else {
+			e.name(part.Column.Name.O)
+			if part.Length > 0 {
+				e.addIssue("列 %s 的前缀索引长度 (%d) 在 YSQL 中不支持，已省略", part.Column.Name.O, part.Length)
+			}
+		}
+		if part.Desc {
+			e.w(" DESC")
+		}
+	}
+}
+
+// reference 输出 REFERENCES 子句
+func (e *Emitter) reference(ref *ast.ReferenceDef) {
+	if ref == nil {
+		return
+	}
+	e.w("REFERENCES ")
+	e.tableName(ref.Table)
+	if len(ref.IndexPartSpecifications) > 0 {
+		e.w(" (")
+		e.keyColumns(ref.IndexPartSpecifications)
+		e.w(")")
+	}
+	if ref.OnDelete != nil && ref.OnDelete.ReferOpt != ast.ReferOptionNoOption {
+		e.w(" ON DELETE " + ref.OnDelete.ReferOpt.String())
+	}
+	if ref.OnUpdate != nil && ref.OnUpdate.ReferOpt != ast.ReferOptionNoOption {
+		e.w(" ON UPDATE " + ref.OnUpdate.ReferOpt.String())
+	}
+}
+
+// alterTableStmt 输出 ALTER TABLE 语句
+// 可合并的子句输出在同一条 ALTER TABLE 中；YSQL 要求单独执行的操作（重命名、索引）追加为后续语句
+func (e *Emitter) alterTableStmt(n *ast.AlterTableStmt) {
+	e.table = n.Table
+
+	var actions []string
+	var extra []string
+	addAction := func(fn func()) {
+		if s := e.capture(fn); s != "" {
+			actions = append(actions, s)
+		}
+	}
+	addStmt := func(fn func()) {
+		extra = append(extra, e.capture(func() {
+			e.w("ALTER TABLE ")
+			e.tableName(n.Table)
+			e.w(" ")
+			fn()
+		}))
+	}
+
+	for _, spec := range n.Specs {
+		switch spec.Tp {
+		case ast.AlterTableAddColumns:
+			for _, col := range spec.NewColumns {
+				addAction(func() {
+					e.w("ADD COLUMN ")
+					if spec.IfNotExists {
+						e.w("IF NOT EXISTS ")
+					}
+					e.columnDef(col)
+				})
+			}
+			for _, c := range spec.NewConstraints {
+				addAction(func() { e.addConstraintAction(c, &extra) })
+			}
+			if spec.Position != nil && spec.Position.Tp != ast.ColumnPositionNone {
+				e.addIssue("ADD COLUMN 的 FIRST/AFTER 列位置在 YSQL 中不支持，新列将追加在末尾")
+			}
+		case ast.AlterTableAddConstraint:
+			addAction(func() { e.addConstraintAction(spec.Constraint, &extra) })
+		case ast.AlterTableDropColumn:
+			addAction(func() {
+				e.w("DROP COLUMN ")
+				if spec.IfExists {
+					e.w("IF EXISTS ")
+				}
+				e.name(spec.OldColumnName.Name.O)
+			})
+		case ast.AlterTableDropPrimaryKey:
+			// YSQL 默认的主键约束名为 <表名>_pkey
+			addAction(func() {
+				e.w("DROP CONSTRAINT ")
+				e.name(n.Table.Name.O + "_pkey")
+			})
+		case ast.AlterTableDropIndex:
+			extra = append(extra, e.capture(func() {
+				e.w("DROP INDEX ")
+				if spec.IfExists {
+					e.w("IF EXISTS ")
+				}
+				e.indexName(n.Table, spec.Name)
+			}))
+		case ast.AlterTableDropForeignKey, ast.AlterTableDropCheck:
+			addAction(func() {
+				e.w("DROP CONSTRAINT ")
+				if spec.IfExists {
+					e.w("IF EXISTS ")
+				}
+				e.name(spec.Name)
+			})
+		case ast.AlterTableModifyColumn, ast.AlterTableChangeColumn:
+			if len(spec.NewColumns) == 0 {
+				continue
+			}
+			col := spec.NewColumns[0]
+			oldName := col.Name.Name.O
+			if spec.Tp == ast.AlterTableChangeColumn && spec.OldColumnName != nil {
+				oldName = spec.OldColumnName.Name.O
+			}
+			actions = append(actions, e.modifyColumnActions(oldName, col)...)
+			if !strings.EqualFold(oldName, col.Name.Name.O) {
+				addStmt(func() {
+					e.w("RENAME COLUMN ")
+					e.name(oldName)
+					e.w(" TO ")
+					e.name(col.Name.Name.O)
+				})
+			}
+			if spec.Position != nil && spec.Position.Tp != ast.ColumnPositionNone {
+				e.addIssue("列 %s 的 FIRST/AFTER 列位置在 YSQL 中不支持，已省略", col.Name.Name.O)
+			}
+		case ast.AlterTableRenameColumn:
+			addStmt(func() {
+				e.w("RENAME COLUMN ")
+				e.name(spec.OldColumnName.Name.O)
+				e.w(" TO ")
+				e.name(spec.NewColumnName.Name.O)
+			})
+		case ast.AlterTableRenameTable:
+			addStmt(func() {
+				e.w("RENAME TO ")
+				e.name(spec.NewTable.Name.O)
+			})
+		case ast.AlterTableRenameIndex:
+			extra = append(extra, e.capture(func() {
+				e.w("ALTER INDEX ")
+				e.indexName(n.Table, spec.FromKey.O)
+				e.w(" RENAME TO ")
+				e.name(spec.ToKey.O)
+			}))
+		case ast.AlterTableAlterColumn:
+			if len(spec.NewColumns) == 0 {
+				continue
+			}
+			col := spec.NewColumns[0]
+			addAction(func() {
+				e.w("ALTER COLUMN ")
+				e.name(col.Name.Name.O)
+				if len(col.Options) == 0 {
+					e.w(" DROP DEFAULT")
+					return
+				}
+				e.w(" SET DEFAULT ")
+				e.expr(col.Options[0].Expr)
+			})
+		case ast.AlterTableOption:
+			e.tableOptions(spec.Options)
+		case ast.AlterTableLock, ast.AlterTableAlgorithm, ast.AlterTableForce:
+			continue
+		default:
+			e.addIssue("ALTER TABLE %s 的子句 %s 在 YSQL 中不支持，已省略", n.Table.Name.O, alterSpecText(spec))
+		}
+	}
+
+	if len(actions) > 0 {
+		e.w("ALTER TABLE ")
+		e.tableName(n.Table)
+		e.w(" " + strings.Join(actions, ", "))
+	} else if len(extra) > 0 {
+		e.w(extra[0])
+		extra = extra[1:]
+	}
+	e.trailing = append(extra, e.trailing...)
+	e.extractedIndexes(n)
+	e.updateTrigger(n)
+}
+
+// alterSpecText 返回 ALTER TABLE 子句的 MySQL 原文（如 DISABLE KEYS），用于问题描述
+// 无法还原时退回子句类型编号
+func alterSpecText(spec *ast.AlterTableSpec) string {
+	var sb strings.Builder
+	if err := spec.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil || sb.Len() == 0 {
+		return "#" + strconv.Itoa(int(spec.Tp))
+	}
+	return sb.String()
+}
+
+// indexName 输出索引名
+// YSQL 的索引与所属表位于同一个 schema，表带库名时索引名同样带上 schema
+func (e *Emitter) indexName(table *ast.TableName, name string) {
+	if table != nil && table.Schema.O != "" {
+		e.name(table.Schema.O)
+		e.w(".")
+	}
+	e.name(name)
+}
+
+// extractedIndexes 在表定义之后输出检查器从 CREATE/ALTER TABLE 中拆分出的 CREATE INDEX 语句
+func (e *Emitter) extractedIndexes(stmt ast.StmtNode) {
+	for _, index := range e.hints.Indexes(stmt) {
+		e.trailing = append(e.trailing, e.capture(func() { e.createIndexStmt(index) }))
+	}
+}
+
+// updateTrigger 在表定义之后输出模拟 ON UPDATE CURRENT_TIMESTAMP 的触发器函数与 BEFORE UPDATE 触发器
+// 行的任一列发生变化且语句没有修改该列时，列更新为 ON UPDATE 表达式的值；YSQL 在 PG 14 之前没有
+// CREATE OR REPLACE TRIGGER，先 DROP TRIGGER IF EXISTS 再创建。没有需要更新的列时删除触发器与函数。
+func (e *Emitter) updateTrigger(stmt ast.StmtNode) {
+	trigger, ok := e.hints.UpdateTrigger(stmt)
+	if !ok || e.table == nil {
+		return
+	}
+	table := e.capture(func() { e.tableName(e.table) })
+	function := QuoteIdent(trigger.Function)
+	if e.table.Schema.O != "" {
+		function = QuoteIdent(e.table.Schema.O) + "." + function
+	}
+	dropTrigger := "DROP TRIGGER IF EXISTS " + QuoteIdent(trigger.Trigger) + " ON " + table
+	if len(trigger.Columns) == 0 {
+		e.trailing = append(e.trailing, dropTrigger, "DROP FUNCTION IF EXISTS "+function+"()")
+		return
+	}
+
+	var body strings.Builder
+	for _, assign := range trigger.Columns {
+		col := QuoteIdent(assign.Column.Name.O)
+		body.WriteString(" IF NEW IS DISTINCT FROM OLD AND NEW." + col + " IS NOT DISTINCT FROM OLD." + col +
+			" THEN NEW." + col + " := " + e.capture(func() { e.expr(assign.Expr) }) + "; END IF;")
+	}
+	e.trailing = append(e.trailing,
+		"CREATE OR REPLACE FUNCTION "+function+"() RETURNS trigger AS $$ BEGIN"+body.String()+" RETURN NEW; END $$ LANGUAGE plpgsql",
+		dropTrigger,
+		"CREATE TRIGGER "+QuoteIdent(trigger.Trigger)+" BEFORE UPDATE ON "+table+" FOR EACH ROW EXECUTE FUNCTION "+function+"()")
+}
+
+// addConstraintAction 输出 ALTER TABLE ADD 约束子句
+// 普通索引在 YSQL 中不是约束，转换为单独的 CREATE INDEX 语句追加到 extra
+func (e *Emitter) addConstraintAction(c *ast.Constraint, extra *[]string) {
+	switch c.Tp {
+	case ast.ConstraintKey, ast.ConstraintIndex:
+		*extra = append(*extra, e.capture(func() {
+			e.w("CREATE INDEX ")
+			e.name(c.Name)
+			e.w(" ON ")
+			e.tableName(e.table)
+			e.w(" (")
+			e.indexParts(c.Keys)
+			e.w(")")
+		}))
+	case ast.ConstraintFulltext:
+		e.addIssue("全文索引 %s 在 YSQL 中不支持，已省略，需要改用 tsvector + GIN 索引", c.Name)
+	default:
+		s := e.capture(func() { e.constraint(c) })
+		if s != "" {
+			e.w("ADD " + s)
+		}
+	}
+}
+
+// modifyColumnActions 将 MODIFY/CHANGE COLUMN 拆分为 YSQL 的 ALTER COLUMN 子句
+func (e *Emitter) modifyColumnActions(name string, col *ast.ColumnDef) []string {
+	prefix := "ALTER COLUMN " + QuoteIdent(name) + " "
+	actions := []string{prefix + "TYPE " + e.columnType(col)}
+	for _, opt := range col.Options {
+		switch opt.Tp {
+		case ast.ColumnOptionNotNull:
+			actions = append(actions, prefix+"SET NOT NULL")
+		case ast.ColumnOptionNull:
+			actions = append(actions, prefix+"DROP NOT NULL")
+		case ast.ColumnOptionDefaultValue:
+			actions = append(actions, prefix+"SET DEFAULT "+e.capture(func() { e.expr(opt.Expr) }))
+		case ast.ColumnOptionComment:
+			e.columnOption(col, opt)
+		default:
+			e.addIssue("MODIFY COLUMN %s 的列选项 %s 需要人工改写", name, columnOptionName(opt.Tp))
+		}
+	}
+	return actions
+}
+
+// dropTableStmt 输出 DROP TABLE/VIEW 语句
+func (e *Emitter) dropTableStmt(n *ast.DropTableStmt) {
+	if n.IsView {
+		e.w("DROP VIEW ")
+	} else {
+		e.w("DROP TABLE ")
+	}
+	if n.IfExists {
+		e.w("IF EXISTS ")
+	}
+	for i, t := range n.Tables {
+		if i > 0 {
+			e.w(", ")
+		}
+		e.tableName(t)
+	}
+}
+
+// renameTableStmt 将 RENAME TABLE 输出为 ALTER TABLE ... RENAME TO
+func (e *Emitter) renameTableStmt(n *ast.RenameTableStmt) {
+	for i, t2t := range n.TableToTables {
+		s := e.capture(func() {
+			e.w("ALTER TABLE ")
+			e.tableName(t2t.OldTable)
+			e.w(" RENAME TO ")
+			e.name(t2t.NewTable.Name.O)
+		})
+		if t2t.NewTable.Schema.O != "" && !strings.EqualFold(t2t.NewTable.Schema.O, t2t.OldTable.Schema.O) {
+			e.addIssue("RENAME TABLE 跨 schema 移动表 %s 需要额外执行 ALTER TABLE ... SET SCHEMA", t2t.OldTable.Name.O)
+		}
+		if i == 0 {
+			e.w(s)
+		} else {
+			e.trailing = append(e.trailing, s)
+		}
+	}
+}
+
+// createIndexStmt 输出 CREATE INDEX 语句
+func (e *Emitter) createIndexStmt(n *ast.CreateIndexStmt) {
+	switch n.KeyType {
+	case ast.IndexKeyTypeNone, ast.IndexKeyTypeUnique:
+	default:
+		e.unsupportedStmt(n, "全文/空间/向量索引")
+		return
+	}
+
+	e.w("CREATE ")
+	if n.KeyType == ast.IndexKeyTypeUnique {
+		e.w("UNIQUE ")
+	}
+	e.w("INDEX ")
+	if n.IfNotExists {
+		e.w("IF NOT EXISTS ")
+	}
+	e.name(n.IndexName)
+	e.w(" ON ")
+	e.tableName(n.Table)
+	e.w(" (")
+	e.indexParts(n.IndexPartSpecifications)
+	e.w(")")
+	if n.IndexOption != nil && n.IndexOption.Condition != nil {
+		e.w(" WHERE ")
+		e.expr(n.IndexOption.Condition)
+	}
+}
+
+// createViewStmt 输出 CREATE VIEW 语句
+func (e *Emitter) createViewStmt(n *ast.CreateViewStmt) {
+	e.w("CREATE ")
+	if n.OrReplace {
+		e.w("OR REPLACE ")
+	}
+	e.w("VIEW ")
+	e.tableName(n.ViewName)
+	if len(n.Cols) > 0 {
+		e.w(" (")
+		for i, col := range n.Cols {
+			if i > 0 {
+				e.w(",")
+			}
+			e.name(col.O)
+		}
+		e.w(")")
+	}
+	e.w(" AS ")
+	e.stmt(n.Select)
+}
+
+// fieldType 将 MySQL 列类型映射为 YSQL 类型
+// 按类型转换表输出，保留原类型的长度、精度与小数秒精度；
+// 转换表中没有的类型（未配置的 ENUM/SET/GEOMETRY 等）输出为 TEXT 并记录问题
+func (e *Emitter) fieldType(tp *types.FieldType) string {
+	if tp == nil {
+		return "TEXT"
+	}
+	if template, ok := e.hints.TypeMapping()[TypeName(tp)]; ok {
+		return FormatType(template, tp)
+	}
+
+	switch tp.GetType() {
+	case mysql.TypeEnum, mysql.TypeSet, mysql.TypeGeometry:
+		e.addIssue("%s 类型在 YSQL 中没有直接对应，已输出为 TEXT，需要人工确认", strings.ToUpper(types.TypeToStr(tp.GetType(), tp.GetCharset())))
+	default:
+		e.addIssue("未知的列类型 %s，已输出为 TEXT", tp.String())
+	}
+	return "TEXT"
+}
@@ -0,0 +1,891 @@
+// Package sqlemitter 将检查器转换后的 TiDB AST 输出为 PostgreSQL/YSQL 方言的 SQL 文本。
+// 与 TiDB 的 Restore 只能输出 MySQL 语法不同，本包直接生成目标数据库语法：
+// 双引号标识符、SERIAL、LIMIT ... OFFSET / OFFSET ... FETCH 等。
+// 无法输出的节点不会回退为 MySQL 文本，所在语句整体输出为注释并记录为问题。
+package sqlemitter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
+	"github.com/pingcap/tidb/pkg/parser/test_driver"
+
+	"github.com/example/ybMigration/internal/model"
+)
+
+// EmitterName 生成器在问题列表中使用的检查器名称
+const EmitterName = "SQLEmitter"
+
+// Emitter YSQL 方言 SQL 生成器
+//
+// 输出格式与 TiDB Restore 的紧凑风格保持一致（关键字大写、逗号后不加空格），
+// 便于与历史输出比对；标识符统一转为小写，仅在需要时使用双引号。
+//
+// 并发安全:
+//   - 该结构体不是并发安全的，每个 goroutine 应使用独立的实例
+type Emitter struct {
+	buf      *strings.Builder                     // 当前语句的输出缓冲
+	hints    *Hints                               // 检查器提供的输出提示
+	issues   []model.Issue                        // 输出过程中发现的问题
+	leading  []string                             // 需要输出在当前语句之前的语句（如 CREATE TYPE）
+	trailing []string                             // 需要追加在当前语句之后的语句（如 COMMENT ON）
+	params   map[*test_driver.ParamMarkerExpr]int // 参数占位符按原文顺序的编号
+	index    int                                  // 当前语句在 Emit 输入中的序号（从 1 开始）
+	table    *ast.TableName                       // 当前正在输出的 DDL 目标表
+	broken   bool                                 // 当前语句包含无法输出的节点，整条语句输出为注释
+}
+
+// NewEmitter 创建 YSQL 生成器
+func NewEmitter() *Emitter {
+	return &Emitter{buf: &strings.Builder{}}
+}
+
+// Emit 将语句列表输出为 YSQL 文本
+// 参数:
+//   - stmts: 转换后的 AST 语句列表，nil 语句会被跳过
+//   - hints: 检查器提供的输出提示，可以为 nil
+//
+// 返回:
+//   - string: 生成的 SQL，语句间用分号和换行符分隔
+//   - []model.Issue: 无法输出的节点等问题
+//   - error: AST 节点损坏导致输出失败时返回错误
+func (e *Emitter) Emit(stmts []ast.StmtNode, hints *Hints) (string, []model.Issue, error) {
+	e.hints = hints
+	e.issues = nil
+
+	sqlParts := make([]string, 0, len(stmts))
+	for i, stmt := range stmts {
+		if stmt == nil {
+			continue
+		}
+		e.index = i + 1
+		parts, err := e.EmitStmt(stmt)
+		if err != nil {
+			return "", e.issues, fmt.Errorf("生成第 %d 条语句失败: %w", i+1, err)
+		}
+		sqlParts = append(sqlParts, parts...)
+	}
+
+	return strings.Join(sqlParts, ";\n"), e.issues, nil
+}
+
+// EmitStmt 输出单条语句
+// 一条 MySQL 语句可能对应多条 YSQL 语句（如 CHANGE COLUMN 拆分出的 RENAME COLUMN），
+// 因此返回语句列表；完全由 MySQL 专有选项组成的语句返回空列表。
+// 参数:
+//   - stmt: AST 语句节点
+//
+// 返回:
+//   - []string: 生成的 SQL 语句（不含结尾分号）
+//   - error: 输出过程中发生 panic 时返回错误
+func (e *Emitter) EmitStmt(stmt ast.StmtNode) (parts []string, err error) {
+	e.buf = &strings.Builder{}
+	e.leading = nil
+	e.trailing = nil
+	e.params = paramOrder(stmt)
+	e.table = nil
+	e.broken = false
+
+	defer func() {
+		if r := recover(); r != nil {
+			parts = nil
+			err = fmt.Errorf("输出 %T 时发生错误: %v", stmt, r)
+		}
+	}()
+
+	e.stmt(stmt)
+	if e.broken {
+		// 语句中有无法输出的节点，输出部分内容会得到语法错误或语义不同的 SQL
+		e.leading, e.trailing = nil, nil
+		e.commentOut(stmt)
+	}
+
+	parts = append(parts, e.leading...)
+	if s := e.buf.String(); s != "" {
+		parts = append(parts, s)
+	}
+	return append(parts, e.trailing...), nil
+}
+
+// Issues 返回最近一次 Emit 发现的问题
+func (e *Emitter) Issues() []model.Issue {
+	return e.issues
+}
+
+// stmt 按语句类型分派输出
+func (e *Emitter) stmt(node ast.StmtNode) {
+	switch n := node.(type) {
+	case *ast.SelectStmt:
+		e.selectStmt(n)
+	case *ast.SetOprStmt:
+		e.setOprStmt(n)
+	case *ast.InsertStmt:
+		e.insertStmt(n)
+	case *ast.UpdateStmt:
+		e.updateStmt(n)
+	case *ast.DeleteStmt:
+		e.deleteStmt(n)
+	case *ast.CreateTableStmt:
+		e.createTableStmt(n)
+	case *ast.AlterTableStmt:
+		e.alterTableStmt(n)
+	case *ast.DropTableStmt:
+		e.dropTableStmt(n)
+	case *ast.TruncateTableStmt:
+		e.w("TRUNCATE TABLE ")
+		e.tableName(n.Table)
+	case *ast.RenameTableStmt:
+		e.renameTableStmt(n)
+	case *ast.CreateIndexStmt:
+		e.createIndexStmt(n)
+	case *ast.DropIndexStmt:
+		e.w("DROP INDEX ")
+		if n.IfExists {
+			e.w("IF EXISTS ")
+		}
+		e.indexName(n.Table, n.IndexName)
+	case *ast.CreateViewStmt:
+		e.createViewStmt(n)
+	case *ast.CreateDatabaseStmt:
+		// MySQL 的 database 对应 YSQL 的 schema
+		e.w("CREATE SCHEMA ")
+		if n.IfNotExists {
+			e.w("IF NOT EXISTS ")
+		}
+		e.name(n.Name.O)
+	case *ast.DropDatabaseStmt:
+		// DROP DATABASE 会删除库内所有对象，需要 CASCADE 保持语义
+		e.w("DROP SCHEMA ")
+		if n.IfExists {
+			e.w("IF EXISTS ")
+		}
+		e.name(n.Name.O)
+		e.w(" CASCADE")
+	case *ast.UseStmt:
+		e.w("SET search_path TO ")
+		e.name(n.DBName)
+	case *ast.BeginStmt:
+		e.w("BEGIN")
+		if n.ReadOnly {
+			e.w(" READ ONLY")
+		}
+	case *ast.CommitStmt:
+		e.w("COMMIT")
+	case *ast.RollbackStmt:
+		e.w("ROLLBACK")
+		if n.SavepointName != "" {
+			e.w(" TO SAVEPOINT ")
+			e.name(n.SavepointName)
+		}
+	case *ast.SavepointStmt:
+		e.w("SAVEPOINT ")
+		e.name(n.Name)
+	case *ast.ReleaseSavepointStmt:
+		e.w("RELEASE SAVEPOINT ")
+		e.name(n.Name)
+	case *ast.ExplainStmt:
+		e.w("EXPLAIN ")
+		if n.Analyze {
+			e.w("ANALYZE ")
+		}
+		e.stmt(n.Stmt)
+	case *ast.SetStmt:
+		e.setStmt(n)
+	default:
+		e.unsupportedStmt(node, "")
+	}
+}
+
+// selectStmt 输出 SELECT 语句
+func (e *Emitter) selectStmt(n *ast.SelectStmt) {
+	if n.IsInBraces {
+		e.w("(")
+		defer e.w(")")
+	}
+	if n.With != nil {
+		e.with(n.With)
+	}
+
+	switch n.Kind {
+	case ast.SelectStmtKindValues:
+		e.w("VALUES ")
+		for i, row := range n.Lists {
+			if i > 0 {
+				e.w(",")
+			}
+			e.w("(")
+			e.exprList(row.Values, ",")
+			e.w(")")
+		}
+		e.selectTail(n)
+		return
+	case ast.SelectStmtKindTable:
+		e.w("TABLE ")
+		e.resultSet(n.From.TableRefs)
+		e.selectTail(n)
+		return
+	}
+
+	e.w("SELECT ")
+	if n.Distinct {
+		e.w("DISTINCT ")
+	}
+	if n.SelectStmtOpts != nil && n.SelectStmtOpts.CalcFoundRows {
+		e.addIssue("SQL_CALC_FOUND_ROWS 在 YSQL 中不存在，已省略，需要改用 COUNT(*) OVER () 或单独的计数查询")
+	}
+	if n.Fields != nil {
+		for i, field := range n.Fields.Fields {
+			if i > 0 {
+				e.w(",")
+			}
+			e.selectField(field)
+		}
+	}
+	if n.From != nil && n.From.TableRefs != nil {
+		e.w(" FROM ")
+		e.resultSet(n.From.TableRefs)
+	}
+	if n.Where != nil {
+		e.w(" WHERE ")
+		e.expr(n.Where)
+	}
+	if n.GroupBy != nil {
+		e.w(" GROUP BY ")
+		if n.GroupBy.Rollup {
+			e.w("ROLLUP (")
+			e.byItems(n.GroupBy.Items)
+			e.w(")")
+		} else {
+			e.byItems(n.GroupBy.Items)
+		}
+	}
+	if n.Having != nil {
+		e.w(" HAVING ")
+		e.expr(n.Having.Expr)
+	}
+	if len(n.WindowSpecs) > 0 {
+		e.w(" WINDOW ")
+		for i := range n.WindowSpecs {
+			if i > 0 {
+				e.w(",")
+			}
+			spec := &n.WindowSpecs[i]
+			e.name(spec.Name.O)
+			e.w(" AS ")
+			e.windowSpec(spec)
+		}
+	}
+	e.selectTail(n)
+	if n.SelectIntoOpt != nil {
+		e.addIssue("SELECT ... INTO OUTFILE/DUMPFILE/变量 在 YSQL 中不支持，已省略，需要改用 COPY 或 PL/pgSQL")
+	}
+}
+
+// selectTail 输出 SELECT 的 ORDER BY、LIMIT 与锁定子句
+func (e *Emitter) selectTail(n *ast.SelectStmt) {
+	if n.OrderBy != nil {
+		e.w(" ORDER BY ")
+		e.byItems(n.OrderBy.Items)
+	}
+	if n.Limit != nil {
+		e.limit(n.Limit)
+	}
+	if n.LockInfo != nil {
+		e.lockInfo(n.LockInfo)
+	}
+}
+
+// selectField 输出 SELECT 字段
+func (e *Emitter) selectField(field *ast.SelectField) {
+	if field.WildCard != nil {
+		if field.WildCard.Schema.O != "" {
+			e.name(field.WildCard.Schema.O)
+			e.w(".")
+		}
+		if field.WildCard.Table.O != "" {
+			e.name(field.WildCard.Table.O)
+			e.w(".")
+		}
+		e.w("*")
+		return
+	}
+	e.expr(field.Expr)
+	if field.AsName.O != "" {
+		e.w(" AS ")
+		e.name(field.AsName.O)
+	}
+}
+
+// with 输出 WITH 子句
+func (e *Emitter) with(n *ast.WithClause) {
+	e.w("WITH ")
+	if n.IsRecursive {
+		e.w("RECURSIVE ")
+	}
+	for i, cte := range n.CTEs {
+		if i > 0 {
+			e.w(", ")
+		}
+		e.name(cte.Name.O)
+		if len(cte.ColNameList) > 0 {
+			e.w(" (")
+			for j, col := range cte.ColNameList {
+				if j > 0 {
+					e.w(", ")
+				}
+				e.name(col.O)
+			}
+			e.w(")")
+		}
+		e.w(" AS ")
+		e.expr(cte.Query)
+	}
+	e.w(" ")
+}
+
+// setOprStmt 输出 UNION/EXCEPT/INTERSECT 语句
+func (e *Emitter) setOprStmt(n *ast.SetOprStmt) {
+	if n.IsInBraces {
+		e.w("(")
+		defer e.w(")")
+	}
+	if n.With != nil {
+		e.with(n.With)
+	}
+	e.setOprSelectList(n.SelectList)
+	if n.OrderBy != nil {
+		e.w(" ORDER BY ")
+		e.byItems(n.OrderBy.Items)
+	}
+	if n.Limit != nil {
+		e.limit(n.Limit)
+	}
+}
+
+// setOprSelectList 输出集合运算的各个分支
+func (e *Emitter) setOprSelectList(list *ast.SetOprSelectList) {
+	if list == nil {
+		return
+	}
+	for i, sel := range list.Selects {
+		switch s := sel.(type) {
+		case *ast.SelectStmt:
+			if i > 0 && s.AfterSetOperator != nil {
+				e.w(" " + s.AfterSetOperator.String() + " ")
+			}
+			e.selectStmt(s)
+		case *ast.SetOprSelectList:
+			if i > 0 && s.AfterSetOperator != nil {
+				e.w(" " + s.AfterSetOperator.String() + " ")
+			}
+			e.w("(")
+			e.setOprSelectList(s)
+			e.w(")")
+		}
+	}
+}
+
+// limit 输出 LIMIT 子句
+// 默认输出 LIMIT n OFFSET m；检查器通过 Hints 标记时输出 SQL 标准的 OFFSET ... FETCH 形式
+func (e *Emitter) limit(n *ast.Limit) {
+	if e.hints.OffsetFetch(n) {
+		if n.Offset != nil {
+			e.w(" OFFSET ")
+			e.expr(n.Offset)
+			e.w(" ROWS")
+		}
+		if n.Count != nil {
+			e.w(" FETCH NEXT ")
+			e.expr(n.Count)
+			e.w(" ROWS ONLY")
+		}
+		return
+	}
+	if n.Count != nil {
+		e.w(" LIMIT ")
+		e.expr(n.Count)
+	}
+	if n.Offset != nil {
+		e.w(" OFFSET ")
+		e.expr(n.Offset)
+	}
+}
+
+// lockInfo 输出 FOR UPDATE / FOR SHARE 子句
+func (e *Emitter) lockInfo(n *ast.SelectLockInfo) {
+	switch n.LockType {
+	case ast.SelectLockNone:
+		return
+	case ast.SelectLockForShare, ast.SelectLockForShareNoWait, ast.SelectLockForShareSkipLocked:
+		e.w(" FOR SHARE")
+	default:
+		e.w(" FOR UPDATE")
+	}
+	if len(n.Tables) > 0 {
+		e.w(" OF ")
+		for i, t := range n.Tables {
+			if i > 0 {
+				e.w(", ")
+			}
+			e.tableName(t)
+		}
+	}
+	switch n.LockType {
+	case ast.SelectLockForUpdateNoWait, ast.SelectLockForShareNoWait:
+		e.w(" NOWAIT")
+	case ast.SelectLockForUpdateSkipLocked, ast.SelectLockForShareSkipLocked:
+		e.w(" SKIP LOCKED")
+	case ast.SelectLockForUpdateWaitN:
+		e.addIssue("FOR UPDATE WAIT %d 在 YSQL 中不支持，已省略等待时间，可改用 lock_timeout", n.WaitSec)
+	}
+}
+
+// byItems 输出 GROUP BY / ORDER BY 项
+func (e *Emitter) byItems(items []*ast.ByItem) {
+	for i, item := range items {
+		if i > 0 {
+			e.w(",")
+		}
+		e.expr(item.Expr)
+		if item.Desc {
+			e.w(" DESC")
+		}
+	}
+}
+
+// resultSet 输出 FROM 子句中的表引用
+func (e *Emitter) resultSet(node ast.ResultSetNode) {
+	switch n := node.(type) {
+	case *ast.Join:
+		e.join(n)
+	case *ast.TableSource:
+		e.tableSource(n)
+	case *ast.TableName:
+		e.tableName(n)
+	case *ast.SelectStmt:
+		e.selectStmt(n)
+	case *ast.SetOprStmt:
+		e.setOprStmt(n)
+	default:
+		e.unsupportedNode(node)
+	}
+}
+
+// join 输出 JOIN 表达式
+func (e *Emitter) join(n *ast.Join) {
+	if n.ExplicitParens && n.Right != nil {
+		e.w("(")
+		defer e.w(")")
+	}
+	e.resultSet(n.Left)
+	if n.Right == nil {
+		return
+	}
+
+	switch {
+	case n.NaturalJoin && n.Tp == ast.LeftJoin:
+		e.w(" NATURAL LEFT JOIN ")
+	case n.NaturalJoin && n.Tp == ast.RightJoin:
+		e.w(" NATURAL RIGHT JOIN ")
+	case n.NaturalJoin:
+		e.w(" NATURAL JOIN ")
+	case n.Tp == ast.LeftJoin:
+		e.w(" LEFT JOIN ")
+	case n.Tp == ast.RightJoin:
+		e.w(" RIGHT JOIN ")
+	case n.On == nil && len(n.Using) == 0:
+		// 逗号连接与无条件 JOIN 在 YSQL 中必须写成 CROSS JOIN
+		e.w(" CROSS JOIN ")
+	default:
+		e.w(" JOIN ")
+	}
+
+	if right, ok := n.Right.(*ast.Join); ok && right.Right != nil && !right.ExplicitParens {
+		e.w("(")
+		e.join(right)
+		e.w(")")
+	} else {
+		e.resultSet(n.Right)
+	}
+
+	if n.On != nil {
+		e.w(" ON ")
+		e.expr(n.On.Expr)
+	}
+	if len(n.Using) > 0 {
+		e.w(" USING (")
+		for i, col := range n.Using {
+			if i > 0 {
+				e.w(",")
+			}
+			e.name(col.Name.O)
+		}
+		e.w(")")
+	}
+}
+
+// tableSource 输出带别名的表引用或派生表
+func (e *Emitter) tableSource(n *ast.TableSource) {
+	switch src := n.Source.(type) {
+	case *ast.SelectStmt:
+		if !src.IsInBraces {
+			e.w("(")
+			e.selectStmt(src)
+			e.w(")")
+		} else {
+			e.selectStmt(src)
+		}
+	case *ast.SetOprStmt:
+		e.w("(")
+		e.setOprStmt(src)
+		e.w(")")
+	case *ast.Join:
+		e.w("(")
+		e.join(src)
+		e.w(")")
+	default:
+		e.resultSet(n.Source)
+	}
+	if n.AsName.O != "" {
+		e.w(" AS ")
+		e.name(n.AsName.O)
+	}
+}
+
+// tableName 输出表名（含 schema 前缀）
+func (e *Emitter) tableName(n *ast.TableName) {
+	if n == nil {
+		return
+	}
+	if n.Schema.O != "" {
+		e.name(n.Schema.O)
+		e.w(".")
+	}
+	e.name(n.Name.O)
+	if len(n.PartitionNames) > 0 {
+		e.addIssue("表 %s 的分区选择 PARTITION (...) 在 YSQL 中不支持，已省略", n.Name.O)
+	}
+}
+
+// insertStmt 输出 INSERT/REPLACE 语句
+// REPLACE INTO 与 ON DUPLICATE KEY UPDATE 需要检查器确定 ON CONFLICT 的冲突目标，
+// 未确定时输出为普通 INSERT 会在冲突时报错或写入不同的结果，因此整条语句输出为注释。
+func (e *Emitter) insertStmt(n *ast.InsertStmt) {
+	if _, ok := e.hints.OnConflict(n); !ok {
+		switch {
+		case n.IsReplace:
+			e.commentedStmt(n, "REPLACE INTO 的冲突目标未确定，语句已输出为注释，需要改写为 INSERT ... ON CONFLICT (...) DO UPDATE")
+			return
+		case len(n.OnDuplicate) > 0:
+			e.commentedStmt(n, "ON DUPLICATE KEY UPDATE 的冲突目标未确定，语句已输出为注释，需要改写为 INSERT ... ON CONFLICT (...) DO UPDATE")
+			return
+		}
+	}
+	if n.IgnoreErr {
+		e.addIssue("INSERT IGNORE 在 YSQL 中不存在，已输出为普通 INSERT，需要改写为 INSERT ... ON CONFLICT DO NOTHING")
+	}
+
+	e.w("INSERT INTO ")
+	if n.Table != nil {
+		e.resultSet(n.Table.TableRefs)
+	}
+	if len(n.PartitionNames) > 0 {
+		e.addIssue("INSERT 的分区选择 PARTITION (...) 在 YSQL 中不支持，已省略")
+	}
+	if len(n.Columns) > 0 {
+		e.w(" (")
+		for i, col := range n.Columns {
+			if i > 0 {
+				e.w(",")
+			}
+			e.name(col.Name.O)
+		}
+		e.w(")")
+	}
+
+	switch {
+	case n.Select != nil:
+		e.w(" ")
+		e.resultSet(n.Select)
+	case len(n.Lists) == 1 && len(n.Lists[0]) == 0:
+		e.w(" DEFAULT VALUES")
+	case len(n.Lists) > 0:
+		e.w(" VALUES ")
+		for i, row := range n.Lists {
+			if i > 0 {
+				e.w(",")
+			}
+			e.w("(")
+			e.exprList(row, ",")
+			e.w(")")
+		}
+	}
+
+	if clause, ok := e.hints.OnConflict(n); ok {
+		e.onConflict(n, clause)
+	}
+}
+
+// onConflict 输出检查器确定的 ON CONFLICT 子句
+// DO UPDATE SET 的赋值取自 ON DUPLICATE KEY UPDATE，其中的 VALUES(col) 输出为 EXCLUDED.col
+func (e *Emitter) onConflict(n *ast.InsertStmt, clause OnConflict) {
+	e.w(" ON CONFLICT")
+	if len(clause.Columns) > 0 {
+		e.w(" (")
+		for i, col := range clause.Columns {
+			if i > 0 {
+				e.w(",")
+			}
+			e.name(col)
+		}
+		e.w(")")
+	}
+	if clause.DoNothing || len(n.OnDuplicate) == 0 {
+		e.w(" DO NOTHING")
+		return
+	}
+	e.w(" DO UPDATE SET ")
+	for i, assign := range n.OnDuplicate {
+		if i > 0 {
+			e.w(",")
+		}
+		e.name(assign.Column.Name.O)
+		e.w("=")
+		e.expr(assign.Expr)
+	}
+}
+
+// updateStmt 输出 UPDATE 语句
+func (e *Emitter) updateStmt(n *ast.UpdateStmt) {
+	if joined, ok := e.hints.JoinedDML(n); ok {
+		e.joinedUpdate(n, joined)
+		return
+	}
+	if n.MultipleTable || isJoined(n.TableRefs) {
+		e.unsupportedStmt(n, "多表 UPDATE")
+		return
+	}
+
+	e.w("UPDATE ")
+	e.resultSet(n.TableRefs.TableRefs)
+	e.w(" SET ")
+	for i, assign := range n.List {
+		if i > 0 {
+			e.w(",")
+		}
+		// YSQL 的 SET 目标列不能带表名前缀
+		e.name(assign.Column.Name.O)
+		e.w("=")
+		e.expr(assign.Expr)
+	}
+	if n.Where != nil {
+		e.w(" WHERE ")
+		e.expr(n.Where)
+	}
+	if n.Order != nil || n.Limit != nil {
+		e.addIssue("UPDATE 的 ORDER BY/LIMIT 在 YSQL 中不支持，已省略，需要改写为基于主键子查询的 UPDATE")
+	}
+}
+
+// deleteStmt 输出 DELETE 语句
+func (e *Emitter) deleteStmt(n *ast.DeleteStmt) {
+	if joined, ok := e.hints.JoinedDML(n); ok {
+		e.joinedDelete(n, joined)
+		return
+	}
+	if n.IsMultiTable || isJoined(n.TableRefs) {
+		e.unsupportedStmt(n, "多表 DELETE")
+		return
+	}
+
+	e.w("DELETE FROM ")
+	e.resultSet(n.TableRefs.TableRefs)
+	if n.Where != nil {
+		e.w(" WHERE ")
+		e.expr(n.Where)
+	}
+	if n.Order != nil || n.Limit != nil {
+		e.addIssue("DELETE 的 ORDER BY/LIMIT 在 YSQL 中不支持，已省略，需要改写为基于主键子查询的 DELETE")
+	}
+}
+
+// joinedUpdate 将多表 UPDATE 输出为 UPDATE ... SET ... FROM ... WHERE
+func (e *Emitter) joinedUpdate(n *ast.UpdateStmt, joined JoinedDML) {
+	e.w("UPDATE ")
+	e.tableSource(joined.Target)
+	e.w(" SET ")
+	for i, assign := range n.List {
+		if i > 0 {
+			e.w(",")
+		}
+		e.name(assign.Column.Name.O)
+		e.w("=")
+		e.expr(assign.Expr)
+	}
+	e.joinedTables(" FROM ", joined.Tables)
+	e.joinedWhere(n.Where, joined.Conditions)
+}
+
+// joinedDelete 将多表 DELETE 输出为 DELETE FROM ... USING ... WHERE
+func (e *Emitter) joinedDelete(n *ast.DeleteStmt, joined JoinedDML) {
+	e.w("DELETE FROM ")
+	e.tableSource(joined.Target)
+	e.joinedTables(" USING ", joined.Tables)
+	e.joinedWhere(n.Where, joined.Conditions)
+}
+
+// joinedTables 输出 UPDATE ... FROM、DELETE ... USING 中目标表以外的表
+func (e *Emitter) joinedTables(keyword string, tables []*ast.TableSource) {
+	if len(tables) == 0 {
+		return
+	}
+	e.w(keyword)
+	for i, table := range tables {
+		if i > 0 {
+			e.w(",")
+		}
+		e.tableSource(table)
+	}
+}
+
+// joinedWhere 输出连接条件与 WHERE 条件合并后的 WHERE 子句
+// OR、XOR 条件加括号，避免与 AND 合并后改变优先级
+func (e *Emitter) joinedWhere(where ast.ExprNode, conditions []*ast.OnCondition) {
+	exprs := make([]ast.ExprNode, 0, len(conditions)+1)
+	for _, cond := range conditions {
+		exprs = append(exprs, cond.Expr)
+	}
+	if where != nil {
+		exprs = append(exprs, where)
+	}
+	for i, expr := range exprs {
+		if i == 0 {
+			e.w(" WHERE ")
+		} else {
+			e.w(" AND ")
+		}
+		if op, ok := expr.(*ast.BinaryOperationExpr); ok && (op.Op == opcode.LogicOr || op.Op == opcode.LogicXor) {
+			e.w("(")
+			e.expr(expr)
+			e.w(")")
+			continue
+		}
+		e.expr(expr)
+	}
+}
+
+// isJoined 判断表引用是否包含多表连接
+func isJoined(refs *ast.TableRefsClause) bool {
+	return refs != nil && refs.TableRefs != nil && refs.TableRefs.Right != nil
+}
+
+// setStmt 输出 SET 语句
+// 仅 SET NAMES 有直接对应（client_encoding），其余 MySQL 会话变量需要人工处理。
+// 输入在分析前已转换为 UTF-8，转换后的 SQL 也是 UTF-8，因此无论 SET NAMES 声明的字符集是什么，
+// 客户端编码都设置为 UTF8，如 SET NAMES gbk 输出为 SET client_encoding TO 'UTF8'。
+func (e *Emitter) setStmt(n *ast.SetStmt) {
+	if len(n.Variables) == 1 && n.Variables[0].Name == ast.SetNames {
+		e.w("SET client_encoding TO ")
+		e.stringLiteral("UTF8")
+		return
+	}
+	e.unsupportedStmt(n, "SET 会话变量")
+}
+
+// ============================================================================
+// 输出辅助函数
+// ============================================================================
+
+// w 写入文本
+func (e *Emitter) w(s string) {
+	e.buf.WriteString(s)
+}
+
+// name 写入标识符
+func (e *Emitter) name(s string) {
+	e.buf.WriteString(QuoteIdent(s))
+}
+
+// capture 在独立缓冲中执行输出并返回结果
+func (e *Emitter) capture(fn func()) string {
+	saved := e.buf
+	e.buf = &strings.Builder{}
+	fn()
+	s := e.buf.String()
+	e.buf = saved
+	return s
+}
+
+// addIssue 记录输出问题
+func (e *Emitter) addIssue(format string, args ...any) {
+	e.issues = append(e.issues, model.Issue{
+		Checker:        EmitterName,
+		Message:        fmt.Sprintf(format, args...),
+		StatementIndex: e.index,
+	})
+}
+
+// unsupportedStmt 以注释占位输出无法转换的语句并记录问题
+// 参数:
+//   - node: 无法输出的语句
+//   - what: 语句描述，为空时使用节点类型名
+func (e *Emitter) unsupportedStmt(node ast.StmtNode, what string) {
+	if what == "" {
+		what = nodeTypeName(node)
+	}
+	e.commentedStmt(node, fmt.Sprintf("YSQL 不支持的语句 %s，已输出为注释，需要人工改写", what))
+}
+
+// commentedStmt 将整条语句的原文输出为注释并记录问题
+// 用于无法输出为语义相同的 YSQL 语句的情况，避免输出可以执行但结果不同的 SQL
+func (e *Emitter) commentedStmt(node ast.StmtNode, message string) {
+	e.addIssue("%s", message)
+	e.commentOut(node)
+}
+
+// commentOut 以语句原文的注释替换当前语句的输出
+func (e *Emitter) commentOut(node ast.StmtNode) {
+	text := strings.TrimRight(strings.TrimSpace(node.Text()), ";")
+	if text == "" {
+		text = nodeTypeName(node)
+	}
+	e.buf.Reset()
+	e.w(CommentOut(text))
+}
+
+// CommentOut 将无法转换的语句原文输出为注释
+// 每行使用 -- 行注释：原文中可能包含 */ 或嵌套的 /*（如存储过程体），块注释无法可靠地包住任意文本
+// 参数:
+//   - text: 语句原文
+//
+// 返回:
+//   - string: 以 "-- YSQL 不支持: " 开头的注释文本
+func CommentOut(text string) string {
+	var sb strings.Builder
+	for i, line := range strings.Split(text, "\n") {
+		if i == 0 {
+			sb.WriteString("-- YSQL 不支持: ")
+		} else {
+			sb.WriteString("\n-- ")
+		}
+		sb.WriteString(strings.TrimRight(line, "\r"))
+	}
+	return sb.String()
+}
+
+// unsupportedNode 记录无法转换的子节点，所在语句在输出完成后整体替换为注释
+func (e *Emitter) unsupportedNode(node ast.Node) {
+	e.addIssue("YSQL 不支持的语法节点 %s，所在语句已输出为注释，需要人工改写", nodeTypeName(node))
+	e.broken = true
+}
+
+// nodeTypeName 返回节点的类型名（去掉包前缀）
+func nodeTypeName(node ast.Node) string {
+	name := fmt.Sprintf("%T", node)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return strings.TrimPrefix(name, "*")
+}
@@ -0,0 +1,358 @@
+package sqlemitter
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sqlparser "github.com/example/ybMigration/internal/sql-parser"
+)
+
+// parse 解析测试 SQL
+func parse(t *testing.T, sql string) []ast.StmtNode {
+	t.Helper()
+	stmts, err := sqlparser.NewSQLParser().ParseSQL(sql)
+	require.NoError(t, err)
+	return stmts
+}
+
+// TestEmit 测试常见语句的 YSQL 输出
+func TestEmit(t *testing.T) {
+	tests := []struct {
+		name        string
+		sql         string
+		expected    string
+		expectIssue bool
+		issueText   string // 非空时要求第一条问题描述包含该文本
+	}{
+		{
+			name:     "标识符转为小写并去除反引号",
+			sql:      "SELECT `Name` FROM `Users` WHERE `ID` = 1",
+			expected: "SELECT name FROM users WHERE id=1",
+		},
+		{
+			name:     "保留字标识符使用双引号",
+			sql:      "SELECT `user`, `order` FROM t",
+			expected: `SELECT "user","order" FROM t`,
+		},
+		{
+			name:     "LIMIT 偏移量语法",
+			sql:      "SELECT * FROM t LIMIT 10, 20",
+			expected: "SELECT * FROM t LIMIT 20 OFFSET 10",
+		},
+		{
+			name:     "字符串中的单引号",
+			sql:      "SELECT 'it''s'",
+			expected: "SELECT 'it''s'",
+		},
+		{
+			name:     "IF 函数转为 CASE",
+			sql:      "SELECT IF(a > 1, 'x', 'y') FROM t",
+			expected: "SELECT CASE WHEN a>1 THEN 'x' ELSE 'y' END FROM t",
+		},
+		{
+			name:     "参数占位符编号",
+			sql:      "SELECT * FROM t WHERE a = ? AND b = ?",
+			expected: "SELECT * FROM t WHERE a=$1 AND b=$2",
+		},
+		{
+			name:     "参数占位符按原文顺序编号",
+			sql:      "SELECT * FROM t WHERE a = ? LIMIT ?, ?",
+			expected: "SELECT * FROM t WHERE a=$1 LIMIT $3 OFFSET $2",
+		},
+		{
+			name:     "列注释拆分为 COMMENT ON",
+			sql:      "CREATE TABLE t (id INT NOT NULL COMMENT 'pk')",
+			expected: "CREATE TABLE t (id INTEGER NOT NULL);\nCOMMENT ON COLUMN t.id IS 'pk'",
+		},
+		{
+			name:     "UNSIGNED 整数扩宽为下一级类型",
+			sql:      "CREATE TABLE t (a SMALLINT UNSIGNED, b INT(10) UNSIGNED ZEROFILL, c BIGINT UNSIGNED)",
+			expected: "CREATE TABLE t (a INTEGER,b BIGINT,c NUMERIC(20))",
+		},
+		{
+			name: "类型转换表保留长度与精度",
+			sql: "CREATE TABLE t (a DECIMAL(18,4), b DECIMAL, c DATETIME(3), d DATETIME, e VARCHAR(255), f CHAR, " +
+				"g MEDIUMBLOB, h LONGTEXT, i VARBINARY(16), j BIT(1), k BIT(8), l JSON, m YEAR, n MEDIUMINT, o DOUBLE)",
+			expected: "CREATE TABLE t (a NUMERIC(18,4),b NUMERIC(10),c TIMESTAMP(3),d TIMESTAMP(0),e VARCHAR(255),f CHAR(1)," +
+				"g BYTEA,h TEXT,i BYTEA,j BOOLEAN,k BIT(8),l JSONB,m SMALLINT,n INTEGER,o DOUBLE PRECISION)",
+		},
+		{
+			name:     "USE 转为 search_path",
+			sql:      "USE shop",
+			expected: "SET search_path TO shop",
+		},
+		{
+			name:        "未确定冲突目标的 REPLACE INTO 输出为注释",
+			sql:         "REPLACE INTO t (id, name) VALUES (1, 'a')",
+			expected:    "-- YSQL 不支持: REPLACE INTO t (id, name) VALUES (1, 'a')",
+			expectIssue: true,
+		},
+		{
+			name:        "未确定冲突目标的 ON DUPLICATE KEY UPDATE 输出为注释",
+			sql:         "INSERT INTO t (id, name) VALUES (1, 'a') ON DUPLICATE KEY UPDATE name = VALUES(name)",
+			expected:    "-- YSQL 不支持: INSERT INTO t (id, name) VALUES (1, 'a') ON DUPLICATE KEY UPDATE name = VALUES(name)",
+			expectIssue: true,
+		},
+		{
+			name:        "包含 MySQL 变量的语句整体输出为注释",
+			sql:         "SELECT @x := 1, @@session.sql_mode",
+			expected:    "-- YSQL 不支持: SELECT @x := 1, @@session.sql_mode",
+			expectIssue: true,
+		},
+		{
+			name:        "存储过程逐行输出为行注释",
+			sql:         "CREATE PROCEDURE p()\nBEGIN\n  /* done */ SELECT 1;\nEND",
+			expected:    "-- YSQL 不支持: CREATE PROCEDURE p()\n-- BEGIN\n--   /* done */ SELECT 1;\n-- END",
+			expectIssue: true,
+		},
+		{
+			name:     "位串与十六进制字面量",
+			sql:      "SELECT b'101', 0x1F, x'0a'",
+			expected: `SELECT B'101','\x1f'::BYTEA,'\x0a'::BYTEA`,
+		},
+		{
+			name:     "DATE_FORMAT 与 STR_TO_DATE 转为 TO_CHAR/TO_DATE/TO_TIMESTAMP",
+			sql:      "SELECT DATE_FORMAT(created_at, '%Y-%m-%d %H:%i:%s'), DATE_FORMAT('2024-01-02', '%Y年%c月 at %T'), STR_TO_DATE(s, '%d/%m/%Y'), STR_TO_DATE(s, '%Y%m%d %H%i') FROM t",
+			expected: `SELECT TO_CHAR(created_at, 'YYYY-MM-DD HH24:MI:SS'),TO_CHAR('2024-01-02'::TIMESTAMP, 'YYYY"年"FMMM"月 at "HH24:MI:SS'),TO_DATE(s, 'DD/MM/YYYY'),TO_TIMESTAMP(s, 'YYYYMMDD HH24MI')::TIMESTAMP FROM t`,
+		},
+		{
+			name:     "UNIX_TIMESTAMP 与 FROM_UNIXTIME 转为 EPOCH 运算",
+			sql:      "SELECT UNIX_TIMESTAMP(), UNIX_TIMESTAMP(created_at), FROM_UNIXTIME(ts), FROM_UNIXTIME(ts, '%Y-%m-%d') FROM t",
+			expected: "SELECT FLOOR(EXTRACT(EPOCH FROM CURRENT_TIMESTAMP))::BIGINT,FLOOR(EXTRACT(EPOCH FROM (created_at)::TIMESTAMPTZ))::BIGINT,TO_TIMESTAMP(ts)::TIMESTAMP,TO_CHAR(TO_TIMESTAMP(ts), 'YYYY-MM-DD') FROM t",
+		},
+		{
+			name:     "JSON_EXTRACT 与 -> / ->> 转为路径运算符",
+			sql:      `SELECT JSON_EXTRACT(doc, '$.a.b[0]'), doc->'$."x y"', doc->>'$.name' FROM t WHERE JSON_EXTRACT('{"a":1}', '$.a') = 1`,
+			expected: `SELECT (doc #> '{a,b,0}'),(doc #> '{"x y"}'),(doc #>> '{name}') FROM t WHERE (('{"a":1}')::JSONB #> '{a}')=1`,
+		},
+		{
+			name:        "无法转换的日期格式符原样输出并记录问题",
+			sql:         "SELECT DATE_FORMAT(d, '%w') FROM t",
+			expected:    "SELECT DATE_FORMAT(d, '%w') FROM t",
+			expectIssue: true,
+			issueText:   "%w",
+		},
+		{
+			name:        "JSON 路径通配符原样输出并记录问题",
+			sql:         "SELECT doc->>'$[*].id' FROM t",
+			expected:    "SELECT JSON_UNQUOTE(JSON_EXTRACT(doc, '$[*].id')) FROM t",
+			expectIssue: true,
+			issueText:   "$[*].id",
+		},
+		{
+			name:        "没有对应写法的 MySQL 函数原样输出并记录问题",
+			sql:         "SELECT FIND_IN_SET('a', tags) FROM t",
+			expected:    "SELECT FIND_IN_SET('a', tags) FROM t",
+			expectIssue: true,
+			issueText:   "FIND_IN_SET",
+		},
+		{
+			name:        "不支持的 ALTER TABLE 子句在问题中给出原文",
+			sql:         "ALTER TABLE t DISABLE KEYS",
+			expected:    "",
+			expectIssue: true,
+			issueText:   "DISABLE KEYS",
+		},
+		{
+			name:     "SET NAMES 转为 UTF8 客户端编码",
+			sql:      "SET NAMES gbk",
+			expected: "SET client_encoding TO 'UTF8'",
+		},
+		{
+			name:        "不支持的语句输出为注释",
+			sql:         "LOCK TABLES t WRITE",
+			expected:    "-- YSQL 不支持: LOCK TABLES t WRITE",
+			expectIssue: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, issues, err := NewEmitter().Emit(parse(t, tt.sql), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sql)
+			if tt.expectIssue {
+				require.NotEmpty(t, issues)
+				assert.Equal(t, EmitterName, issues[0].Checker)
+				assert.Contains(t, issues[0].Message, tt.issueText)
+			} else {
+				assert.Empty(t, issues)
+			}
+		})
+	}
+}
+
+// TestEmit_Hints 测试检查器提示对输出的影响
+func TestEmit_Hints(t *testing.T) {
+	t.Run("列类型覆盖", func(t *testing.T) {
+		stmts := parse(t, "CREATE TABLE t (id BIGINT AUTO_INCREMENT PRIMARY KEY)")
+		create := stmts[0].(*ast.CreateTableStmt)
+		col := create.Cols[0]
+		// 模拟检查器移除 AUTO_INCREMENT 并记录 SERIAL 类型
+		opts := col.Options[:0]
+		for _, opt := range col.Options {
+			if opt.Tp != ast.ColumnOptionAutoIncrement {
+				opts = append(opts, opt)
+			}
+		}
+		col.Options = opts
+
+		hints := NewHints()
+		hints.SetColumnType(col, "BIGSERIAL")
+
+		sql, _, err := NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Equal(t, "CREATE TABLE t (id BIGSERIAL PRIMARY KEY)", sql)
+	})
+
+	t.Run("ENUM 类型", func(t *testing.T) {
+		stmts := parse(t, "CREATE TABLE shop.users (status ENUM('on','off'))")
+		hints := NewHints()
+		hints.UseEnumType(stmts[0].(*ast.CreateTableStmt).Cols[0])
+
+		sql, issues, err := NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+		assert.Equal(t, "CREATE TYPE shop.users_status AS ENUM ('on','off');\nCREATE TABLE shop.users (status shop.users_status)", sql)
+	})
+
+	t.Run("OFFSET FETCH", func(t *testing.T) {
+		stmts := parse(t, "SELECT * FROM t LIMIT 5, 10")
+		hints := NewHints()
+		hints.UseOffsetFetch(stmts[0].(*ast.SelectStmt).Limit)
+
+		sql, _, err := NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM t OFFSET 5 ROWS FETCH NEXT 10 ROWS ONLY", sql)
+	})
+
+	t.Run("ON CONFLICT DO UPDATE", func(t *testing.T) {
+		stmts := parse(t, "INSERT INTO users (email, name) VALUES ('a@x', 'a') ON DUPLICATE KEY UPDATE name = VALUES(name)")
+		hints := NewHints()
+		hints.SetOnConflict(stmts[0].(*ast.InsertStmt), OnConflict{Columns: []string{"email"}})
+
+		sql, issues, err := NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+		assert.Equal(t, "INSERT INTO users (email,name) VALUES ('a@x','a') ON CONFLICT (email) DO UPDATE SET name=EXCLUDED.name", sql)
+	})
+
+	t.Run("ON CONFLICT DO NOTHING", func(t *testing.T) {
+		stmts := parse(t, "INSERT INTO logs (msg) VALUES ('x')")
+		hints := NewHints()
+		hints.SetOnConflict(stmts[0].(*ast.InsertStmt), OnConflict{DoNothing: true})
+
+		sql, _, err := NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Equal(t, "INSERT INTO logs (msg) VALUES ('x') ON CONFLICT DO NOTHING", sql)
+	})
+
+	t.Run("UPDATE ... FROM", func(t *testing.T) {
+		stmts := parse(t, "UPDATE orders o JOIN users u ON o.uid = u.id SET o.status = u.flag WHERE u.a = 1 OR u.b = 1")
+		update := stmts[0].(*ast.UpdateStmt)
+		join := update.TableRefs.TableRefs
+		hints := NewHints()
+		hints.SetJoinedDML(update, JoinedDML{
+			Target:     join.Left.(*ast.TableSource),
+			Tables:     []*ast.TableSource{join.Right.(*ast.TableSource)},
+			Conditions: []*ast.OnCondition{join.On},
+		})
+
+		sql, issues, err := NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+		assert.Equal(t, "UPDATE orders AS o SET status=u.flag FROM users AS u WHERE o.uid=u.id AND (u.a=1 OR u.b=1)", sql)
+	})
+
+	t.Run("DELETE ... USING", func(t *testing.T) {
+		stmts := parse(t, "DELETE o FROM orders o JOIN users u ON o.uid = u.id")
+		del := stmts[0].(*ast.DeleteStmt)
+		join := del.TableRefs.TableRefs
+		hints := NewHints()
+		hints.SetJoinedDML(del, JoinedDML{
+			Target:     join.Left.(*ast.TableSource),
+			Tables:     []*ast.TableSource{join.Right.(*ast.TableSource)},
+			Conditions: []*ast.OnCondition{join.On},
+		})
+
+		sql, _, err := NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Equal(t, "DELETE FROM orders AS o USING users AS u WHERE o.uid=u.id", sql)
+	})
+
+	t.Run("类型转换表", func(t *testing.T) {
+		stmts := parse(t, "CREATE TABLE t (a TIMESTAMP(3), b ENUM('x'), c DATETIME)")
+		mapping, err := NewTypeMapping(map[string]string{"timestamp": "TIMESTAMPTZ(${fsp})", "Enum": "TEXT"})
+		require.NoError(t, err)
+		hints := NewHints()
+		hints.SetTypeMapping(mapping)
+		hints.Reset()
+
+		sql, issues, err := NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+		assert.Equal(t, "CREATE TABLE t (a TIMESTAMPTZ(3),b TEXT,c TIMESTAMP(0))", sql)
+
+		_, err = NewTypeMapping(map[string]string{"DATETIME2": "TIMESTAMP"})
+		assert.Error(t, err)
+	})
+
+	t.Run("ON UPDATE 触发器", func(t *testing.T) {
+		stmts := parse(t, "CREATE TABLE shop.t (id INT, updated_at DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3))")
+		create := stmts[0].(*ast.CreateTableStmt)
+		call := create.Cols[1].Options[0].Expr
+		hints := NewHints()
+		hints.SetUpdateTrigger(create, UpdateTrigger{
+			Function: "t_on_update_fn",
+			Trigger:  "t_on_update_trg",
+			Columns:  []*ast.Assignment{{Column: &ast.ColumnName{Name: create.Cols[1].Name.Name}, Expr: call}},
+		})
+
+		sql, issues, err := NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+		assert.Equal(t, "CREATE TABLE shop.t (id INTEGER,updated_at TIMESTAMP(3) DEFAULT CURRENT_TIMESTAMP(3));\n"+
+			"CREATE OR REPLACE FUNCTION shop.t_on_update_fn() RETURNS trigger AS $$ BEGIN IF NEW IS DISTINCT FROM OLD AND NEW.updated_at IS NOT DISTINCT FROM OLD.updated_at THEN NEW.updated_at := CURRENT_TIMESTAMP(3); END IF; RETURN NEW; END $$ LANGUAGE plpgsql;\n"+
+			"DROP TRIGGER IF EXISTS t_on_update_trg ON shop.t;\n"+
+			"CREATE TRIGGER t_on_update_trg BEFORE UPDATE ON shop.t FOR EACH ROW EXECUTE FUNCTION shop.t_on_update_fn()", sql)
+
+		stmts = parse(t, "ALTER TABLE t DROP COLUMN updated_at")
+		hints.SetUpdateTrigger(stmts[0], UpdateTrigger{Function: "t_on_update_fn", Trigger: "t_on_update_trg"})
+		sql, _, err = NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Equal(t, "ALTER TABLE t DROP COLUMN updated_at;\nDROP TRIGGER IF EXISTS t_on_update_trg ON t;\nDROP FUNCTION IF EXISTS t_on_update_fn()", sql)
+	})
+
+	t.Run("拆分的索引", func(t *testing.T) {
+		stmts := parse(t, "CREATE TABLE shop.t (id INT, name VARCHAR(20), KEY idx_name (name))")
+		create := stmts[0].(*ast.CreateTableStmt)
+		keys := create.Constraints[0].Keys
+		create.Constraints = nil
+		hints := NewHints()
+		hints.SetIndexes(create, []*ast.CreateIndexStmt{{IndexName: "t_idx_name", Table: create.Table, IndexPartSpecifications: keys}})
+
+		sql, issues, err := NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+		assert.Equal(t, "CREATE TABLE shop.t (id INTEGER,name VARCHAR(20));\nCREATE INDEX t_idx_name ON shop.t (name)", sql)
+
+		stmts = parse(t, "ALTER TABLE shop.t DROP INDEX idx_a, RENAME INDEX idx_b TO idx_c")
+		sql, _, err = NewEmitter().Emit(stmts, NewHints())
+		require.NoError(t, err)
+		assert.Equal(t, "DROP INDEX shop.idx_a;\nALTER INDEX shop.idx_b RENAME TO idx_c", sql)
+	})
+
+	t.Run("Reset 后提示失效", func(t *testing.T) {
+		stmts := parse(t, "SELECT * FROM t LIMIT 10")
+		hints := NewHints()
+		hints.UseOffsetFetch(stmts[0].(*ast.SelectStmt).Limit)
+		hints.Reset()
+
+		sql, _, err := NewEmitter().Emit(stmts, hints)
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM t LIMIT 10", sql)
+	})
+}
@@ -0,0 +1,715 @@
+package sqlemitter
+
+import (
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
+	"github.com/pingcap/tidb/pkg/parser/test_driver"
+)
+
+// plainIdentPattern 无需引号即可使用的标识符（小写形式）
+var plainIdentPattern = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+
+// reservedWords PostgreSQL 保留关键字，作为标识符时必须加双引号
+var reservedWords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true,
+	"as": true, "asc": true, "asymmetric": true, "authorization": true, "binary": true,
+	"both": true, "case": true, "cast": true, "check": true, "collate": true, "collation": true,
+	"column": true, "concurrently": true, "constraint": true, "create": true, "cross": true,
+	"current_catalog": true, "current_date": true, "current_role": true, "current_schema": true,
+	"current_time": true, "current_timestamp": true, "current_user": true, "default": true,
+	"deferrable": true, "desc": true, "distinct": true, "do": true, "else": true, "end": true,
+	"except": true, "false": true, "fetch": true, "for": true, "foreign": true, "freeze": true,
+	"from": true, "full": true, "grant": true, "group": true, "having": true, "ilike": true,
+	"in": true, "initially": true, "inner": true, "intersect": true, "into": true, "is": true,
+	"isnull": true, "join": true, "lateral": true, "leading": true, "left": true, "like": true,
+	"limit": true, "localtime": true, "localtimestamp": true, "natural": true, "not": true,
+	"notnull": true, "null": true, "offset": true, "on": true, "only": true, "or": true,
+	"order": true, "outer": true, "overlaps": true, "placing": true, "primary": true,
+	"references": true, "returning": true, "right": true, "select": true, "session_user": true,
+	"similar": true, "some": true, "symmetric": true, "system_user": true, "table": true,
+	"tablesample": true, "then": true, "to": true, "trailing": true, "true": true, "union": true,
+	"unique": true, "user": true, "using": true, "variadic": true, "verbose": true, "when": true,
+	"where": true, "window": true, "with": true,
+}
+
+// QuoteIdent 将 MySQL 标识符转换为 YSQL 标识符
+// MySQL 标识符大小写不敏感，统一转为小写；仅当名称包含特殊字符或为保留字时使用双引号。
+// 参数:
+//   - name: 原始标识符
+//
+// 返回:
+//   - string: YSQL 标识符
+func QuoteIdent(name string) string {
+	lower := strings.ToLower(name)
+	if plainIdentPattern.MatchString(lower) && !reservedWords[lower] {
+		return lower
+	}
+	return `"` + strings.ReplaceAll(lower, `"`, `""`) + `"`
+}
+
+// QuoteString 将字符串输出为 YSQL 单引号字面量
+// standard_conforming_strings 开启时反斜杠不是转义符，只需转义单引号
+func QuoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// binaryOps 二元运算符映射
+// 与 TiDB Restore 保持一致：比较与算术运算符两侧不加空格，关键字运算符两侧加空格
+var binaryOps = map[opcode.Op]string{
+	opcode.LogicAnd:   " AND ",
+	opcode.LogicOr:    " OR ",
+	opcode.GE:         ">=",
+	opcode.LE:         "<=",
+	opcode.EQ:         "=",
+	opcode.NE:         "!=",
+	opcode.LT:         "<",
+	opcode.GT:         ">",
+	opcode.Plus:       "+",
+	opcode.Minus:      "-",
+	opcode.Mul:        "*",
+	opcode.Div:        "/",
+	opcode.Mod:        "%",
+	opcode.And:        "&",
+	opcode.Or:         "|",
+	opcode.Xor:        "#",
+	opcode.LeftShift:  "<<",
+	opcode.RightShift: ">>",
+	opcode.NullEQ:     " IS NOT DISTINCT FROM ",
+}
+
+// niladicFuncs 在 YSQL 中无参数时不能带括号的函数
+var niladicFuncs = map[string]bool{
+	"current_timestamp": true,
+	"current_date":      true,
+	"current_time":      true,
+	"localtime":         true,
+	"localtimestamp":    true,
+	"current_user":      true,
+	"session_user":      true,
+}
+
+// intervalUnits 时间单位到 YSQL 单位间隔的映射
+var intervalUnits = map[ast.TimeUnitType]string{
+	ast.TimeUnitMicrosecond: "1 MICROSECOND",
+	ast.TimeUnitSecond:      "1 SECOND",
+	ast.TimeUnitMinute:      "1 MINUTE",
+	ast.TimeUnitHour:        "1 HOUR",
+	ast.TimeUnitDay:         "1 DAY",
+	ast.TimeUnitWeek:        "1 WEEK",
+	ast.TimeUnitMonth:       "1 MONTH",
+	ast.TimeUnitQuarter:     "3 MONTH",
+	ast.TimeUnitYear:        "1 YEAR",
+}
+
+// expr 输出表达式
+func (e *Emitter) expr(node ast.ExprNode) {
+	switch n := node.(type) {
+	case nil:
+		e.w("NULL")
+	case *test_driver.ParamMarkerExpr:
+		// YSQL 预处理语句使用 $n 形式的占位符，编号与 MySQL 中 ? 的出现顺序一致
+		e.w("$" + strconv.Itoa(e.params[n]))
+	case *test_driver.ValueExpr:
+		e.value(n)
+	case *ast.ColumnNameExpr:
+		e.columnName(n.Name)
+	case *ast.BinaryOperationExpr:
+		e.binaryOperation(n)
+	case *ast.UnaryOperationExpr:
+		e.unaryOperation(n)
+	case *ast.ParenthesesExpr:
+		e.w("(")
+		e.expr(n.Expr)
+		e.w(")")
+	case *ast.FuncCallExpr:
+		e.funcCall(n)
+	case *ast.AggregateFuncExpr:
+		e.aggregateFunc(n)
+	case *ast.WindowFuncExpr:
+		e.windowFunc(n)
+	case *ast.FuncCastExpr:
+		e.w("CAST(")
+		e.expr(n.Expr)
+		e.w(" AS ")
+		e.w(e.fieldType(n.Tp))
+		e.w(")")
+	case *ast.PatternInExpr:
+		e.patternIn(n)
+	case *ast.PatternLikeOrIlikeExpr:
+		e.patternLike(n)
+	case *ast.PatternRegexpExpr:
+		e.expr(n.Expr)
+		if n.Not {
+			e.w(" !~ ")
+		} else {
+			e.w(" ~ ")
+		}
+		e.expr(n.Pattern)
+	case *ast.BetweenExpr:
+		e.expr(n.Expr)
+		if n.Not {
+			e.w(" NOT BETWEEN ")
+		} else {
+			e.w(" BETWEEN ")
+		}
+		e.expr(n.Left)
+		e.w(" AND ")
+		e.expr(n.Right)
+	case *ast.IsNullExpr:
+		e.expr(n.Expr)
+		if n.Not {
+			e.w(" IS NOT NULL")
+		} else {
+			e.w(" IS NULL")
+		}
+	case *ast.IsTruthExpr:
+		e.expr(n.Expr)
+		e.w(" IS ")
+		if n.Not {
+			e.w("NOT ")
+		}
+		if n.True > 0 {
+			e.w("TRUE")
+		} else {
+			e.w("FALSE")
+		}
+	case *ast.CaseExpr:
+		e.caseExpr(n)
+	case *ast.SubqueryExpr:
+		e.subquery(n)
+	case *ast.ExistsSubqueryExpr:
+		if n.Not {
+			e.w("NOT ")
+		}
+		e.w("EXISTS ")
+		e.expr(n.Sel)
+	case *ast.CompareSubqueryExpr:
+		e.expr(n.L)
+		e.w(binaryOps[n.Op])
+		if n.All {
+			e.w("ALL ")
+		} else {
+			e.w("ANY ")
+		}
+		e.expr(n.R)
+	case *ast.RowExpr:
+		e.w("ROW(")
+		e.exprList(n.Values, ",")
+		e.w(")")
+	case *ast.DefaultExpr:
+		e.w("DEFAULT")
+		if n.Name != nil {
+			e.addIssue("DEFAULT(%s) 在 YSQL 中不支持，已输出为 DEFAULT", n.Name.Name.O)
+		}
+	case *ast.ValuesExpr:
+		// ON DUPLICATE KEY UPDATE 中的 VALUES(col) 对应 ON CONFLICT 的 EXCLUDED.col
+		e.w("EXCLUDED.")
+		e.name(n.Column.Name.Name.O)
+	case *ast.PositionExpr:
+		if n.P != nil {
+			e.expr(n.P)
+		} else {
+			e.w(strconv.Itoa(n.N))
+		}
+	case *ast.TimeUnitExpr:
+		e.w(n.Unit.String())
+	case *ast.TrimDirectionExpr:
+		e.w(n.Direction.String())
+	case *ast.SetCollationExpr:
+		e.addIssue("COLLATE %s 在 YSQL 中没有同名排序规则，已省略", n.Collate)
+		e.expr(n.Expr)
+	default:
+		e.unsupportedNode(node)
+	}
+}
+
+// exprList 输出以 sep 分隔的表达式列表
+func (e *Emitter) exprList(list []ast.ExprNode, sep string) {
+	for i, item := range list {
+		if i > 0 {
+			e.w(sep)
+		}
+		e.expr(item)
+	}
+}
+
+// value 输出字面量
+func (e *Emitter) value(n *test_driver.ValueExpr) {
+	switch n.Kind() {
+	case test_driver.KindNull:
+		e.w("NULL")
+	case test_driver.KindInt64:
+		if n.Type.GetFlag()&mysql.IsBooleanFlag != 0 {
+			if n.GetInt64() > 0 {
+				e.w("TRUE")
+			} else {
+				e.w("FALSE")
+			}
+			return
+		}
+		e.w(strconv.FormatInt(n.GetInt64(), 10))
+	case test_driver.KindUint64:
+		e.w(strconv.FormatUint(n.GetUint64(), 10))
+	case test_driver.KindFloat32:
+		e.w(strconv.FormatFloat(n.GetFloat64(), 'g', -1, 32))
+	case test_driver.KindFloat64:
+		e.w(strconv.FormatFloat(n.GetFloat64(), 'g', -1, 64))
+	case test_driver.KindString, test_driver.KindBytes:
+		// 字符集前缀（_utf8mb4 等）在 YSQL 中没有意义，直接省略
+		e.stringLiteral(n.GetString())
+	case test_driver.KindMysqlDecimal:
+		e.w(n.GetMysqlDecimal().String())
+	case test_driver.KindBinaryLiteral:
+		// 十六进制字面量 0x1F/x'1F' 带 UnsignedFlag，输出为 BYTEA；位串字面量 b'101' 输出为 YSQL 的位串 B'101'
+		if n.Type.GetFlag()&mysql.UnsignedFlag == 0 {
+			e.w("B" + strings.TrimPrefix(n.GetBinaryLiteral().ToBitLiteralString(true), "b"))
+			return
+		}
+		e.w(`'\x` + hex.EncodeToString(n.GetBytes()) + `'::BYTEA`)
+	default:
+		e.unsupportedNode(n)
+	}
+}
+
+// stringLiteral 输出字符串字面量
+func (e *Emitter) stringLiteral(s string) {
+	e.w(QuoteString(s))
+}
+
+// columnName 输出列名（含表名、schema 前缀）
+func (e *Emitter) columnName(n *ast.ColumnName) {
+	if n.Schema.O != "" {
+		e.name(n.Schema.O)
+		e.w(".")
+	}
+	if n.Table.O != "" {
+		e.name(n.Table.O)
+		e.w(".")
+	}
+	e.name(n.Name.O)
+}
+
+// binaryOperation 输出二元运算
+func (e *Emitter) binaryOperation(n *ast.BinaryOperationExpr) {
+	switch n.Op {
+	case opcode.IntDiv:
+		e.w("DIV(")
+		e.expr(n.L)
+		e.w(", ")
+		e.expr(n.R)
+		e.w(")")
+		return
+	case opcode.LogicXor:
+		e.w("(")
+		e.expr(n.L)
+		e.w(")::BOOLEAN<>(")
+		e.expr(n.R)
+		e.w(")::BOOLEAN")
+		return
+	}
+
+	op, ok := binaryOps[n.Op]
+	if !ok {
+		e.unsupportedNode(n)
+		return
+	}
+	e.expr(n.L)
+	e.w(op)
+	e.expr(n.R)
+}
+
+// unaryOperation 输出一元运算
+func (e *Emitter) unaryOperation(n *ast.UnaryOperationExpr) {
+	switch n.Op {
+	case opcode.Not, opcode.Not2:
+		e.w("NOT ")
+		e.expr(n.V)
+	case opcode.BitNeg:
+		e.w("~")
+		e.expr(n.V)
+	case opcode.Plus:
+		e.expr(n.V)
+	case opcode.Minus:
+		operand := e.capture(func() { e.expr(n.V) })
+		// 避免输出 "--"，在 YSQL 中它会被当作注释
+		if strings.HasPrefix(operand, "-") {
+			e.w("-(" + operand + ")")
+		} else {
+			e.w("-" + operand)
+		}
+	default:
+		e.unsupportedNode(n)
+	}
+}
+
+// patternIn 输出 IN 表达式
+func (e *Emitter) patternIn(n *ast.PatternInExpr) {
+	e.expr(n.Expr)
+	if n.Not {
+		e.w(" NOT IN ")
+	} else {
+		e.w(" IN ")
+	}
+	if n.Sel != nil {
+		e.expr(n.Sel)
+		return
+	}
+	e.w("(")
+	e.exprList(n.List, ",")
+	e.w(")")
+}
+
+// patternLike 输出 LIKE / ILIKE 表达式
+func (e *Emitter) patternLike(n *ast.PatternLikeOrIlikeExpr) {
+	e.expr(n.Expr)
+	if n.Not {
+		e.w(" NOT")
+	}
+	if n.IsLike {
+		e.w(" LIKE ")
+	} else {
+		e.w(" ILIKE ")
+	}
+	e.expr(n.Pattern)
+	if n.Escape != '\\' {
+		e.w(" ESCAPE ")
+		e.stringLiteral(string(n.Escape))
+	}
+}
+
+// caseExpr 输出 CASE 表达式
+func (e *Emitter) caseExpr(n *ast.CaseExpr) {
+	e.w("CASE")
+	if n.Value != nil {
+		e.w(" ")
+		e.expr(n.Value)
+	}
+	for _, clause := range n.WhenClauses {
+		e.w(" WHEN ")
+		e.expr(clause.Expr)
+		e.w(" THEN ")
+		e.expr(clause.Result)
+	}
+	if n.ElseClause != nil {
+		e.w(" ELSE ")
+		e.expr(n.ElseClause)
+	}
+	e.w(" END")
+}
+
+// subquery 输出子查询
+func (e *Emitter) subquery(n *ast.SubqueryExpr) {
+	switch q := n.Query.(type) {
+	case *ast.SelectStmt:
+		if q.IsInBraces {
+			e.selectStmt(q)
+			return
+		}
+		e.w("(")
+		e.selectStmt(q)
+		e.w(")")
+	case *ast.SetOprStmt:
+		if q.IsInBraces {
+			e.setOprStmt(q)
+			return
+		}
+		e.w("(")
+		e.setOprStmt(q)
+		e.w(")")
+	default:
+		e.w("(")
+		e.resultSet(n.Query)
+		e.w(")")
+	}
+}
+
+// funcCall 输出普通函数调用
+func (e *Emitter) funcCall(n *ast.FuncCallExpr) {
+	switch n.FnName.L {
+	case ast.DateLiteral, ast.TimeLiteral, ast.TimestampLiteral:
+		keyword := map[string]string{
+			ast.DateLiteral:      "DATE ",
+			ast.TimeLiteral:      "TIME ",
+			ast.TimestampLiteral: "TIMESTAMP ",
+		}[n.FnName.L]
+		e.w(keyword)
+		e.expr(n.Args[0])
+		return
+	case "if":
+		if len(n.Args) == 3 {
+			e.w("CASE WHEN ")
+			e.expr(n.Args[0])
+			e.w(" THEN ")
+			e.expr(n.Args[1])
+			e.w(" ELSE ")
+			e.expr(n.Args[2])
+			e.w(" END")
+			return
+		}
+	case "date_add", "adddate", "date_sub", "subdate":
+		if len(n.Args) == 3 {
+			e.dateArith(n)
+			return
+		}
+	case "extract":
+		e.w("EXTRACT(")
+		e.expr(n.Args[0])
+		e.w(" FROM ")
+		e.expr(n.Args[1])
+		e.w(")")
+		return
+	case "position":
+		e.w("POSITION(")
+		e.expr(n.Args[0])
+		e.w(" IN ")
+		e.expr(n.Args[1])
+		e.w(")")
+		return
+	case "trim":
+		e.trim(n)
+		return
+	case "convert":
+		e.addIssue("CONVERT(... USING ...) 字符集转换在 YSQL 中不支持，已省略")
+		e.expr(n.Args[0])
+		return
+	case ast.WeightString, ast.JSONMemberOf, ast.GetFormat:
+		e.unsupportedNode(n)
+		return
+	}
+
+	if mysqlOnlyFuncs[n.FnName.L] && e.mysqlFunc(n) {
+		return
+	}
+
+	if niladicFuncs[n.FnName.L] && len(n.Args) == 0 {
+		e.w(strings.ToUpper(n.FnName.O))
+		return
+	}
+
+	if n.Schema.O != "" {
+		e.name(n.Schema.O)
+		e.w(".")
+	}
+	if n.Tp == ast.FuncCallExprTypeGeneric {
+		e.name(n.FnName.O)
+	} else {
+		e.w(strings.ToUpper(n.FnName.O))
+	}
+	e.w("(")
+	e.exprList(n.Args, ", ")
+	e.w(")")
+}
+
+// dateArith 将 DATE_ADD/DATE_SUB 输出为 YSQL 的间隔运算
+func (e *Emitter) dateArith(n *ast.FuncCallExpr) {
+	unitExpr, ok := n.Args[2].(*ast.TimeUnitExpr)
+	if !ok {
+		e.unsupportedNode(n)
+		return
+	}
+	interval, ok := intervalUnits[unitExpr.Unit]
+	if !ok {
+		e.addIssue("%s 的复合时间单位 %s 在 YSQL 中没有对应写法，需要人工改写", strings.ToUpper(n.FnName.O), unitExpr.Unit.String())
+		e.unsupportedNode(n)
+		return
+	}
+
+	op := " + "
+	if n.FnName.L == "date_sub" || n.FnName.L == "subdate" {
+		op = " - "
+	}
+	e.w("(")
+	e.expr(n.Args[0])
+	e.w(op + "(")
+	e.expr(n.Args[1])
+	e.w(") * INTERVAL '" + interval + "')")
+}
+
+// trim 输出 TRIM([BOTH|LEADING|TRAILING] [remstr] FROM str)
+func (e *Emitter) trim(n *ast.FuncCallExpr) {
+	e.w("TRIM(")
+	switch len(n.Args) {
+	case 3:
+		e.expr(n.Args[2])
+		e.w(" ")
+		fallthrough
+	case 2:
+		if v, ok := n.Args[1].(ast.ValueExpr); !ok || v.GetValue() != nil {
+			e.expr(n.Args[1])
+			e.w(" ")
+		}
+		e.w("FROM ")
+		fallthrough
+	case 1:
+		e.expr(n.Args[0])
+	}
+	e.w(")")
+}
+
+// aggregateFunc 输出聚合函数
+func (e *Emitter) aggregateFunc(n *ast.AggregateFuncExpr) {
+	name := strings.ToLower(n.F)
+	e.w(strings.ToUpper(n.F))
+	e.w("(")
+	if n.Distinct {
+		e.w("DISTINCT ")
+	}
+
+	switch name {
+	case ast.AggFuncGroupConcat, "string_agg":
+		// GROUP_CONCAT 的 AST 参数为 [表达式..., 分隔符]，多个表达式需要先拼接
+		if len(n.Args) >= 2 {
+			values := n.Args[:len(n.Args)-1]
+			if len(values) > 1 {
+				e.w("CONCAT(")
+				e.exprList(values, ", ")
+				e.w(")")
+			} else {
+				e.expr(values[0])
+			}
+			e.w(", ")
+			e.expr(n.Args[len(n.Args)-1])
+			break
+		}
+		e.exprList(n.Args, ", ")
+	default:
+		e.exprList(n.Args, ", ")
+	}
+
+	if n.Order != nil {
+		e.w(" ORDER BY ")
+		e.byItems(n.Order.Items)
+	}
+	e.w(")")
+}
+
+// windowFunc 输出窗口函数
+func (e *Emitter) windowFunc(n *ast.WindowFuncExpr) {
+	e.w(strings.ToUpper(n.Name))
+	e.w("(")
+	if n.Distinct {
+		e.w("DISTINCT ")
+	}
+	e.exprList(n.Args, ", ")
+	e.w(")")
+	if n.FromLast || n.IgnoreNull {
+		e.addIssue("窗口函数 %s 的 FROM LAST/IGNORE NULLS 在 YSQL 中不支持，已省略", strings.ToUpper(n.Name))
+	}
+	e.w(" OVER ")
+	e.windowSpec(&n.Spec)
+}
+
+// windowSpec 输出窗口定义
+func (e *Emitter) windowSpec(spec *ast.WindowSpec) {
+	if spec.OnlyAlias {
+		e.name(spec.Name.O)
+		return
+	}
+
+	parts := make([]string, 0, 4)
+	if spec.Ref.O != "" {
+		parts = append(parts, QuoteIdent(spec.Ref.O))
+	}
+	if spec.PartitionBy != nil {
+		parts = append(parts, e.capture(func() {
+			e.w("PARTITION BY ")
+			e.byItems(spec.PartitionBy.Items)
+		}))
+	}
+	if spec.OrderBy != nil {
+		parts = append(parts, e.capture(func() {
+			e.w("ORDER BY ")
+			e.byItems(spec.OrderBy.Items)
+		}))
+	}
+	if spec.Frame != nil {
+		parts = append(parts, e.capture(func() { e.frame(spec.Frame) }))
+	}
+	e.w("(" + strings.Join(parts, " ") + ")")
+}
+
+// frame 输出窗口帧定义
+func (e *Emitter) frame(n *ast.FrameClause) {
+	switch n.Type {
+	case ast.Rows:
+		e.w("ROWS")
+	case ast.Ranges:
+		e.w("RANGE")
+	case ast.Groups:
+		e.w("GROUPS")
+	}
+	e.w(" BETWEEN ")
+	e.frameBound(&n.Extent.Start)
+	e.w(" AND ")
+	e.frameBound(&n.Extent.End)
+}
+
+// frameBound 输出窗口帧边界
+func (e *Emitter) frameBound(n *ast.FrameBound) {
+	if n.Type == ast.CurrentRow {
+		e.w("CURRENT ROW")
+		return
+	}
+	switch {
+	case n.UnBounded:
+		e.w("UNBOUNDED")
+	case n.Unit != ast.TimeUnitInvalid:
+		interval, ok := intervalUnits[n.Unit]
+		if !ok {
+			e.unsupportedNode(n)
+			break
+		}
+		e.w("(")
+		e.expr(n.Expr)
+		e.w(") * INTERVAL '" + interval + "'")
+	default:
+		e.expr(n.Expr)
+	}
+	if n.Type == ast.Preceding {
+		e.w(" PRECEDING")
+	} else {
+		e.w(" FOLLOWING")
+	}
+}
+
+// paramCollector 收集语句中的参数占位符
+type paramCollector struct {
+	markers []*test_driver.ParamMarkerExpr
+}
+
+// Enter 实现 ast.Visitor 接口
+func (c *paramCollector) Enter(node ast.Node) (ast.Node, bool) {
+	if m, ok := node.(*test_driver.ParamMarkerExpr); ok {
+		c.markers = append(c.markers, m)
+	}
+	return node, false
+}
+
+// Leave 实现 ast.Visitor 接口
+func (c *paramCollector) Leave(node ast.Node) (ast.Node, bool) {
+	return node, true
+}
+
+// paramOrder 按占位符在原文中的位置为其编号（从 1 开始）
+// 输出顺序可能与原文不同（如 LIMIT ?, ? 输出为 LIMIT ... OFFSET ...），
+// 因此编号不能在输出时递增，而要以原文顺序为准，保证参数绑定顺序不变。
+func paramOrder(stmt ast.StmtNode) map[*test_driver.ParamMarkerExpr]int {
+	c := &paramCollector{}
+	stmt.Accept(c)
+	sort.SliceStable(c.markers, func(i, j int) bool {
+		return c.markers[i].Offset < c.markers[j].Offset
+	})
+
+	order := make(map[*test_driver.ParamMarkerExpr]int, len(c.markers))
+	for i, m := range c.markers {
+		order[m] = i + 1
+	}
+	return order
+}
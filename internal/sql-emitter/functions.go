@@ -0,0 +1,315 @@
+package sqlemitter
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/test_driver"
+)
+
+// mysqlOnlyFuncs YSQL 中没有同名实现（或同名但语义不同）的 MySQL 函数
+// 未能自动改写时原样输出并记录问题，由用户改写或在目标库中创建兼容函数
+var mysqlOnlyFuncs = map[string]bool{
+	"date_format": true, "str_to_date": true, "unix_timestamp": true, "from_unixtime": true,
+	"datediff": true, "timediff": true, "timestampdiff": true, "timestampadd": true,
+	"curdate": true, "curtime": true, "sysdate": true, "utc_timestamp": true,
+	"dayofweek": true, "dayofmonth": true, "dayofyear": true, "weekday": true, "yearweek": true,
+	"last_day": true, "makedate": true, "maketime": true, "sec_to_time": true, "time_to_sec": true,
+	"to_days": true, "from_days": true, "period_add": true, "period_diff": true, "convert_tz": true,
+	"find_in_set": true, "instr": true, "locate": true, "substring_index": true, "field": true,
+	"elt": true, "make_set": true, "export_set": true, "insert_func": true, "space": true,
+	"strcmp": true, "hex": true, "unhex": true, "bin": true, "oct": true, "conv": true,
+	"inet_aton": true, "inet_ntoa": true, "uuid": true, "uuid_short": true,
+	"last_insert_id": true, "found_rows": true, "row_count": true, "database": true,
+	"json_extract": true, "json_unquote": true, "json_set": true, "json_insert": true,
+	"json_replace": true, "json_remove": true, "json_contains": true, "json_contains_path": true,
+	"json_search": true, "json_keys": true, "json_length": true, "json_depth": true,
+	"json_valid": true, "json_type": true, "json_array": true, "json_object": true,
+	"json_merge": true, "json_merge_patch": true, "json_merge_preserve": true,
+	"json_array_append": true, "json_array_insert": true, "json_quote": true,
+}
+
+// dateFormatSpecifiers MySQL DATE_FORMAT/STR_TO_DATE 格式符到 YSQL TO_CHAR/TO_TIMESTAMP 模板的映射
+// 未列出的格式符（如 %U、%w）在 YSQL 中没有语义一致的写法
+var dateFormatSpecifiers = map[byte]string{
+	'Y': "YYYY", 'y': "YY", 'm': "MM", 'c': "FMMM", 'd': "DD", 'e': "FMDD", 'D': "FMDDth",
+	'H': "HH24", 'k': "FMHH24", 'h': "HH12", 'I': "HH12", 'l': "FMHH12",
+	'i': "MI", 's': "SS", 'S': "SS", 'f': "US", 'p': "AM",
+	'M': "FMMonth", 'b': "Mon", 'W': "FMDay", 'a': "Dy", 'j': "DDD",
+	'v': "IW", 'x': "IYYY", 'T': "HH24:MI:SS", 'r': "HH12:MI:SS AM",
+}
+
+// timeSpecifiers 包含时间部分的格式符，STR_TO_DATE 据此决定输出 TO_DATE 还是 TO_TIMESTAMP
+const timeSpecifiers = "HkhIlisSfpTr"
+
+// mysqlFunc 将 MySQL 特有函数改写为 YSQL 写法，无法改写时记录问题
+// 参数:
+//   - n: 函数调用节点，函数名属于 mysqlOnlyFuncs
+//
+// 返回:
+//   - bool: 已输出改写结果时为 true；为 false 时由调用方按原样输出
+func (e *Emitter) mysqlFunc(n *ast.FuncCallExpr) bool {
+	switch n.FnName.L {
+	case "date_format":
+		if len(n.Args) != 2 {
+			break
+		}
+		tmpl, ok := e.dateTemplate(n, n.Args[1])
+		if !ok {
+			return false
+		}
+		e.w("TO_CHAR(")
+		e.temporalArg(n.Args[0])
+		e.w(", " + QuoteString(tmpl) + ")")
+		return true
+	case "str_to_date":
+		if len(n.Args) != 2 {
+			break
+		}
+		tmpl, ok := e.dateTemplate(n, n.Args[1])
+		if !ok {
+			return false
+		}
+		format, _ := stringArg(n.Args[1])
+		if hasTimeSpecifier(format) {
+			e.w("TO_TIMESTAMP(")
+			e.expr(n.Args[0])
+			e.w(", " + QuoteString(tmpl) + ")::TIMESTAMP")
+		} else {
+			e.w("TO_DATE(")
+			e.expr(n.Args[0])
+			e.w(", " + QuoteString(tmpl) + ")")
+		}
+		return true
+	case "unix_timestamp":
+		switch len(n.Args) {
+		case 0:
+			e.w("FLOOR(EXTRACT(EPOCH FROM CURRENT_TIMESTAMP))::BIGINT")
+			return true
+		case 1:
+			e.w("FLOOR(EXTRACT(EPOCH FROM (")
+			e.expr(n.Args[0])
+			e.w(")::TIMESTAMPTZ))::BIGINT")
+			return true
+		}
+	case "from_unixtime":
+		switch len(n.Args) {
+		case 1:
+			e.w("TO_TIMESTAMP(")
+			e.expr(n.Args[0])
+			e.w(")::TIMESTAMP")
+			return true
+		case 2:
+			tmpl, ok := e.dateTemplate(n, n.Args[1])
+			if !ok {
+				return false
+			}
+			e.w("TO_CHAR(TO_TIMESTAMP(")
+			e.expr(n.Args[0])
+			e.w("), " + QuoteString(tmpl) + ")")
+			return true
+		}
+	case "json_extract":
+		return e.jsonExtract(n, " #> ")
+	case "json_unquote":
+		// col->>'$.a' 解析为 JSON_UNQUOTE(JSON_EXTRACT(col, '$.a'))
+		if len(n.Args) != 1 {
+			break
+		}
+		inner, ok := n.Args[0].(*ast.FuncCallExpr)
+		if !ok || inner.FnName.L != "json_extract" {
+			break
+		}
+		if !e.jsonExtract(inner, " #>> ") {
+			// 问题已由 jsonExtract 记录，直接输出原调用，避免内层 JSON_EXTRACT 重复记录
+			e.w("JSON_UNQUOTE(JSON_EXTRACT(")
+			e.exprList(inner.Args, ", ")
+			e.w("))")
+		}
+		return true
+	}
+
+	e.addIssue("函数 %s 在 YSQL 中不存在，已原样输出，需要人工改写或在目标库中创建兼容函数", strings.ToUpper(n.FnName.O))
+	return false
+}
+
+// dateTemplate 将 MySQL 日期格式参数转换为 YSQL 模板，无法转换时记录问题
+func (e *Emitter) dateTemplate(n *ast.FuncCallExpr, arg ast.ExprNode) (string, bool) {
+	format, ok := stringArg(arg)
+	if !ok {
+		e.addIssue("%s 的格式参数不是字符串常量，无法转换为 YSQL 模板，已原样输出，需要人工改写", strings.ToUpper(n.FnName.O))
+		return "", false
+	}
+	tmpl, bad := convertDateFormat(format)
+	if bad != "" {
+		e.addIssue("%s 的格式符 %s 在 YSQL 中没有对应写法，已原样输出，需要人工改写", strings.ToUpper(n.FnName.O), bad)
+		return "", false
+	}
+	return tmpl, true
+}
+
+// temporalArg 输出日期函数的时间参数，字符串常量需显式转为 TIMESTAMP 才能匹配 TO_CHAR
+func (e *Emitter) temporalArg(arg ast.ExprNode) {
+	if _, ok := stringArg(arg); ok {
+		e.expr(arg)
+		e.w("::TIMESTAMP")
+		return
+	}
+	e.expr(arg)
+}
+
+// jsonExtract 将 JSON_EXTRACT(doc, '$.a[0]') 输出为 (doc #> '{a,0}')
+// 参数:
+//   - n: JSON_EXTRACT 调用节点
+//   - op: 路径运算符，#> 返回 JSONB，#>> 返回文本
+func (e *Emitter) jsonExtract(n *ast.FuncCallExpr, op string) bool {
+	if len(n.Args) != 2 {
+		e.addIssue("JSON_EXTRACT 的多路径查询在 YSQL 中没有对应写法，已原样输出，需要人工改写")
+		return false
+	}
+	path, ok := stringArg(n.Args[1])
+	if !ok {
+		e.addIssue("JSON_EXTRACT 的路径参数不是字符串常量，无法转换为 YSQL 路径运算符，已原样输出，需要人工改写")
+		return false
+	}
+	elems, ok := parseJSONPath(path)
+	if !ok {
+		e.addIssue("JSON 路径 %s 包含通配符或范围，在 YSQL 中没有对应写法，已原样输出，需要人工改写", path)
+		return false
+	}
+
+	e.w("(")
+	if _, isColumn := n.Args[0].(*ast.ColumnNameExpr); isColumn {
+		e.expr(n.Args[0])
+	} else {
+		e.w("(")
+		e.expr(n.Args[0])
+		e.w(")::JSONB")
+	}
+	e.w(op + QuoteString("{"+strings.Join(elems, ",")+"}") + ")")
+	return true
+}
+
+// stringArg 返回字符串常量参数的值
+func stringArg(arg ast.ExprNode) (string, bool) {
+	v, ok := arg.(*test_driver.ValueExpr)
+	if !ok || (v.Kind() != test_driver.KindString && v.Kind() != test_driver.KindBytes) {
+		return "", false
+	}
+	return v.GetString(), true
+}
+
+// hasTimeSpecifier 判断 MySQL 日期格式是否包含时间部分
+func hasTimeSpecifier(format string) bool {
+	for i := 0; i+1 < len(format); i++ {
+		if format[i] == '%' {
+			if strings.IndexByte(timeSpecifiers, format[i+1]) >= 0 {
+				return true
+			}
+			i++
+		}
+	}
+	return false
+}
+
+// convertDateFormat 将 MySQL 日期格式转换为 YSQL TO_CHAR 模板
+// 格式符之间的普通文本含字母时加双引号，避免被 YSQL 识别为模板模式
+// 参数:
+//   - format: MySQL 日期格式，如 "%Y-%m-%d %H:%i"
+//
+// 返回:
+//   - string: YSQL 模板
+//   - string: 无法转换的格式符，全部可转换时为空
+func convertDateFormat(format string) (string, string) {
+	var sb, text strings.Builder
+	flush := func() {
+		s := text.String()
+		text.Reset()
+		if strings.IndexFunc(s, unicode.IsLetter) < 0 {
+			sb.WriteString(s)
+			return
+		}
+		sb.WriteString(`"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`)
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			text.WriteByte(format[i])
+			continue
+		}
+		i++
+		spec := format[i]
+		if spec == '%' {
+			text.WriteByte('%')
+			continue
+		}
+		pattern, ok := dateFormatSpecifiers[spec]
+		if !ok {
+			return "", "%" + string(spec)
+		}
+		flush()
+		sb.WriteString(pattern)
+	}
+	flush()
+	return sb.String(), ""
+}
+
+// parseJSONPath 将 MySQL JSON 路径（如 $.a."b c"[0]）拆分为 YSQL 文本数组元素
+// 包含通配符 *、** 或 last/范围下标时返回 false
+func parseJSONPath(path string) ([]string, bool) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, false
+	}
+	elems := []string{}
+	for rest := path[1:]; rest != ""; {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			var key string
+			if strings.HasPrefix(rest, `"`) {
+				end := strings.IndexByte(rest[1:], '"')
+				if end < 0 {
+					return nil, false
+				}
+				key, rest = rest[1:end+1], rest[end+2:]
+			} else {
+				end := strings.IndexAny(rest, ".[")
+				if end < 0 {
+					end = len(rest)
+				}
+				key, rest = rest[:end], rest[end:]
+			}
+			if key == "" || strings.Contains(key, "*") {
+				return nil, false
+			}
+			elems = append(elems, arrayElem(key))
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, false
+			}
+			idx := strings.TrimSpace(rest[1:end])
+			if _, err := strconv.Atoi(idx); err != nil {
+				return nil, false
+			}
+			elems = append(elems, idx)
+			rest = rest[end+1:]
+		default:
+			return nil, false
+		}
+	}
+	return elems, true
+}
+
+// arrayElem 输出文本数组字面量中的一个元素，含特殊字符时加双引号
+func arrayElem(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+	}) < 0 {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
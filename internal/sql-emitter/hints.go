@@ -0,0 +1,266 @@
+package sqlemitter
+
+import (
+	"github.com/pingcap/tidb/pkg/parser/ast"
+)
+
+// Hints 检查器为 YSQL 输出提供的附加信息
+// TiDB AST 无法直接表达部分目标语法（如 SERIAL 类型、OFFSET ... FETCH 子句、ON CONFLICT 子句），
+// 检查器在转换时把这些意图记录在 Hints 中，由 Emitter 在输出时使用。
+//
+// 并发安全:
+//   - 该结构体不是并发安全的，与检查器的 Inspect 一样应在单个 goroutine 中使用
+type Hints struct {
+	columnTypes map[*ast.ColumnDef]string               // 列类型覆盖：输出时直接使用的 YSQL 类型名
+	enumTypes   map[*ast.ColumnDef]bool                 // 以 CREATE TYPE ... AS ENUM 定义类型的 ENUM 列
+	offsetFetch map[*ast.Limit]bool                     // 需要以 OFFSET ... FETCH 形式输出的 LIMIT 子句
+	onConflict  map[*ast.InsertStmt]OnConflict          // 需要输出 ON CONFLICT 子句的 INSERT 语句
+	joinedDML   map[ast.StmtNode]JoinedDML              // 改写为 UPDATE ... FROM、DELETE ... USING 的多表语句
+	triggers    map[ast.StmtNode]UpdateTrigger          // 在表定义之后输出 BEFORE UPDATE 触发器的 CREATE/ALTER TABLE 语句
+	indexes     map[ast.StmtNode][]*ast.CreateIndexStmt // 从 CREATE/ALTER TABLE 中拆分出的索引
+	typeMapping TypeMapping                             // 配置的类型转换表，为 nil 时使用默认转换表
+}
+
+// OnConflict INSERT 语句输出的 ON CONFLICT 子句
+type OnConflict struct {
+	Columns   []string // 冲突目标列，为空时不指定冲突目标（仅 DO NOTHING 可以省略）
+	DoNothing bool     // 输出 DO NOTHING；否则按语句的 ON DUPLICATE KEY UPDATE 赋值输出 DO UPDATE SET
+}
+
+// JoinedDML 多表 UPDATE/DELETE 改写后的结构
+// 目标表输出在 UPDATE/DELETE FROM 之后，其余表输出在 FROM/USING 中，连接条件与 WHERE 条件以 AND 合并。
+// 字段引用原语句中的节点，检查器对子节点的转换在输出时仍然生效。
+type JoinedDML struct {
+	Target     *ast.TableSource   // 被修改的表
+	Tables     []*ast.TableSource // 其余参与连接的表
+	Conditions []*ast.OnCondition // 连接条件
+}
+
+// UpdateTrigger 模拟 ON UPDATE CURRENT_TIMESTAMP 的 BEFORE UPDATE 触发器
+// 函数与表位于同一个 schema。名称由检查器按表名确定，重复执行转换结果时
+// CREATE OR REPLACE FUNCTION 与 DROP TRIGGER IF EXISTS 保证语句可以重复执行。
+type UpdateTrigger struct {
+	Function string            // 触发器函数名
+	Trigger  string            // 触发器名
+	Columns  []*ast.Assignment // 行被修改且未显式赋值时更新的列及其表达式，为空时删除触发器与函数
+}
+
+// NewHints 创建空的输出提示
+func NewHints() *Hints {
+	return &Hints{
+		columnTypes: make(map[*ast.ColumnDef]string),
+		enumTypes:   make(map[*ast.ColumnDef]bool),
+		offsetFetch: make(map[*ast.Limit]bool),
+		onConflict:  make(map[*ast.InsertStmt]OnConflict),
+		joinedDML:   make(map[ast.StmtNode]JoinedDML),
+		triggers:    make(map[ast.StmtNode]UpdateTrigger),
+		indexes:     make(map[ast.StmtNode][]*ast.CreateIndexStmt),
+	}
+}
+
+// SetColumnType 指定列在 YSQL 中输出的类型名
+// 参数:
+//   - col: 列定义节点
+//   - typeName: YSQL 类型名（如 "SERIAL"、"BIGSERIAL"）
+func (h *Hints) SetColumnType(col *ast.ColumnDef, typeName string) {
+	if h == nil || col == nil {
+		return
+	}
+	h.columnTypes[col] = typeName
+}
+
+// ColumnType 返回列的类型覆盖
+// 返回:
+//   - string: YSQL 类型名
+//   - bool: 是否存在覆盖
+func (h *Hints) ColumnType(col *ast.ColumnDef) (string, bool) {
+	if h == nil {
+		return "", false
+	}
+	typeName, ok := h.columnTypes[col]
+	return typeName, ok
+}
+
+// UseEnumType 标记 ENUM 列使用 YSQL 枚举类型
+// 输出时在语句之前生成 CREATE TYPE <表名>_<列名> AS ENUM (...)，列类型使用该枚举类型
+func (h *Hints) UseEnumType(col *ast.ColumnDef) {
+	if h == nil || col == nil {
+		return
+	}
+	h.enumTypes[col] = true
+}
+
+// EnumType 判断 ENUM 列是否使用 YSQL 枚举类型
+func (h *Hints) EnumType(col *ast.ColumnDef) bool {
+	if h == nil {
+		return false
+	}
+	return h.enumTypes[col]
+}
+
+// UseOffsetFetch 标记 LIMIT 子句以 OFFSET ... ROWS FETCH NEXT ... ROWS ONLY 形式输出
+func (h *Hints) UseOffsetFetch(limit *ast.Limit) {
+	if h == nil || limit == nil {
+		return
+	}
+	h.offsetFetch[limit] = true
+}
+
+// OffsetFetch 判断 LIMIT 子句是否需要以 OFFSET ... FETCH 形式输出
+func (h *Hints) OffsetFetch(limit *ast.Limit) bool {
+	if h == nil {
+		return false
+	}
+	return h.offsetFetch[limit]
+}
+
+// SetOnConflict 指定 INSERT 语句输出的 ON CONFLICT 子句
+// 参数:
+//   - insert: INSERT 语句节点
+//   - clause: ON CONFLICT 子句的冲突目标与动作
+func (h *Hints) SetOnConflict(insert *ast.InsertStmt, clause OnConflict) {
+	if h == nil || insert == nil {
+		return
+	}
+	h.onConflict[insert] = clause
+}
+
+// OnConflict 返回 INSERT 语句的 ON CONFLICT 子句
+// 返回:
+//   - OnConflict: ON CONFLICT 子句
+//   - bool: 是否需要输出 ON CONFLICT 子句
+func (h *Hints) OnConflict(insert *ast.InsertStmt) (OnConflict, bool) {
+	if h == nil {
+		return OnConflict{}, false
+	}
+	clause, ok := h.onConflict[insert]
+	return clause, ok
+}
+
+// SetJoinedDML 指定多表 UPDATE/DELETE 改写为 UPDATE ... FROM、DELETE ... USING 输出
+// 参数:
+//   - stmt: *ast.UpdateStmt 或 *ast.DeleteStmt
+//   - joined: 改写后的目标表、其余表与连接条件
+func (h *Hints) SetJoinedDML(stmt ast.StmtNode, joined JoinedDML) {
+	if h == nil || stmt == nil {
+		return
+	}
+	h.joinedDML[stmt] = joined
+}
+
+// JoinedDML 返回多表 UPDATE/DELETE 的改写结构
+// 返回:
+//   - JoinedDML: 改写后的结构
+//   - bool: 是否需要改写输出
+func (h *Hints) JoinedDML(stmt ast.StmtNode) (JoinedDML, bool) {
+	if h == nil {
+		return JoinedDML{}, false
+	}
+	joined, ok := h.joinedDML[stmt]
+	return joined, ok
+}
+
+// SetUpdateTrigger 指定在 CREATE/ALTER TABLE 语句之后输出的 BEFORE UPDATE 触发器
+// 参数:
+//   - stmt: *ast.CreateTableStmt 或 *ast.AlterTableStmt
+//   - trigger: 触发器函数名、触发器名与更新的列
+func (h *Hints) SetUpdateTrigger(stmt ast.StmtNode, trigger UpdateTrigger) {
+	if h == nil || stmt == nil {
+		return
+	}
+	h.triggers[stmt] = trigger
+}
+
+// UpdateTrigger 返回 CREATE/ALTER TABLE 语句之后输出的触发器
+// 返回:
+//   - UpdateTrigger: 触发器
+//   - bool: 是否需要输出触发器
+func (h *Hints) UpdateTrigger(stmt ast.StmtNode) (UpdateTrigger, bool) {
+	if h == nil {
+		return UpdateTrigger{}, false
+	}
+	trigger, ok := h.triggers[stmt]
+	return trigger, ok
+}
+
+// SetIndexes 指定在 CREATE/ALTER TABLE 语句之后输出的 CREATE INDEX 语句
+// YSQL 的 CREATE TABLE 只能内联 PRIMARY KEY 与 UNIQUE 约束，普通索引需要拆分为单独的语句
+// 参数:
+//   - stmt: *ast.CreateTableStmt 或 *ast.AlterTableStmt
+//   - indexes: 按输出顺序排列的索引
+func (h *Hints) SetIndexes(stmt ast.StmtNode, indexes []*ast.CreateIndexStmt) {
+	if h == nil || stmt == nil {
+		return
+	}
+	h.indexes[stmt] = indexes
+}
+
+// Indexes 返回 CREATE/ALTER TABLE 语句之后输出的 CREATE INDEX 语句
+func (h *Hints) Indexes(stmt ast.StmtNode) []*ast.CreateIndexStmt {
+	if h == nil {
+		return nil
+	}
+	return h.indexes[stmt]
+}
+
+// SetTypeMapping 指定输出列类型使用的类型转换表
+// 类型转换表来自配置而不是单条语句的转换结果，不随 Reset 清空
+func (h *Hints) SetTypeMapping(mapping TypeMapping) {
+	if h == nil {
+		return
+	}
+	h.typeMapping = mapping
+}
+
+// TypeMapping 返回输出列类型使用的类型转换表，未指定时返回默认转换表
+func (h *Hints) TypeMapping() TypeMapping {
+	if h == nil || h.typeMapping == nil {
+		return defaultTypeMapping
+	}
+	return h.typeMapping
+}
+
+// Merge 合并另一组提示，相同节点以 other 中的值为准
+func (h *Hints) Merge(other *Hints) {
+	if h == nil || other == nil {
+		return
+	}
+	for col, typeName := range other.columnTypes {
+		h.columnTypes[col] = typeName
+	}
+	for col := range other.enumTypes {
+		h.enumTypes[col] = true
+	}
+	for limit := range other.offsetFetch {
+		h.offsetFetch[limit] = true
+	}
+	for insert, clause := range other.onConflict {
+		h.onConflict[insert] = clause
+	}
+	for stmt, joined := range other.joinedDML {
+		h.joinedDML[stmt] = joined
+	}
+	for stmt, trigger := range other.triggers {
+		h.triggers[stmt] = trigger
+	}
+	for stmt, indexes := range other.indexes {
+		h.indexes[stmt] = indexes
+	}
+	if other.typeMapping != nil {
+		h.typeMapping = other.typeMapping
+	}
+}
+
+// Reset 清空所有语句级的提示，类型转换表保留
+func (h *Hints) Reset() {
+	if h == nil {
+		return
+	}
+	clear(h.columnTypes)
+	clear(h.enumTypes)
+	clear(h.offsetFetch)
+	clear(h.onConflict)
+	clear(h.joinedDML)
+	clear(h.triggers)
+	clear(h.indexes)
+}
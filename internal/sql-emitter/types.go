@@ -0,0 +1,227 @@
+package sqlemitter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/types"
+)
+
+// 类型模板中的占位符，替换为原类型的长度、精度、小数位数与小数秒精度
+const (
+	TypeParamLength    = "length"    // CHAR/VARCHAR/BINARY/VARBINARY/BIT 的长度，CHAR 与 BINARY 未指定时为 1
+	TypeParamPrecision = "precision" // DECIMAL 的精度，未指定时为 MySQL 默认的 10；FLOAT/DOUBLE 仅在指定时有值
+	TypeParamScale     = "scale"     // DECIMAL/FLOAT/DOUBLE 的小数位数，为 0 或未指定时没有值
+	TypeParamFsp       = "fsp"       // DATETIME/TIMESTAMP/TIME 的小数秒精度，未指定时为 MySQL 默认的 0
+)
+
+// typeParamRe 匹配类型模板中的占位符及其前面的逗号
+var typeParamRe = regexp.MustCompile(`(,?)\$\{(\w+)\}`)
+
+// TypeMapping MySQL 类型到 YSQL 类型的转换表
+// key 为 MySQL 类型名（见 TypeName），如 DATETIME、INT UNSIGNED、BIT(1)；
+// value 为 YSQL 类型模板，其中的 ${length}、${precision}、${scale}、${fsp} 替换为原类型的对应值，
+// 没有值的占位符连同前面的逗号一起省略，省略后为空的括号也一并省略，
+// 例如 NUMERIC(${precision},${scale}) 对 DECIMAL(18,4) 输出 NUMERIC(18,4)，对 DECIMAL(20) 输出 NUMERIC(20)。
+type TypeMapping map[string]string
+
+// defaultTypeMapping 默认的类型转换表
+// MySQL 的默认长度、精度与小数秒精度显式写出，避免 YSQL 按自己的默认值（如 TIMESTAMP 默认 6 位小数秒）处理。
+var defaultTypeMapping = TypeMapping{
+	"TINYINT":            "SMALLINT", // YSQL 没有 TINYINT，最小的整数类型为 SMALLINT
+	"TINYINT UNSIGNED":   "SMALLINT",
+	"SMALLINT":           "SMALLINT",
+	"SMALLINT UNSIGNED":  "INTEGER", // YSQL 没有无符号整数，UNSIGNED 列扩宽为下一级类型以容纳其上限
+	"MEDIUMINT":          "INTEGER",
+	"MEDIUMINT UNSIGNED": "INTEGER",
+	"INT":                "INTEGER",
+	"INT UNSIGNED":       "BIGINT",
+	"BIGINT":             "BIGINT",
+	"BIGINT UNSIGNED":    "NUMERIC(20)",
+	"FLOAT":              "REAL",
+	"DOUBLE":             "DOUBLE PRECISION",
+	"DECIMAL":            "NUMERIC(${precision},${scale})",
+	"DATE":               "DATE",
+	"DATETIME":           "TIMESTAMP(${fsp})",
+	"TIMESTAMP":          "TIMESTAMP(${fsp})",
+	"TIME":               "TIME(${fsp})",
+	"YEAR":               "SMALLINT",
+	"CHAR":               "CHAR(${length})",
+	"VARCHAR":            "VARCHAR(${length})",
+	"BINARY":             "BYTEA",
+	"VARBINARY":          "BYTEA",
+	"TINYTEXT":           "TEXT",
+	"TEXT":               "TEXT",
+	"MEDIUMTEXT":         "TEXT",
+	"LONGTEXT":           "TEXT",
+	"TINYBLOB":           "BYTEA",
+	"BLOB":               "BYTEA",
+	"MEDIUMBLOB":         "BYTEA",
+	"LONGBLOB":           "BYTEA",
+	"JSON":               "JSONB",
+	"BIT":                "BIT(${length})",
+	"BIT(1)":             "BOOLEAN",
+}
+
+// overridableTypes 默认转换表之外可以在配置中指定的类型，未配置时输出为 TEXT 并记录问题
+var overridableTypes = map[string]bool{"ENUM": true, "SET": true, "GEOMETRY": true}
+
+// NewTypeMapping 以默认转换表为基础创建类型转换表
+// 参数:
+//   - overrides: 覆盖默认值的条目，key 不区分大小写
+//
+// 返回:
+//   - TypeMapping: 合并后的类型转换表
+//   - error: key 不是已知的 MySQL 类型名或 value 为空时返回错误
+func NewTypeMapping(overrides map[string]string) (TypeMapping, error) {
+	mapping := make(TypeMapping, len(defaultTypeMapping)+len(overrides))
+	for name, template := range defaultTypeMapping {
+		mapping[name] = template
+	}
+	for name, template := range overrides {
+		key := strings.Join(strings.Fields(strings.ToUpper(name)), " ")
+		if _, ok := defaultTypeMapping[key]; !ok && !overridableTypes[key] {
+			return nil, fmt.Errorf("未知的 MySQL 类型: %s", name)
+		}
+		if strings.TrimSpace(template) == "" {
+			return nil, fmt.Errorf("MySQL 类型 %s 的目标类型不能为空", name)
+		}
+		mapping[key] = strings.TrimSpace(template)
+	}
+	return mapping, nil
+}
+
+// TypeName 返回类型转换表中 MySQL 类型的名称
+// 整数类型带 UNSIGNED 后缀，二进制字符集的字符串类型为 BINARY/VARBINARY/*BLOB，
+// 其余字符串类型为 CHAR/VARCHAR/*TEXT，长度为 1 的 BIT 为 BIT(1)。
+// 返回:
+//   - string: 类型名，未知类型返回空字符串
+func TypeName(tp *types.FieldType) string {
+	if tp == nil {
+		return ""
+	}
+	binary := tp.GetCharset() == charset.CharsetBin
+	pick := func(text, bin string) string {
+		if binary {
+			return bin
+		}
+		return text
+	}
+
+	var name string
+	switch tp.GetType() {
+	case mysql.TypeTiny:
+		name = "TINYINT"
+	case mysql.TypeShort:
+		name = "SMALLINT"
+	case mysql.TypeInt24:
+		name = "MEDIUMINT"
+	case mysql.TypeLong:
+		name = "INT"
+	case mysql.TypeLonglong:
+		name = "BIGINT"
+	case mysql.TypeFloat:
+		return "FLOAT"
+	case mysql.TypeDouble:
+		return "DOUBLE"
+	case mysql.TypeNewDecimal:
+		return "DECIMAL"
+	case mysql.TypeDate:
+		return "DATE"
+	case mysql.TypeDatetime:
+		return "DATETIME"
+	case mysql.TypeTimestamp:
+		return "TIMESTAMP"
+	case mysql.TypeDuration:
+		return "TIME"
+	case mysql.TypeYear:
+		return "YEAR"
+	case mysql.TypeString:
+		return pick("CHAR", "BINARY")
+	case mysql.TypeVarchar, mysql.TypeVarString:
+		return pick("VARCHAR", "VARBINARY")
+	case mysql.TypeTinyBlob:
+		return pick("TINYTEXT", "TINYBLOB")
+	case mysql.TypeBlob:
+		return pick("TEXT", "BLOB")
+	case mysql.TypeMediumBlob:
+		return pick("MEDIUMTEXT", "MEDIUMBLOB")
+	case mysql.TypeLongBlob:
+		return pick("LONGTEXT", "LONGBLOB")
+	case mysql.TypeJSON:
+		return "JSON"
+	case mysql.TypeBit:
+		if tp.GetFlen() == 1 {
+			return "BIT(1)"
+		}
+		return "BIT"
+	case mysql.TypeEnum:
+		return "ENUM"
+	case mysql.TypeSet:
+		return "SET"
+	case mysql.TypeGeometry:
+		return "GEOMETRY"
+	default:
+		return ""
+	}
+	if mysql.HasUnsignedFlag(tp.GetFlag()) {
+		name += " UNSIGNED"
+	}
+	return name
+}
+
+// FormatType 按类型模板输出 YSQL 类型，占位符替换为原类型的长度、精度、小数位数与小数秒精度
+// 参数:
+//   - template: YSQL 类型模板，如 TIMESTAMP(${fsp})，不含占位符时原样返回
+//   - tp: 原 MySQL 类型
+//
+// 返回:
+//   - string: YSQL 类型
+func FormatType(template string, tp *types.FieldType) string {
+	if !strings.Contains(template, "${") {
+		return template
+	}
+	params := typeParams(tp)
+	formatted := typeParamRe.ReplaceAllStringFunc(template, func(m string) string {
+		sub := typeParamRe.FindStringSubmatch(m)
+		if value := params[sub[2]]; value != "" {
+			return sub[1] + value
+		}
+		return ""
+	})
+	return strings.ReplaceAll(formatted, "()", "")
+}
+
+// typeParams 返回类型模板占位符的取值，没有值的占位符不在结果中
+func typeParams(tp *types.FieldType) map[string]string {
+	params := make(map[string]string)
+	if tp == nil {
+		return params
+	}
+	flen, decimal := tp.GetFlen(), tp.GetDecimal()
+	switch tp.GetType() {
+	case mysql.TypeString:
+		params[TypeParamLength] = strconv.Itoa(max(flen, 1))
+	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeBit:
+		if flen > 0 {
+			params[TypeParamLength] = strconv.Itoa(flen)
+		}
+	case mysql.TypeNewDecimal:
+		params[TypeParamPrecision] = "10"
+		fallthrough
+	case mysql.TypeFloat, mysql.TypeDouble:
+		if flen > 0 {
+			params[TypeParamPrecision] = strconv.Itoa(flen)
+		}
+		if flen > 0 && decimal > 0 {
+			params[TypeParamScale] = strconv.Itoa(decimal)
+		}
+	case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
+		params[TypeParamFsp] = strconv.Itoa(max(decimal, 0))
+	}
+	return params
+}
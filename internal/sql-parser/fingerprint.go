@@ -0,0 +1,26 @@
+package sqlparser
+
+import (
+	pparser "github.com/pingcap/tidb/pkg/parser"
+)
+
+// fingerprintLength 指纹保留的十六进制字符数
+const fingerprintLength = 16
+
+// Fingerprint 计算语句的规范化形式和指纹
+// 规范化使用 TiDB 的 digester：字面量替换为 ?，IN 列表和多行 VALUES 折叠为 ...，
+// 关键字转为小写，空白和注释被移除。只有字面量不同的语句得到相同的指纹。
+// 参数:
+//   - sql: 语句文本
+//
+// 返回:
+//   - normalized: 规范化后的语句，如 "select * from `t` where `id` = ?"
+//   - fingerprint: 规范化语句摘要的前 fingerprintLength 个十六进制字符
+func Fingerprint(sql string) (normalized, fingerprint string) {
+	normalized, digest := pparser.NormalizeDigest(sql)
+	fingerprint = digest.String()
+	if len(fingerprint) > fingerprintLength {
+		fingerprint = fingerprint[:fingerprintLength]
+	}
+	return normalized, fingerprint
+}
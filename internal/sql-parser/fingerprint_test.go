@@ -0,0 +1,50 @@
+package sqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFingerprint 测试语句规范化与指纹
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name       string
+		a, b       string
+		same       bool
+		normalized string
+	}{
+		{
+			name:       "只有字面量不同",
+			a:          "SELECT * FROM t WHERE id = 42 AND name = 'x'",
+			b:          "select *  from t\nwhere id=43 and name = \"y\"",
+			same:       true,
+			normalized: "select * from `t` where `id` = ? and `name` = ?",
+		},
+		{
+			name:       "多行 VALUES 折叠",
+			a:          "INSERT INTO t VALUES (1, 'a'), (2, 'b')",
+			b:          "INSERT INTO t VALUES (3, 'c'), (4, 'd'), (5, 'e')",
+			same:       true,
+			normalized: "insert into `t` values ( ... )",
+		},
+		{
+			name:       "结构不同",
+			a:          "SELECT a FROM t WHERE id = 1",
+			b:          "SELECT b FROM t WHERE id = 1",
+			same:       false,
+			normalized: "select `a` from `t` where `id` = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, fa := Fingerprint(tt.a)
+			_, fb := Fingerprint(tt.b)
+
+			assert.Equal(t, tt.normalized, normalized)
+			assert.Len(t, fa, fingerprintLength)
+			assert.Equal(t, tt.same, fa == fb)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package sqlparser
+
+import (
+	"strings"
+)
+
+// Placeholders 返回预处理语句中 ? 占位符的字节偏移量
+// 字符串、带引号的标识符、注释和优化器提示中的 ? 不是占位符。
+// 参数:
+//   - sql: 预处理语句文本
+//
+// 返回:
+//   - []int: 按出现顺序排列的占位符偏移量
+func Placeholders(sql string) []int {
+	var (
+		offsets []int
+		lex     lexer
+	)
+	for i := 0; i < len(sql); {
+		n, kind := lex.next(sql[i:])
+		if kind == tokenCode && sql[i] == '?' {
+			offsets = append(offsets, i)
+		}
+		i += n
+	}
+	return offsets
+}
+
+// ReplacePlaceholders 将预处理语句中的 ? 占位符替换为指定文本
+// 用于 TiDB 解析器不接受占位符的位置（如 DDL 中的 DEFAULT ?），替换后语句结构不变。
+// 参数:
+//   - sql: 预处理语句文本
+//   - replacement: 替换文本，如 NULL
+//
+// 返回:
+//   - string: 替换后的语句
+//   - int: 替换的占位符数量
+func ReplacePlaceholders(sql, replacement string) (string, int) {
+	offsets := Placeholders(sql)
+	if len(offsets) == 0 {
+		return sql, 0
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, off := range offsets {
+		sb.WriteString(sql[last:off])
+		sb.WriteString(replacement)
+		last = off + 1
+	}
+	sb.WriteString(sql[last:])
+	return sb.String(), len(offsets)
+}
@@ -0,0 +1,45 @@
+package sqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReplacePlaceholders 测试占位符识别与替换
+func TestReplacePlaceholders(t *testing.T) {
+	tests := []struct {
+		name      string
+		sql       string
+		wantSQL   string
+		wantCount int
+	}{
+		{
+			name:      "普通占位符",
+			sql:       "SELECT * FROM t WHERE a = ? AND b IN (?, ?)",
+			wantSQL:   "SELECT * FROM t WHERE a = NULL AND b IN (NULL, NULL)",
+			wantCount: 3,
+		},
+		{
+			name:      "字符串和注释中的问号",
+			sql:       "SELECT 'a?b', `c?` FROM t -- why?\nWHERE x = ? /* ? */",
+			wantSQL:   "SELECT 'a?b', `c?` FROM t -- why?\nWHERE x = NULL /* ? */",
+			wantCount: 1,
+		},
+		{
+			name:      "没有占位符",
+			sql:       "SELECT 1",
+			wantSQL:   "SELECT 1",
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, n := ReplacePlaceholders(tt.sql, "NULL")
+			assert.Equal(t, tt.wantSQL, got)
+			assert.Equal(t, tt.wantCount, n)
+			assert.Len(t, Placeholders(tt.sql), tt.wantCount)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package sqlparser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSnippetRunes 源码片段的最大字符数
+const maxSnippetRunes = 120
+
+// parseErrorPosRe 匹配 TiDB 解析错误中的位置信息，如 "line 3 column 10 near ..."
+var parseErrorPosRe = regexp.MustCompile(`line (\d+) column (\d+)`)
+
+// ParseErrorPosition 从 TiDB 解析错误中提取行号和列号
+// 参数:
+//   - err: ParseSQL 返回的错误
+//
+// 返回:
+//   - line: 错误所在行（相对于被解析的文本，从 1 开始）
+//   - column: 错误所在列
+//   - ok: 错误中是否包含位置信息
+func ParseErrorPosition(err error) (line, column int, ok bool) {
+	if err == nil {
+		return 0, 0, false
+	}
+	m := parseErrorPosRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, 0, false
+	}
+	line, _ = strconv.Atoi(m[1])
+	column, _ = strconv.Atoi(m[2])
+	return line, column, true
+}
+
+// OffsetPosition 将文本中的字节偏移量换算为行列号
+// 参数:
+//   - text: 文本
+//   - offset: 字节偏移量，超出范围时按文本边界处理
+//
+// 返回:
+//   - line: 行号（从 1 开始）
+//   - column: 列号（从 1 开始，按字符计）
+func OffsetPosition(text string, offset int) (line, column int) {
+	offset = max(0, min(offset, len(text)))
+	before := text[:offset]
+	lineStart := strings.LastIndexByte(before, '\n') + 1
+	return strings.Count(before, "\n") + 1, utf8.RuneCountInString(before[lineStart:]) + 1
+}
+
+// LineOffset 将行列号换算为文本中的字节偏移量
+// 参数:
+//   - text: 文本
+//   - line: 行号（从 1 开始）
+//   - column: 列号（从 1 开始，按字符计）
+//
+// 返回:
+//   - int: 字节偏移量，超出范围时返回最近的有效位置
+func LineOffset(text string, line, column int) int {
+	offset := 0
+	for ; line > 1; line-- {
+		i := strings.IndexByte(text[offset:], '\n')
+		if i < 0 {
+			return len(text)
+		}
+		offset += i + 1
+	}
+	for ; column > 1 && offset < len(text) && text[offset] != '\n'; column-- {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// Snippet 提取 [start, end) 范围内的源码片段
+// 参数:
+//   - text: 文本
+//   - start: 片段起始的字节偏移量
+//   - end: 片段结束的字节偏移量，超出范围时按文本边界处理
+//
+// 返回:
+//   - string: 去除首尾空白的源码片段，最长 maxSnippetRunes 个字符
+func Snippet(text string, start, end int) string {
+	end = min(end, len(text))
+	if start < 0 || start >= end {
+		return ""
+	}
+	snippet := strings.TrimSpace(text[start:end])
+	runes := 0
+	for i := range snippet {
+		if runes == maxSnippetRunes {
+			return strings.TrimSpace(snippet[:i])
+		}
+		runes++
+	}
+	return snippet
+}
+
+// ClauseEnd 返回从偏移量开始的子句在同一行内的结束位置
+// 遇到括号外的逗号或分号、不匹配的右括号或行尾时结束，适用于列定义、表级约束等以逗号分隔的子句，
+// 以及解析失败时没有语法节点可用的出错行。
+// 参数:
+//   - text: 文本
+//   - offset: 子句起始的字节偏移量
+//
+// 返回:
+//   - int: 子句结束的字节偏移量
+func ClauseEnd(text string, offset int) int {
+	if offset < 0 || offset >= len(text) {
+		return offset
+	}
+
+	depth := 0
+	var quote rune
+	for i, r := range text[offset:] {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '\n':
+			return offset + i
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth == 0 {
+				return offset + i
+			}
+			depth--
+		case (r == ',' || r == ';') && depth == 0:
+			return offset + i
+		}
+	}
+	return len(text)
+}
+
+// TokenEnd 返回从偏移量开始的记号的结束位置
+// 记号为引号括起的字符串或标识符、括号括起的整组内容，或由字母、数字、下划线、点组成的名称与数值；
+// 其他字符作为单个字符的记号。
+// 参数:
+//   - text: 文本
+//   - offset: 记号起始的字节偏移量
+//
+// 返回:
+//   - int: 记号结束的字节偏移量
+func TokenEnd(text string, offset int) int {
+	if offset < 0 || offset >= len(text) {
+		return offset
+	}
+	switch c := text[offset]; {
+	case c == '\'' || c == '"' || c == '`':
+		return quoteEnd(text, offset)
+	case c == '(':
+		return CloseParens(text, offset, offset+1)
+	case isNameByte(c):
+		end := offset
+		for end < len(text) && (isNameByte(text[end]) || text[end] == '.') {
+			end++
+		}
+		return end
+	}
+	_, size := utf8.DecodeRuneInString(text[offset:])
+	return offset + size
+}
+
+// CloseParens 补齐 [start, end) 范围内未闭合的括号
+// 参数:
+//   - text: 文本
+//   - start: 范围起始的字节偏移量
+//   - end: 范围结束的字节偏移量
+//
+// 返回:
+//   - int: 范围内的括号全部闭合后的结束位置，文本中没有对应的右括号时为文本末尾
+func CloseParens(text string, start, end int) int {
+	depth := 0
+	for i := start; i < len(text); i++ {
+		if i >= end && depth == 0 {
+			return i
+		}
+		switch text[i] {
+		case '\'', '"', '`':
+			i = quoteEnd(text, i) - 1
+		case '(':
+			depth++
+		case ')':
+			depth = max(depth-1, 0)
+		}
+	}
+	return len(text)
+}
+
+// quoteEnd 返回从引号开始的字符串或标识符的结束位置，支持重复引号与反斜杠转义
+func quoteEnd(text string, offset int) int {
+	quote := text[offset]
+	for i := offset + 1; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && quote != '`':
+			i++
+		case text[i] == quote:
+			if i+1 < len(text) && text[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(text)
+}
+
+// isNameByte 判断字节是否可以组成名称或数值（含 @ 变量与多字节字符）
+func isNameByte(c byte) bool {
+	return c == '_' || c == '$' || c == '@' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= utf8.RuneSelf
+}
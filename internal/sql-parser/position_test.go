@@ -0,0 +1,99 @@
+package sqlparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseErrorPosition 测试解析错误位置提取
+func TestParseErrorPosition(t *testing.T) {
+	p := NewSQLParser()
+
+	_, err := p.ParseSQL("SELECT 1;\nSELECT * FROM\n  t WHERE")
+	require.Error(t, err)
+	line, column, ok := ParseErrorPosition(err)
+	require.True(t, ok)
+	assert.Equal(t, 3, line)
+	assert.Equal(t, 10, column)
+
+	_, _, ok = ParseErrorPosition(errors.New("other error"))
+	assert.False(t, ok)
+
+	_, _, ok = ParseErrorPosition(nil)
+	assert.False(t, ok)
+}
+
+// TestOffsetPosition 测试偏移量与行列号互相换算
+func TestOffsetPosition(t *testing.T) {
+	text := "SELECT '中文',\n  name FROM t"
+
+	tests := []struct {
+		name   string
+		offset int
+		line   int
+		column int
+	}{
+		{name: "文本开头", offset: 0, line: 1, column: 1},
+		{name: "多字节字符之后", offset: len("SELECT '中文'"), line: 1, column: 12},
+		{name: "第二行", offset: len("SELECT '中文',\n  "), line: 2, column: 3},
+		{name: "超出范围", offset: len(text) + 10, line: 2, column: 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, column := OffsetPosition(text, tt.offset)
+			assert.Equal(t, tt.line, line)
+			assert.Equal(t, tt.column, column)
+			if tt.offset <= len(text) {
+				assert.Equal(t, tt.offset, LineOffset(text, line, column))
+			}
+		})
+	}
+}
+
+// TestSnippet 测试源码片段提取
+func TestSnippet(t *testing.T) {
+	text := "CREATE TABLE t (\n  id INT AUTO_INCREMENT,\n  total DECIMAL(10, 2) DEFAULT '0,0'\n)"
+
+	tests := []struct {
+		name     string
+		offset   int
+		expected string
+	}{
+		{name: "在行尾结束", offset: 0, expected: "CREATE TABLE t ("},
+		{name: "在逗号处结束", offset: len("CREATE TABLE t (\n  "), expected: "id INT AUTO_INCREMENT"},
+		{name: "括号和字符串中的逗号", offset: len("CREATE TABLE t (\n  id INT AUTO_INCREMENT,\n  "), expected: "total DECIMAL(10, 2) DEFAULT '0,0'"},
+		{name: "越界", offset: -1, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Snippet(text, tt.offset, ClauseEnd(text, tt.offset)))
+		})
+	}
+
+	t.Run("截断过长的片段", func(t *testing.T) {
+		long := strings.Repeat("字", maxSnippetRunes+10)
+		assert.Equal(t, strings.Repeat("字", maxSnippetRunes), Snippet(long, 0, len(long)))
+	})
+}
+
+// TestTokenEnd 测试记号与括号范围的计算
+func TestTokenEnd(t *testing.T) {
+	text := "SELECT IFNULL(t.name, 'a)''b'), `x y` FROM t"
+
+	assert.Equal(t, "IFNULL", text[7:TokenEnd(text, 7)])
+	assert.Equal(t, "t.name", text[14:TokenEnd(text, 14)])
+	assert.Equal(t, "'a)''b'", text[22:TokenEnd(text, 22)], "引号内的括号与重复引号")
+	assert.Equal(t, "(t.name, 'a)''b')", text[13:TokenEnd(text, 13)], "括号整组")
+	assert.Equal(t, "`x y`", text[32:TokenEnd(text, 32)])
+	assert.Equal(t, ",", text[30:TokenEnd(text, 30)])
+
+	// 从函数名开始、到最后一个参数为止的范围补齐右括号
+	assert.Equal(t, "IFNULL(t.name, 'a)''b')", text[7:CloseParens(text, 7, 29)])
+	assert.Equal(t, 7, CloseParens(text, 0, 7), "没有未闭合的括号时不变")
+}
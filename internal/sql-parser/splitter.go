@@ -0,0 +1,277 @@
+package sqlparser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// DefaultDelimiter 默认语句分隔符
+const DefaultDelimiter = ";"
+
+// Statement 切分后的单条 SQL 语句
+type Statement struct {
+	Text   string // 语句文本，不含结尾分隔符
+	Line   int    // 语句在原始文本中的起始行号（从 1 开始）
+	Column int    // 语句在原始文本中的起始列号（从 1 开始，按字符计）
+}
+
+// delimiterCommandRe 匹配 mysql 客户端的 DELIMITER 命令
+var delimiterCommandRe = regexp.MustCompile(`(?i)^delimiter[ \t]+(\S+)`)
+
+// SplitStatements 将 SQL 文本切分为独立的语句
+// 切分时识别单引号、双引号、反引号字符串，--、# 和 /* */ 注释，
+// 以及 mysql 客户端的 DELIMITER 命令，分隔符出现在字符串或注释中时不会切分。
+// 参数:
+//   - sql: 原始 SQL 文本，可能包含多条语句
+//
+// 返回:
+//   - []Statement: 按出现顺序排列的语句，仅包含注释或空白的片段会被丢弃
+//
+// 注意:
+//   - /*! ... */ 版本注释和 /*+ ... */ 优化器提示视为语句内容而非注释
+//   - DELIMITER 命令本身不会作为语句返回
+//   - 大文件请使用 StatementScanner 逐条读取，避免一次性载入内存
+func SplitStatements(sql string) []Statement {
+	var stmts []Statement
+	s := NewStatementScanner(strings.NewReader(sql))
+	for s.Scan() {
+		stmts = append(stmts, s.Statement())
+	}
+	return stmts
+}
+
+// StatementScanner 从 io.Reader 中逐条读取 SQL 语句
+// 切分规则与 SplitStatements 相同。输入按行读取，行长度不受 bufio.Scanner 的 64KB 限制，
+// 任意时刻只在内存中保留当前行和当前语句，适合处理多 GB 的 mysqldump 文件。
+//
+// 使用方式与 bufio.Scanner 一致：
+//
+//	s := NewStatementScanner(file)
+//	for s.Scan() {
+//		stmt := s.Statement()
+//	}
+//	if err := s.Err(); err != nil { ... }
+type StatementScanner struct {
+	r         *bufio.Reader
+	lex       lexer
+	delimiter string
+	line      int // 当前行号
+
+	text                strings.Builder // 当前语句已读取的文本
+	started             bool            // 当前语句是否已遇到内容
+	startLine, startCol int
+
+	pending   []Statement                 // 已切分、尚未返回的语句
+	onComment func(line int, text string) // 单行注释回调
+	current   Statement
+	eof       bool
+	err       error
+}
+
+// NewStatementScanner 创建语句读取器
+// 参数:
+//   - r: SQL 输入
+//
+// 返回:
+//   - *StatementScanner: 语句读取器，初始分隔符为 DefaultDelimiter
+func NewStatementScanner(r io.Reader) *StatementScanner {
+	return &StatementScanner{r: bufio.NewReader(r), delimiter: DefaultDelimiter}
+}
+
+// Scan 读取下一条语句，没有更多语句或发生读取错误时返回 false
+func (s *StatementScanner) Scan() bool {
+	for len(s.pending) == 0 && !s.eof {
+		line, err := s.r.ReadString('\n')
+		if line != "" {
+			s.line++
+			s.scanLine(line)
+		}
+		switch {
+		case err == io.EOF:
+			s.eof = true
+			s.finish()
+		case err != nil:
+			s.eof = true
+			s.err = fmt.Errorf("读取 SQL 输入失败: %w", err)
+			return false
+		}
+	}
+
+	if len(s.pending) == 0 {
+		return false
+	}
+	s.current = s.pending[0]
+	s.pending = s.pending[1:]
+	return true
+}
+
+// OnComment 设置单行注释（-- 和 #）的回调
+// 回调在读取到注释所在行时调用，早于该行之后开始的语句被 Scan 返回，
+// 可用于识别 mysqldump 中 "-- Table structure for table" 等分段标记。
+func (s *StatementScanner) OnComment(fn func(line int, text string)) {
+	s.onComment = fn
+}
+
+// Statement 返回最近一次 Scan 读取的语句
+func (s *StatementScanner) Statement() Statement {
+	return s.current
+}
+
+// Err 返回读取过程中发生的错误，正常结束时返回 nil
+func (s *StatementScanner) Err() error {
+	return s.err
+}
+
+// scanLine 处理一行输入（包含行尾换行符）
+func (s *StatementScanner) scanLine(line string) {
+	col := 1
+	for i := 0; i < len(line); {
+		rest := line[i:]
+
+		if s.lex.idle() {
+			// DELIMITER 命令只在语句开头、行首识别
+			if !s.started && strings.TrimSpace(line[:i]) == "" {
+				if m := delimiterCommandRe.FindStringSubmatch(rest); m != nil {
+					s.delimiter = m[1]
+					return
+				}
+			}
+			if strings.HasPrefix(rest, s.delimiter) {
+				s.finish()
+				col += runeCount(s.delimiter)
+				i += len(s.delimiter)
+				continue
+			}
+		}
+
+		n, kind := s.lex.next(rest)
+		if kind == tokenComment && !s.lex.inBlock && rest[0] != '*' && s.onComment != nil {
+			s.onComment(s.line, rest[:n])
+		}
+		if !s.started && kind != tokenSpace && kind != tokenComment {
+			s.started = true
+			s.startLine, s.startCol = s.line, col
+		}
+		if s.started {
+			s.text.WriteString(rest[:n])
+		}
+		col += runeCount(rest[:n])
+		i += n
+	}
+}
+
+// finish 结束当前语句
+func (s *StatementScanner) finish() {
+	if s.started {
+		if text := strings.TrimSpace(s.text.String()); text != "" {
+			s.pending = append(s.pending, Statement{Text: text, Line: s.startLine, Column: s.startCol})
+		}
+	}
+	s.text.Reset()
+	s.started = false
+}
+
+// tokenKind 词法记号的类型
+type tokenKind int
+
+const (
+	tokenCode    tokenKind = iota // 普通代码
+	tokenSpace                    // 空白
+	tokenQuoted                   // 字符串或带引号的标识符（含引号）
+	tokenComment                  // 注释
+	tokenHint                     // /*! ... */ 版本注释或 /*+ ... */ 优化器提示，视为语句内容
+)
+
+// lexer 识别字符串和注释的边界
+// 状态在多次调用之间保留，因此字符串和 /* */ 注释可以跨行（跨读取块）。
+type lexer struct {
+	quote     byte      // 当前所在字符串的引号，0 表示不在字符串中
+	escaped   bool      // 上一个字符是字符串中的反斜杠
+	inBlock   bool      // 是否在 /* */ 中
+	blockKind tokenKind // 当前 /* */ 的类型：tokenComment 或 tokenHint
+}
+
+// idle 判断当前是否既不在字符串中也不在 /* */ 中
+func (l *lexer) idle() bool {
+	return l.quote == 0 && !l.inBlock
+}
+
+// next 返回从 rest 开始的下一个记号的字节长度和类型
+// 单引号和双引号支持反斜杠转义，三种引号都支持连续两个引号表示引号本身；
+// -- 和 # 注释到行尾为止（不含换行符）。
+func (l *lexer) next(rest string) (int, tokenKind) {
+	c := rest[0]
+	switch {
+	case l.quote != 0:
+		switch {
+		case l.escaped:
+			l.escaped = false
+		case c == '\\' && l.quote != '`':
+			l.escaped = true
+		case c == l.quote:
+			if len(rest) > 1 && rest[1] == l.quote {
+				return 2, tokenQuoted
+			}
+			l.quote = 0
+		}
+		return 1, tokenQuoted
+
+	case l.inBlock:
+		if strings.HasPrefix(rest, "*/") {
+			l.inBlock = false
+			return 2, l.blockKind
+		}
+		return 1, l.blockKind
+
+	case c == '\'' || c == '"' || c == '`':
+		l.quote = c
+		return 1, tokenQuoted
+
+	case c == '#' || isDashComment(rest):
+		if i := strings.IndexByte(rest, '\n'); i >= 0 {
+			return i, tokenComment
+		}
+		return len(rest), tokenComment
+
+	case strings.HasPrefix(rest, "/*"):
+		l.inBlock = true
+		l.blockKind = tokenComment
+		if strings.HasPrefix(rest, "/*!") || strings.HasPrefix(rest, "/*+") {
+			l.blockKind = tokenHint
+		}
+		return 2, l.blockKind
+
+	case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		return 1, tokenSpace
+	}
+	return 1, tokenCode
+}
+
+// runeCount 统计字符数，只在 UTF-8 字符的首字节计数
+func runeCount(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i]&0xC0 != 0x80 {
+			n++
+		}
+	}
+	return n
+}
+
+// isDashComment 判断是否为 -- 注释，MySQL 要求 -- 后跟空白或位于文本末尾
+func isDashComment(rest string) bool {
+	if !strings.HasPrefix(rest, "--") {
+		return false
+	}
+	if len(rest) == 2 {
+		return true
+	}
+	switch rest[2] {
+	case ' ', '\t', '\n', '\r':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package sqlparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSplitStatements 测试语句切分
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected []Statement
+	}{
+		{
+			name: "多条语句",
+			sql:  "SELECT 1;\nSELECT 2;",
+			expected: []Statement{
+				{Text: "SELECT 1", Line: 1, Column: 1},
+				{Text: "SELECT 2", Line: 2, Column: 1},
+			},
+		},
+		{
+			name: "字符串和标识符中的分号",
+			sql:  "INSERT INTO t VALUES ('a;b', \"c\\\";d\", 'it''s;');  SELECT `x;y` FROM t",
+			expected: []Statement{
+				{Text: "INSERT INTO t VALUES ('a;b', \"c\\\";d\", 'it''s;')", Line: 1, Column: 1},
+				{Text: "SELECT `x;y` FROM t", Line: 1, Column: 51},
+			},
+		},
+		{
+			name: "注释中的分号",
+			sql:  "-- a; b\n# c; d\n/* e; f */ SELECT 1; --x\n",
+			expected: []Statement{
+				{Text: "SELECT 1", Line: 3, Column: 12},
+				{Text: "--x", Line: 3, Column: 22},
+			},
+		},
+		{
+			name: "版本注释视为语句内容",
+			sql:  "/*!40101 SET NAMES utf8 */;\nSELECT 1",
+			expected: []Statement{
+				{Text: "/*!40101 SET NAMES utf8 */", Line: 1, Column: 1},
+				{Text: "SELECT 1", Line: 2, Column: 1},
+			},
+		},
+		{
+			name: "DELIMITER 命令",
+			sql:  "DELIMITER $$\nCREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END$$\nDELIMITER ;\nSELECT 3;",
+			expected: []Statement{
+				{Text: "CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END", Line: 2, Column: 1},
+				{Text: "SELECT 3", Line: 4, Column: 1},
+			},
+		},
+		{
+			name: "列号按字符计算",
+			sql:  "SELECT '中文'; SELECT 2",
+			expected: []Statement{
+				{Text: "SELECT '中文'", Line: 1, Column: 1},
+				{Text: "SELECT 2", Line: 1, Column: 14},
+			},
+		},
+		{
+			name:     "只有注释",
+			sql:      "-- 注释\n/* 注释 */",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SplitStatements(tt.sql))
+		})
+	}
+}
+
+// TestStatementScanner 测试从 io.Reader 逐条读取语句
+func TestStatementScanner(t *testing.T) {
+	// 超过 bufio.Scanner 默认 64KB 限制的单行批量 INSERT
+	values := strings.Repeat("(1,'a;b'),", 10000) + "(2,'c')"
+	input := "/*!40101 SET NAMES utf8mb4 */;\n" +
+		"INSERT INTO t VALUES " + values + ";\n" +
+		"INSERT INTO t VALUES ('多行\n字符串;');\n" +
+		"DELIMITER ;;\n" +
+		"CREATE TRIGGER tr BEFORE INSERT ON t FOR EACH ROW BEGIN SET NEW.a = 1; END;;\n" +
+		"DELIMITER ;\n" +
+		"SELECT 1"
+
+	s := NewStatementScanner(iotest.OneByteReader(strings.NewReader(input)))
+	var stmts []Statement
+	for s.Scan() {
+		stmts = append(stmts, s.Statement())
+	}
+	require.NoError(t, s.Err())
+
+	require.Len(t, stmts, 5)
+	assert.Equal(t, Statement{Text: "/*!40101 SET NAMES utf8mb4 */", Line: 1, Column: 1}, stmts[0])
+	assert.Equal(t, "INSERT INTO t VALUES "+values, stmts[1].Text)
+	assert.Equal(t, Statement{Text: "INSERT INTO t VALUES ('多行\n字符串;')", Line: 3, Column: 1}, stmts[2])
+	assert.Equal(t, Statement{Text: "CREATE TRIGGER tr BEFORE INSERT ON t FOR EACH ROW BEGIN SET NEW.a = 1; END", Line: 6, Column: 1}, stmts[3])
+	assert.Equal(t, Statement{Text: "SELECT 1", Line: 8, Column: 1}, stmts[4])
+}
+
+// TestStatementScanner_ReadError 测试读取错误
+func TestStatementScanner_ReadError(t *testing.T) {
+	s := NewStatementScanner(iotest.ErrReader(errors.New("boom")))
+	assert.False(t, s.Scan())
+	assert.ErrorContains(t, s.Err(), "boom")
+}
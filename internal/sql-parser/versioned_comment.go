@@ -0,0 +1,85 @@
+package sqlparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// versionDigits 版本注释中版本号的位数，格式为 Mmmrr（如 40101 表示 4.1.1），
+// 主版本号为两位数时（如 MariaDB 10.x）为 6 位
+const versionDigits = 5
+
+// ExpandVersionedComments 按服务器版本展开或去除 MySQL 版本注释
+// /*! ... */ 中的内容视为语句的一部分；/*!NNNNN ... */ 只在版本号不大于 version 时展开，
+// 否则与普通注释一样去除。字符串和带引号的标识符中的内容保持不变。
+// 参数:
+//   - sql: SQL 文本
+//   - version: 服务器版本，格式与版本注释相同（如 80035 表示 8.0.35）
+//
+// 返回:
+//   - string: 展开后的 SQL 文本，首尾空白已去除
+//
+// 注意:
+//   - 注释内的换行保持不变，行号不受影响
+//   - 未闭合的版本注释按原文保留
+func ExpandVersionedComments(sql string, version int) string {
+	if !strings.Contains(sql, "/*!") {
+		return strings.TrimSpace(sql)
+	}
+
+	var (
+		sb  strings.Builder
+		lex lexer
+	)
+	for i := 0; i < len(sql); {
+		rest := sql[i:]
+		if lex.idle() && strings.HasPrefix(rest, "/*!") {
+			end := strings.Index(rest, "*/")
+			if end < 0 {
+				sb.WriteString(rest)
+				break
+			}
+			body, minVersion := versionedBody(rest[3:end])
+			if minVersion > version {
+				// 不生效的注释只保留换行，保证行号不变
+				body = strings.Repeat("\n", strings.Count(body, "\n"))
+			}
+			// 注释与前后内容之间保留一个空白，避免与相邻的单词连在一起
+			if i > 0 && !isSpaceByte(sql[i-1]) {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(strings.Trim(body, " \t"))
+			i += end + 2
+			if i < len(sql) && !isSpaceByte(sql[i]) {
+				sb.WriteByte(' ')
+			}
+			continue
+		}
+
+		n, _ := lex.next(rest)
+		sb.WriteString(rest[:n])
+		i += n
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// versionedBody 拆分版本注释的版本号与内容，没有版本号时版本为 0
+func versionedBody(comment string) (string, int) {
+	if len(comment) < versionDigits {
+		return comment, 0
+	}
+	digits := versionDigits
+	if len(comment) > digits && comment[digits] >= '0' && comment[digits] <= '9' {
+		digits++
+	}
+	v, err := strconv.Atoi(comment[:digits])
+	if err != nil || strings.ContainsAny(comment[:digits], "+-") {
+		return comment, 0
+	}
+	return comment[digits:], v
+}
+
+// isSpaceByte 判断是否为空白字符
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,78 @@
+package sqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExpandVersionedComments 测试版本注释的展开与去除
+func TestExpandVersionedComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		version  int
+		expected string
+	}{
+		{
+			name:     "版本号不大于服务器版本时展开",
+			sql:      "/*!40101 SET NAMES utf8 */",
+			version:  80000,
+			expected: "SET NAMES utf8",
+		},
+		{
+			name:     "版本号大于服务器版本时去除",
+			sql:      "/*!80016 SET @@SESSION.default_table_encryption=OFF */",
+			version:  50744,
+			expected: "",
+		},
+		{
+			name:     "6 位版本号",
+			sql:      "/*!100101 SET STATEMENT max_statement_time=1 FOR*/ SELECT 1",
+			version:  80000,
+			expected: "SELECT 1",
+		},
+		{
+			name:     "没有版本号时总是展开",
+			sql:      "SELECT /*! STRAIGHT_JOIN */ a FROM t",
+			version:  50100,
+			expected: "SELECT STRAIGHT_JOIN a FROM t",
+		},
+		{
+			name:     "相邻的片段拼接为完整语句",
+			sql:      "/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`%`*/ /*!50003 TRIGGER tr BEFORE INSERT ON t FOR EACH ROW SET NEW.a = 1 */",
+			version:  80000,
+			expected: "CREATE DEFINER=`root`@`%` TRIGGER tr BEFORE INSERT ON t FOR EACH ROW SET NEW.a = 1",
+		},
+		{
+			name:     "与相邻单词之间保留空白",
+			sql:      "CREATE DATABASE/*!32312 IF NOT EXISTS*/`shop`",
+			version:  80000,
+			expected: "CREATE DATABASE IF NOT EXISTS `shop`",
+		},
+		{
+			name:     "去除的注释保留换行",
+			sql:      "SELECT 1 /*!90000 a,\nb */\nFROM t",
+			version:  80000,
+			expected: "SELECT 1 \n\nFROM t",
+		},
+		{
+			name:     "字符串中的内容不变",
+			sql:      "INSERT INTO t VALUES ('/*!40101 x */')",
+			version:  80000,
+			expected: "INSERT INTO t VALUES ('/*!40101 x */')",
+		},
+		{
+			name:     "优化器提示与普通注释不变",
+			sql:      "SELECT /*+ MAX_EXECUTION_TIME(1) */ 1 /* c */",
+			version:  80000,
+			expected: "SELECT /*+ MAX_EXECUTION_TIME(1) */ 1 /* c */",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExpandVersionedComments(tt.sql, tt.version))
+		})
+	}
+}
@@ -0,0 +1,88 @@
+# The proper term is pseudo_replica_mode, but we use this compatibility alias
+# to make the statement usable on server versions 8.0.24 and older.
+/*!50530 SET @@SESSION.PSEUDO_SLAVE_MODE=1*/;
+/*!50003 SET @OLD_COMPLETION_TYPE=@@COMPLETION_TYPE,COMPLETION_TYPE=0*/;
+DELIMITER /*!*/;
+# at 4
+#240115 10:30:45 server id 1  end_log_pos 126 CRC32 0x1b2c3d4e 	Start: binlog v 4, server v 8.0.35 created 240115 10:30:45 at startup
+ROLLBACK/*!*/;
+# at 126
+#240115 10:30:45 server id 1  end_log_pos 157 CRC32 0x2c3d4e5f 	Previous-GTIDs
+# [empty]
+# at 157
+#240115 10:31:00 server id 1  end_log_pos 234 CRC32 0x3d4e5f60 	GTID	last_committed=0	sequence_number=1	rbr_only=no
+SET @@SESSION.GTID_NEXT= '3e11fa47-71ca-11e1-9e33-c80aa9429562:1'/*!*/;
+# at 234
+#240115 10:31:00 server id 1  end_log_pos 420 CRC32 0x4e5f6071 	Query	thread_id=8	exec_time=0	error_code=0	Xid = 12
+use `shop`/*!*/;
+SET TIMESTAMP=1705314660/*!*/;
+SET @@session.pseudo_thread_id=8/*!*/;
+/*!\C utf8mb4 *//*!*/;
+CREATE TABLE users (
+  id INT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  name VARCHAR(20)
+) ENGINE=InnoDB
+/*!*/;
+# at 420
+#240115 10:32:00 server id 1  end_log_pos 499 CRC32 0x5f607182 	GTID	last_committed=1	sequence_number=2	rbr_only=yes
+SET @@SESSION.GTID_NEXT= '3e11fa47-71ca-11e1-9e33-c80aa9429562:2'/*!*/;
+# at 499
+#240115 10:32:00 server id 1  end_log_pos 574 CRC32 0x60718293 	Query	thread_id=8	exec_time=0	error_code=0
+SET TIMESTAMP=1705314720/*!*/;
+BEGIN
+/*!*/;
+# at 574
+#240115 10:32:00 server id 1  end_log_pos 630 CRC32 0x718293a4 	Table_map: `shop`.`users` mapped to number 90
+# at 630
+#240115 10:32:00 server id 1  end_log_pos 690 CRC32 0x8293a4b5 	Write_rows: table id 90 flags: STMT_END_F
+
+BINLOG '
+ZJmlZRMBAAAAOgAAAHYCAAAAAFoAAAAAAAEABHNob3AABXVzZXJzAAIDDwJQAAIBAQACAS2B
+ZJmlZR4BAAAAPAAAALICAAAAAFoAAAAAAAEAAgAC/wABAAAABWFsaWNlAAIAAAADYm9i
+'/*!*/;
+### INSERT INTO `shop`.`users`
+### SET
+###   @1=1
+###   @2='alice'
+### INSERT INTO `shop`.`users`
+### SET
+###   @1=2
+###   @2='it\'s bob'
+# at 690
+#240115 10:32:00 server id 1  end_log_pos 721 CRC32 0x93a4b5c6 	Xid = 20
+COMMIT/*!*/;
+# at 721
+#240115 10:33:00 server id 1  end_log_pos 800 CRC32 0xa4b5c6d7 	GTID	last_committed=2	sequence_number=3	rbr_only=yes
+SET @@SESSION.GTID_NEXT= '3e11fa47-71ca-11e1-9e33-c80aa9429562:3'/*!*/;
+# at 800
+#240115 10:33:00 server id 1  end_log_pos 875 CRC32 0xb5c6d7e8 	Query	thread_id=9	exec_time=0	error_code=0
+SET TIMESTAMP=1705314780/*!*/;
+BEGIN
+/*!*/;
+# at 875
+#240115 10:33:00 server id 1  end_log_pos 931 CRC32 0xc6d7e8f9 	Table_map: `shop`.`users` mapped to number 90
+# Columns(`id` INT UNSIGNED NOT NULL,
+#         `name` VARCHAR(20) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci)
+# at 931
+#240115 10:33:00 server id 1  end_log_pos 1001 CRC32 0xd7e8f90a 	Update_rows: table id 90 flags: STMT_END_F
+### UPDATE `shop`.`users`
+### WHERE
+###   @1=1 /* INT meta=0 nullable=0 is_null=0 */
+###   @2='alice' /* VARSTRING(80) meta=80 nullable=1 is_null=0 */
+### SET
+###   @1=1 /* INT meta=0 nullable=0 is_null=0 */
+###   @2=NULL /* VARSTRING(80) meta=80 nullable=1 is_null=1 */
+# at 1001
+#240115 10:33:00 server id 1  end_log_pos 1061 CRC32 0xe8f90a1b 	Delete_rows: table id 90 flags: STMT_END_F
+### DELETE FROM `shop`.`users`
+### WHERE
+###   @1=2 /* INT meta=0 nullable=0 is_null=0 */
+###   @2=NULL /* VARSTRING(80) meta=80 nullable=1 is_null=1 */
+# at 1061
+#240115 10:33:00 server id 1  end_log_pos 1092 CRC32 0xf90a1b2c 	Xid = 31
+COMMIT/*!*/;
+SET @@SESSION.GTID_NEXT= 'AUTOMATIC' /* added by mysqlbinlog */ /*!*/;
+DELIMITER ;
+# End of log file
+/*!50003 SET COMPLETION_TYPE=@OLD_COMPLETION_TYPE*/;
+/*!50530 SET @@SESSION.PSEUDO_SLAVE_MODE=0*/;